@@ -1,21 +1,28 @@
 package api
 
 import (
-	"os"
+	"log/slog"
 
+	"github.com/sergiodii/bbb/cmd/api/middleware"
+	"github.com/sergiodii/bbb/cmd/api/route/openapi"
 	"github.com/sergiodii/bbb/cmd/api/route/vote"
-	"github.com/sergiodii/bbb/internal/usecase/vote/aggregator"
-	"github.com/sergiodii/bbb/pkg/redis"
+	"github.com/sergiodii/bbb/pkg/metrics"
 
 	"github.com/gin-gonic/gin"
 )
 
-func commandApiRegister(g *gin.Engine, rootPath string) {
-	commandAggregator := aggregator.NewCommandAggregator(
-		redis.NewRedisRoundRepository(os.Getenv("REDIS_ADDR")),
-		// localsql.NewLocalSqlRoundRepository(), // This is a example, in real case we could have a different repository for command
-		// can be added other repositories if needed for example: postgres, mongodb, etc
-	)
+// commandApiRegister registers the routes that register the votes, built from the pipeline of the container,
+// and adds their operations to the OpenAPI document.
+// It returns the names of the repositories used by the routes, to be checked by the readiness probe.
+func commandApiRegister(g *gin.Engine, doc *openapi.Document, rootPath string, logger *slog.Logger, deps *container) ([]string, error) {
+	commandAggregator, used, err := deps.CommandAggregator()
+	if err != nil {
+		return nil, err
+	}
 
-	vote.NewCommandRoute(commandAggregator, g.Group(rootPath))
+	admin := middleware.NewAdminMiddlewareV1(logger, deps.adminToken)
+	vote.NewCommandRoute(commandAggregator, g.Group(rootPath, metrics.VoteMiddleware(deps.metricsRounds)), logger, deps.repos.Idempotency(), deps.idempotencyTTL, admin)
+	doc.Add(rootPath, vote.CommandOperations()...)
+
+	return used, nil
 }
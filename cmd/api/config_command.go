@@ -0,0 +1,44 @@
+package api
+
+import (
+	"github.com/sergiodii/bbb/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+func ConfigCommand() *cobra.Command {
+	c := cobra.Command{
+		Use:   "config",
+		Short: "Comandos da configuração da aplicação",
+	}
+
+	c.AddCommand(configPrintCommand())
+	return &c
+}
+
+// configPrintCommand prints the effective configuration, read with the same flags, variables and file of the APIs
+func configPrintCommand() *cobra.Command {
+	c := cobra.Command{
+		Use:   "print",
+		Short: "Exibe a configuração efetiva (flag > variável de ambiente > arquivo), com os segredos ocultos",
+		Args:  cobra.NoArgs,
+	}
+
+	defaults := config.Default()
+	config.BindFlags(c.Flags(), defaults)
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(defaults, cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		out, err := cfg.YAML()
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(out)
+		return err
+	}
+
+	return &c
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigPrintCommand(t *testing.T) {
+	t.Run("Should print the effective configuration with the secrets redacted", func(t *testing.T) {
+		// Arrange
+		t.Setenv("PORT", "9000")
+		t.Setenv("REDIS_PASSWORD", "s3cr3t")
+		var out bytes.Buffer
+		cmd := ConfigCommand()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"print", "--redis-pool-size", "20"})
+
+		// Act
+		err := cmd.Execute()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), `port: "9000"`)
+		assert.Contains(t, out.String(), "pool_size: 20")
+		assert.NotContains(t, out.String(), "s3cr3t")
+	})
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/sergiodii/bbb/cmd/api/route/health"
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	"github.com/sergiodii/bbb/internal/usecase/vote/aggregator"
+	"github.com/sergiodii/bbb/pkg/metrics"
+	"github.com/sergiodii/bbb/pkg/tracing"
+)
+
+// container builds the dependencies of the APIs once and shares them between the routes.
+// The repositories are created from the pipeline configuration, and the ones holding connections
+// are closed by Close, in the reverse order they were created.
+type container struct {
+	logger   *slog.Logger
+	pipeline *config.Pipeline
+	repos    config.Repositories
+
+	// queryCacheTTL is the time the results of the queries are cached, zero disables the cache
+	queryCacheTTL time.Duration
+
+	// idempotencyTTL is the time the idempotency keys of the votes are kept
+	idempotencyTTL time.Duration
+
+	// metricsRounds are the rounds whose votes are counted apart in the metrics
+	metricsRounds []string
+
+	// adminToken is the token of the administrative operations, they are disabled when it is empty
+	adminToken string
+
+	commandAggregator aggregator.CommandAggregator
+	commandRepos      []string
+	queryAggregator   aggregator.QueryAggregator
+	queryRepos        []string
+
+	hooks []func() error
+}
+
+// defaultConfig returns the default configuration of an API listening on the port
+func defaultConfig(port string) config.Config {
+	cfg := config.Default()
+	cfg.Port = port
+	return cfg
+}
+
+// newContainer loads the pipeline configuration and creates its repositories, with metrics and tracing
+func newContainer(cfg config.Config, l *slog.Logger) (*container, error) {
+	// every pipe created from now on reports the latency, the errors and the spans of its tasks,
+	// whichever API serves them
+	pipe.SetDefaultObserver(pipe.NewMultiObserver(metrics.NewPipeObserver(), tracing.NewPipeObserver()))
+
+	pipeline, err := config.LoadPipeline(cfg.Pipeline, l)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := pipeline.NewRepositories(cfg.Redis, l, instrumentRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &container{
+		logger:         l,
+		pipeline:       pipeline,
+		repos:          repos,
+		queryCacheTTL:  cfg.Query.CacheTTL,
+		metricsRounds:  cfg.Metrics.Rounds,
+		idempotencyTTL: cfg.Idempotency.TTL,
+		adminToken:     cfg.AdminToken,
+	}
+
+	for _, repo := range c.repos.List() {
+		c.closeOnShutdown(repository.Unwrap(repo))
+	}
+	c.closeOnShutdown(c.repos.DeadLetters())
+	c.closeOnShutdown(c.repos.Idempotency())
+
+	return c, nil
+}
+
+// closeOnShutdown registers the Close of the dependency when it holds resources
+func (c *container) closeOnShutdown(dependency any) {
+	if closer, ok := dependency.(io.Closer); ok {
+		c.OnClose(closer.Close)
+	}
+}
+
+// OnClose registers a hook called by Close
+func (c *container) OnClose(hook func() error) {
+	c.hooks = append(c.hooks, hook)
+}
+
+// CommandAggregator returns the aggregator that registers the votes and the names of the repositories it uses.
+// The aggregator is created on the first call.
+func (c *container) CommandAggregator() (aggregator.CommandAggregator, []string, error) {
+	if c.commandAggregator == nil {
+		a, repos, err := c.pipeline.NewCommandAggregator(c.repos, aggregator.WithLogger(c.logger))
+		if err != nil {
+			return nil, nil, err
+		}
+		c.commandAggregator, c.commandRepos = a, repos
+	}
+	return c.commandAggregator, c.commandRepos, nil
+}
+
+// QueryAggregator returns the aggregator that queries the votes and the names of the repositories it uses.
+// The aggregator is created on the first call.
+func (c *container) QueryAggregator() (aggregator.QueryAggregator, []string, error) {
+	if c.queryAggregator == nil {
+		a, repos, err := c.pipeline.NewQueryAggregator(c.repos, aggregator.WithLogger(c.logger), aggregator.WithQueryCache(c.queryCacheTTL))
+		if err != nil {
+			return nil, nil, err
+		}
+		c.queryAggregator, c.queryRepos = a, repos
+	}
+	return c.queryAggregator, c.queryRepos, nil
+}
+
+// CheckRepositories registers the repositories with the names in the readiness checks
+func (c *container) CheckRepositories(checker *health.Checker, names []string) {
+	for _, name := range names {
+		checker.Register(name, c.repos.Get(name))
+	}
+}
+
+// Wait blocks until the background tasks of the aggregators created so far are done or the context is done
+func (c *container) Wait(ctx context.Context) error {
+	if c.commandAggregator == nil {
+		return nil
+	}
+	return c.commandAggregator.Wait(ctx)
+}
+
+// Close calls the hooks in the reverse order they were registered.
+// Every hook is called, the errors are logged and returned joined.
+func (c *container) Close() error {
+	var errs []error
+	for _, hook := range slices.Backward(c.hooks) {
+		if err := hook(); err != nil {
+			c.logger.Error("failed to close a dependency", "error", err)
+			errs = append(errs, err)
+		}
+	}
+	c.hooks = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryPipeline writes a pipeline with a memory repository and returns its path
+func memoryPipeline(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	os.WriteFile(path, []byte("repositories:\n  - name: cache\n    type: memory\n"), 0o600)
+	return path
+}
+
+// newTestContainer creates a container from a pipeline with a memory repository
+func newTestContainer(t *testing.T) *container {
+	cfg := config.Default()
+	cfg.Pipeline = memoryPipeline(t)
+
+	deps, err := newContainer(cfg, logger.Discard())
+	assert.NoError(t, err)
+	return deps
+}
+
+func TestContainer(t *testing.T) {
+	t.Run("Should share the repositories between the command and the query aggregators", func(t *testing.T) {
+		// Arrange
+		deps := newTestContainer(t)
+		defer deps.Close()
+
+		command, commandRepos, commandErr := deps.CommandAggregator()
+		query, queryRepos, queryErr := deps.QueryAggregator()
+
+		// Act
+		err := command.GetAggregatedUseCase().CreateVote(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "alice"})
+		total, _ := query.GetAggregatedUseCase().GetTotalVotes(context.Background(), "round1")
+		sameCommand, _, _ := deps.CommandAggregator()
+
+		// Assert
+		assert.NoError(t, commandErr)
+		assert.NoError(t, queryErr)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, commandRepos, queryRepos)
+		assert.Same(t, command, sameCommand)
+	})
+
+	t.Run("Should call every close hook in the reverse order", func(t *testing.T) {
+		// Arrange
+		deps := newTestContainer(t)
+		var calls []string
+		deps.OnClose(func() error {
+			calls = append(calls, "first")
+			return nil
+		})
+		deps.OnClose(func() error {
+			calls = append(calls, "second")
+			return assert.AnError
+		})
+
+		// Act
+		err := deps.Close()
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, []string{"second", "first"}, calls)
+		assert.NoError(t, deps.Close())
+	})
+
+	t.Run("Should return the validation errors of the pipeline", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "pipeline.yaml")
+		os.WriteFile(path, []byte("repositories:\n  - name: cache\n    type: mongo\n"), 0o600)
+
+		cfg := config.Default()
+		cfg.Pipeline = path
+
+		// Act
+		_, err := newContainer(cfg, logger.Discard())
+
+		// Assert
+		assert.ErrorIs(t, err, config.ErrInvalidPipeline)
+	})
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sergiodii/bbb/cmd/api/middleware"
+	"github.com/sergiodii/bbb/cmd/api/route/health"
+	"github.com/sergiodii/bbb/cmd/api/route/openapi"
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	"github.com/sergiodii/bbb/pkg/logger"
+	"github.com/sergiodii/bbb/pkg/metrics"
+	"github.com/sergiodii/bbb/pkg/problem"
+	"github.com/sergiodii/bbb/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRouter creates the gin engine with the routes shared by every API: the probes, the metrics and
+// the OpenAPI specification, whose document receives the operations of the routes registered later.
+// These routes are registered before any other middleware, so they are never blocked or rate limited.
+// The client IP and the voter ID are only read from the headers of the trusted proxies of the configuration.
+func newRouter(cfg config.Config, checker *health.Checker, l *slog.Logger) (*gin.Engine, *openapi.Document, error) {
+	trusted, err := middleware.NewTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// gin.Default is not used because its logger writes synchronously to the console on every request
+	r := gin.New()
+	r.RemoteIPHeaders = slices.Clone(middleware.RemoteIPHeaders)
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, nil, err
+	}
+	r.Use(gin.Recovery(), middleware.NewTrustedProxiesMiddleware(trusted), logger.GinMiddleware(l), metrics.GinMiddleware(), tracing.GinMiddleware())
+	r.NoRoute(func(c *gin.Context) {
+		problem.Write(c, problem.New(http.StatusNotFound, problem.CodeNotFound, "route not found"))
+	})
+
+	health.NewHealthRoute(checker, r.Group(""))
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	doc := openapi.NewDocument(apiTitle, apiVersion, apiDescription)
+	openapi.NewRoute(doc, r.Group(""))
+
+	return r, doc, nil
+}
+
+// The information of the API in the OpenAPI specification
+const (
+	apiTitle       = "API de Votação BBB"
+	apiVersion     = "1.0.0"
+	apiDescription = "Registro de votos (command) e consultas dos resultados (query) das rodadas do BBB. " +
+		"Na API unificada as rotas ficam em /command e /query, nas APIs separadas ficam na raiz."
+)
+
+// Engine is the unified API of the api command: the query routes under /query and the command routes
+// under /command, with the probes, the metrics and the middlewares
+type Engine struct {
+	router  *gin.Engine
+	doc     *openapi.Document
+	checker *health.Checker
+	deps    *container
+}
+
+// NewEngine builds the unified API from the configuration, without listening on any port, so it can
+// also be served by a test (see pkg/apitest). Close must be called to release the repositories.
+func NewEngine(cfg config.Config, logger *slog.Logger) (*Engine, error) {
+	deps, err := newContainer(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := health.NewChecker(logger)
+	r, doc, err := newRouter(cfg, checker, logger)
+	if err != nil {
+		deps.Close()
+		return nil, err
+	}
+
+	// This middleware simulate the blocking of IP ranges
+	// The blocked_ip_ranges of the configuration is a list of IP prefixes to block
+	// Example: export BLOCKED_IP_RANGES="192.168.1.,10.0.0."
+	// Any request from an IP starting with these prefixes will be blocked with a 403 response
+	r.Use(middleware.NewBlockingIPRangeMiddlewareV1(logger, cfg.BlockedIPRanges))
+
+	// This middleware simulate a rate limiting of 60 requests per minute per IP
+	// In a real implementation, you would use a more robust solution with a datastore or in-memory structure
+	// to track requests per IP and enforce limits.
+	// Here, for simplicity, we just allow all requests.
+	r.Use(middleware.RateLimitMiddlewareV1(logger))
+
+	queryRepos, err := queryApiRegister(r, doc, "/query", logger, deps)
+	if err != nil {
+		deps.Close()
+		return nil, err
+	}
+	commandRepos, err := commandApiRegister(r, doc, "/command", logger, deps)
+	if err != nil {
+		deps.Close()
+		return nil, err
+	}
+
+	deps.CheckRepositories(checker, queryRepos)
+	deps.CheckRepositories(checker, commandRepos)
+	return &Engine{router: r, doc: doc, checker: checker, deps: deps}, nil
+}
+
+// Handler returns the HTTP handler of the API
+func (e *Engine) Handler() http.Handler {
+	return e.router
+}
+
+// Wait blocks until the votes being registered in the background are done or the context is done
+func (e *Engine) Wait(ctx context.Context) error {
+	return e.deps.Wait(ctx)
+}
+
+// Close releases the repositories
+func (e *Engine) Close() error {
+	return e.deps.Close()
+}
+
+// instrumentRepository adds metrics and tracing to a repository
+func instrumentRepository(name string, repo repository.RoundRepository) repository.RoundRepository {
+	return tracing.TraceRepository(name, metrics.InstrumentRepository(name, repo))
+}
+
+// setupLogger creates the application logger and sets it as the slog default,
+// so the components without an injected logger (e.g. the pipes) use it too.
+// The returned function flushes the pending records.
+func setupLogger(cfg config.LogConfig) (*slog.Logger, func()) {
+	l, closer := logger.NewDefault(cfg.Level, metrics.LogDropped)
+	slog.SetDefault(l)
+
+	return l, func() { closer.Close() }
+}
+
+// setupTracing configures the trace exporter and returns the function that flushes the pending spans
+func setupTracing(serviceName string, cfg config.TracingConfig) (func(), error) {
+	shutdown, err := tracing.Setup(context.Background(), serviceName, cfg.Exporter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing configuration: %w", err)
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sergiodii/bbb/cmd/api/middleware"
+	rpcvote "github.com/sergiodii/bbb/cmd/api/rpc/vote"
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/pkg/metrics"
+	votev1 "github.com/sergiodii/bbb/pkg/pb/vote/v1"
+	"github.com/sergiodii/bbb/pkg/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// grpcAPI is the gRPC server of the grpc-api command and the services registered in it
+type grpcAPI struct {
+	server *grpc.Server
+	votes  *rpcvote.Server
+	health *health.Server
+
+	// metrics serves the /metrics on the metrics port, nil when it is not configured
+	metrics *http.Server
+}
+
+// newGRPCAPI creates the gRPC server with the VoteService, built from the aggregators of the container.
+// The calls are measured and traced, and go through the same trusted proxies, blocking of IP ranges and rate limiting
+// of the REST API; CreateRound requires the admin token like the creation of the rounds of the REST API. The standard
+// health service and the reflection (for grpcurl) are registered too, and the metrics are served on the metrics port.
+func newGRPCAPI(cfg config.Config, logger *slog.Logger, deps *container) (*grpcAPI, error) {
+	commandAggregator, _, err := deps.CommandAggregator()
+	if err != nil {
+		return nil, err
+	}
+	queryAggregator, _, err := deps.QueryAggregator()
+	if err != nil {
+		return nil, err
+	}
+
+	trusted, err := middleware.NewTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	// the calls are measured and traced first, so the rejected ones are too;
+	// then the client of the call is resolved, the other interceptors use its IP
+	metricsUnary, metricsStream := metrics.GRPCInterceptors()
+	tracingUnary, tracingStream := tracing.GRPCInterceptors()
+	clientUnary, clientStream := middleware.NewTrustedProxiesInterceptors(trusted)
+	blockUnary, blockStream := middleware.NewBlockingIPRangeInterceptorsV1(logger, cfg.BlockedIPRanges)
+	limitUnary, limitStream := middleware.RateLimitInterceptorsV1(logger)
+	adminUnary := middleware.NewAdminInterceptorV1(logger, cfg.AdminToken, votev1.VoteService_CreateRound_FullMethodName)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(metricsUnary, tracingUnary, clientUnary, blockUnary, limitUnary, adminUnary),
+		grpc.ChainStreamInterceptor(metricsStream, tracingStream, clientStream, blockStream, limitStream),
+	)
+
+	api := &grpcAPI{
+		server: s,
+		votes:  rpcvote.NewVoteServer(commandAggregator, queryAggregator, s, logger, middleware.GRPCRateLimitV1(logger)),
+		health: health.NewServer(),
+	}
+	healthpb.RegisterHealthServer(s, api.health)
+	reflection.Register(s)
+
+	if cfg.Metrics.Port != "" {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", metrics.Handler())
+		api.metrics = &http.Server{Addr: ":" + cfg.Metrics.Port, Handler: mux}
+	}
+
+	return api, nil
+}
+
+// serveGRPC starts the gRPC server and blocks until a SIGINT or SIGTERM is received or the context is done.
+// The shutdown follows serve: the health service is flipped to NOT_SERVING, the server stops after the
+// drain delay (the WatchTotals streams are ended, the other calls are awaited), then wait is called for
+// the background tasks. Both steps share the shutdown timeout, the server is stopped when it expires.
+func serveGRPC(ctx context.Context, api *grpcAPI, port string, logger *slog.Logger, cfg config.ShutdownConfig, wait func(context.Context) error) error {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	failed := make(chan error, 2)
+	go func() {
+		if err := api.server.Serve(lis); err != nil {
+			failed <- err
+		}
+	}()
+	if api.metrics != nil {
+		go func() {
+			if err := api.metrics.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failed <- err
+			}
+		}()
+	}
+
+	select {
+	case err := <-failed:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down, draining connections", "drain_delay", cfg.DrainDelay, "timeout", cfg.Timeout)
+	api.health.Shutdown()
+	time.Sleep(cfg.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	var errs []error
+	api.votes.Close()
+	stopped := make(chan struct{})
+	go func() {
+		api.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		api.server.Stop()
+		logger.Error("shutdown failed", "error", shutdownCtx.Err())
+		errs = append(errs, shutdownCtx.Err())
+	}
+
+	if api.metrics != nil {
+		if err := api.metrics.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := wait(shutdownCtx); err != nil {
+		logger.Error("background tasks did not finish", "error", err)
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
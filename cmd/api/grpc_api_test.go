@@ -0,0 +1,448 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rpcvote "github.com/sergiodii/bbb/cmd/api/rpc/vote"
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/pkg/logger"
+	"github.com/sergiodii/bbb/pkg/metrics"
+	votev1 "github.com/sergiodii/bbb/pkg/pb/vote/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// testAdminToken is the admin token of the tests that create rounds
+const testAdminToken = "s3cr3t"
+
+// adminContext returns the context of a call with the admin token
+func adminContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testAdminToken)
+}
+
+// newTestGRPCAPI serves the gRPC API of a memory pipeline in memory and returns a connection to it
+func newTestGRPCAPI(t *testing.T, cfg config.Config) (*grpcAPI, *grpc.ClientConn) {
+	cfg.Pipeline = memoryPipeline(t)
+	deps, err := newContainer(cfg, logger.Discard())
+	require.NoError(t, err)
+	t.Cleanup(func() { deps.Close() })
+
+	api, err := newGRPCAPI(cfg, logger.Discard(), deps)
+	require.NoError(t, err)
+
+	// a loopback listener, so the calls come from 127.0.0.1 as for a trusted proxy on the same host
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go api.server.Serve(lis)
+	t.Cleanup(api.server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return api, conn
+}
+
+func TestGRPCAPI(t *testing.T) {
+	t.Run("Should register the votes and answer the queries", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		_, conn := newTestGRPCAPI(t, config.Default())
+		client := votev1.NewVoteServiceClient(conn)
+
+		// Act
+		_, voteErr := client.CreateVote(ctx, &votev1.CreateVoteRequest{RoundId: "round1", ParticipantId: "alice"})
+
+		bulk, err := client.BulkCreateVotes(ctx)
+		require.NoError(t, err)
+		for _, participant := range []string{"bob", "", "alice"} {
+			require.NoError(t, bulk.Send(&votev1.CreateVoteRequest{RoundId: "round1", ParticipantId: participant}))
+		}
+		bulkRes, bulkErr := bulk.CloseAndRecv()
+
+		round := &votev1.RoundRequest{RoundId: "round1"}
+		total, totalErr := client.GetTotalVotes(ctx, round)
+		participants, participantsErr := client.GetTotalVotesForParticipant(ctx, round)
+		hours, hoursErr := client.GetTotalVotesForHour(ctx, round)
+		winner, winnerErr := client.GetWinner(ctx, round)
+		ranking, rankingErr := client.GetRanking(ctx, round)
+		series, seriesErr := client.GetTimeSeries(ctx, round)
+
+		// Assert
+		for _, err := range []error{voteErr, bulkErr, totalErr, participantsErr, hoursErr, winnerErr, rankingErr, seriesErr} {
+			assert.NoError(t, err)
+		}
+		assert.EqualValues(t, 2, bulkRes.GetAccepted())
+		assert.EqualValues(t, 1, bulkRes.GetFailed())
+		require.Len(t, bulkRes.GetErrors(), 1)
+		assert.EqualValues(t, 1, bulkRes.GetErrors()[0].GetIndex())
+
+		assert.EqualValues(t, 3, total.GetTotal())
+		assert.Equal(t, map[string]int64{"alice": 2, "bob": 1}, participants.GetVotes())
+		assert.Len(t, hours.GetVotes(), 1)
+		assert.Equal(t, "alice", winner.GetParticipantId())
+		assert.Len(t, ranking.GetEntries(), 2)
+		require.Len(t, series.GetPoints(), 1)
+		assert.EqualValues(t, 3, series.GetPoints()[0].GetCumulative())
+	})
+
+	t.Run("Should send the totals when they change", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, conn := newTestGRPCAPI(t, config.Default())
+		client := votev1.NewVoteServiceClient(conn)
+		_, err := client.CreateVote(ctx, &votev1.CreateVoteRequest{RoundId: "round1", ParticipantId: "alice"})
+		require.NoError(t, err)
+
+		// Act
+		watch, err := client.WatchTotals(ctx, &votev1.WatchTotalsRequest{RoundId: "round1", Interval: durationpb.New(10 * time.Millisecond)})
+		require.NoError(t, err)
+		first, firstErr := watch.Recv()
+		_, err = client.CreateVote(ctx, &votev1.CreateVoteRequest{RoundId: "round1", ParticipantId: "bob"})
+		require.NoError(t, err)
+		second, secondErr := watch.Recv()
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		assert.EqualValues(t, 1, first.GetTotal())
+		assert.EqualValues(t, 2, second.GetTotal())
+		assert.Equal(t, map[string]int64{"alice": 1, "bob": 1}, second.GetParticipants())
+	})
+
+	t.Run("Should end the watches when the server closes", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		cfg := config.Default()
+		cfg.AdminToken = testAdminToken
+		api, conn := newTestGRPCAPI(t, cfg)
+		client := votev1.NewVoteServiceClient(conn)
+		_, err := client.CreateRound(adminContext(ctx), &votev1.CreateRoundRequest{RoundId: "round1"})
+		require.NoError(t, err)
+		watch, err := client.WatchTotals(ctx, &votev1.WatchTotalsRequest{RoundId: "round1"})
+		require.NoError(t, err)
+		_, err = watch.Recv()
+		require.NoError(t, err)
+
+		// Act
+		api.votes.Close()
+		_, err = watch.Recv()
+
+		// Assert
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("Should count the unique voters", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		cfg := config.Default()
+		cfg.TrustedProxies = []string{"127.0.0.1"}
+		_, conn := newTestGRPCAPI(t, cfg)
+		client := votev1.NewVoteServiceClient(conn)
+
+		// Act
+		for _, vote := range []*votev1.CreateVoteRequest{
+			{RoundId: "round1", ParticipantId: "alice", VoterId: "v1"},
+			{RoundId: "round1", ParticipantId: "alice", VoterId: "v1"},
+			{RoundId: "round1", ParticipantId: "bob", VoterId: "v2"},
+		} {
+			_, err := client.CreateVote(ctx, vote)
+			require.NoError(t, err)
+		}
+		voters, err := client.GetUniqueVoters(ctx, &votev1.RoundRequest{RoundId: "round1"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.EqualValues(t, 2, voters.GetTotal())
+		assert.Equal(t, map[string]int64{"alice": 1, "bob": 1}, voters.GetParticipants())
+	})
+
+	t.Run("Should ignore the voter ID and the IP metadata of an untrusted client", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		_, conn := newTestGRPCAPI(t, config.Default())
+		client := votev1.NewVoteServiceClient(conn)
+
+		// Act
+		for i, voterID := range []string{"v1", "v2", "v3"} {
+			ctx := metadata.AppendToOutgoingContext(ctx, "x-forwarded-for", fmt.Sprintf("10.0.0.%d", i))
+			_, err := client.CreateVote(ctx, &votev1.CreateVoteRequest{RoundId: "round1", ParticipantId: "alice", VoterId: voterID})
+			require.NoError(t, err)
+		}
+		voters, err := client.GetUniqueVoters(ctx, &votev1.RoundRequest{RoundId: "round1"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, voters.GetTotal())
+	})
+
+	t.Run("Should tell an unknown round from a created round without votes", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		cfg := config.Default()
+		cfg.AdminToken = testAdminToken
+		_, conn := newTestGRPCAPI(t, cfg)
+		client := votev1.NewVoteServiceClient(conn)
+
+		// Act
+		_, unknownErr := client.GetTotalVotes(ctx, &votev1.RoundRequest{RoundId: "round1"})
+		_, createErr := client.CreateRound(adminContext(ctx), &votev1.CreateRoundRequest{
+			RoundId:      "round1",
+			Name:         "Paredão 1",
+			Participants: []*votev1.Participant{{Id: "alice", Name: "Alice"}},
+		})
+		total, totalErr := client.GetTotalVotes(ctx, &votev1.RoundRequest{RoundId: "round1"})
+
+		// Assert
+		assert.Equal(t, codes.NotFound, status.Code(unknownErr))
+		assert.NoError(t, createErr)
+		assert.NoError(t, totalErr)
+		assert.EqualValues(t, 0, total.GetTotal())
+	})
+
+	t.Run("Should reject the invalid requests", func(t *testing.T) {
+		// Arrange
+		cfg := config.Default()
+		cfg.AdminToken = testAdminToken
+		_, conn := newTestGRPCAPI(t, cfg)
+		client := votev1.NewVoteServiceClient(conn)
+
+		// Act
+		_, voteErr := client.CreateVote(context.Background(), &votev1.CreateVoteRequest{RoundId: "round1"})
+		_, queryErr := client.GetTotalVotes(context.Background(), &votev1.RoundRequest{})
+		_, roundErr := client.CreateRound(adminContext(context.Background()), &votev1.CreateRoundRequest{RoundId: "round1", Participants: []*votev1.Participant{{Name: "Alice"}}})
+
+		// Assert
+		assert.Equal(t, codes.InvalidArgument, status.Code(voteErr))
+		assert.Equal(t, codes.InvalidArgument, status.Code(queryErr))
+		assert.Equal(t, codes.InvalidArgument, status.Code(roundErr))
+	})
+
+	t.Run("Should create the rounds only with the admin token", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		cfg := config.Default()
+		cfg.AdminToken = testAdminToken
+		_, conn := newTestGRPCAPI(t, cfg)
+		client := votev1.NewVoteServiceClient(conn)
+		_, disabledConn := newTestGRPCAPI(t, config.Default())
+		round := &votev1.CreateRoundRequest{RoundId: "round1"}
+
+		// Act
+		_, missingErr := client.CreateRound(ctx, round)
+		_, wrongErr := client.CreateRound(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer wrong"), round)
+		_, disabledErr := votev1.NewVoteServiceClient(disabledConn).CreateRound(adminContext(ctx), round)
+		_, unknownErr := client.GetTotalVotes(ctx, &votev1.RoundRequest{RoundId: "round1"})
+		_, adminErr := client.CreateRound(adminContext(ctx), round)
+
+		// Assert
+		assert.Equal(t, codes.Unauthenticated, status.Code(missingErr))
+		assert.Equal(t, codes.Unauthenticated, status.Code(wrongErr))
+		assert.Equal(t, codes.PermissionDenied, status.Code(disabledErr))
+		assert.Equal(t, codes.NotFound, status.Code(unknownErr))
+		assert.NoError(t, adminErr)
+	})
+
+	t.Run("Should measure and trace the calls and the pipes they run", func(t *testing.T) {
+		// Arrange
+		recorder := tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		t.Cleanup(func() { otel.SetTracerProvider(previous) })
+		_, conn := newTestGRPCAPI(t, config.Default())
+		client := votev1.NewVoteServiceClient(conn)
+
+		// Act
+		_, err := client.CreateVote(context.Background(), &votev1.CreateVoteRequest{RoundId: "round1", ParticipantId: "alice"})
+		require.NoError(t, err)
+		families, gatherErr := metrics.Registry.Gather()
+
+		// Assert
+		require.NoError(t, gatherErr)
+		var spans []string
+		for _, span := range recorder.Ended() {
+			spans = append(spans, span.Name())
+		}
+		assert.Contains(t, spans, "bbb.vote.v1.VoteService/CreateVote")
+		assert.Contains(t, spans, "pipe.task")
+
+		counts := map[string]uint64{}
+		for _, family := range families {
+			for _, m := range family.GetMetric() {
+				for _, label := range m.GetLabel() {
+					if label.GetName() == "method" && label.GetValue() == votev1.VoteService_CreateVote_FullMethodName {
+						counts[family.GetName()] += m.GetHistogram().GetSampleCount()
+					}
+				}
+				if family.GetName() == "bbb_pipe_task_duration_seconds" {
+					counts[family.GetName()] += m.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+		assert.NotZero(t, counts["bbb_grpc_request_duration_seconds"])
+		assert.NotZero(t, counts["bbb_pipe_task_duration_seconds"])
+	})
+
+	t.Run("Should check the rate limit of each vote of a bulk stream", func(t *testing.T) {
+		// Arrange
+		cfg := config.Default()
+		cfg.Pipeline = memoryPipeline(t)
+		deps, err := newContainer(cfg, logger.Discard())
+		require.NoError(t, err)
+		t.Cleanup(func() { deps.Close() })
+		commandAggregator, _, err := deps.CommandAggregator()
+		require.NoError(t, err)
+		queryAggregator, _, err := deps.QueryAggregator()
+		require.NoError(t, err)
+
+		// the limiter allows two votes, the stream itself is not checked by it
+		var checks atomic.Int32
+		limit := func(ctx context.Context) error {
+			if checks.Add(1) > 2 {
+				return status.Error(codes.ResourceExhausted, "rate limited")
+			}
+			return nil
+		}
+		s := grpc.NewServer()
+		rpcvote.NewVoteServer(commandAggregator, queryAggregator, s, logger.Discard(), limit)
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		go s.Serve(lis)
+		t.Cleanup(s.Stop)
+		conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		client := votev1.NewVoteServiceClient(conn)
+
+		// Act
+		bulk, err := client.BulkCreateVotes(context.Background())
+		require.NoError(t, err)
+		for range 4 {
+			require.NoError(t, bulk.Send(&votev1.CreateVoteRequest{RoundId: "round1", ParticipantId: "alice"}))
+		}
+		res, bulkErr := bulk.CloseAndRecv()
+		total, totalErr := client.GetTotalVotes(context.Background(), &votev1.RoundRequest{RoundId: "round1"})
+
+		// Assert
+		require.NoError(t, bulkErr)
+		require.NoError(t, totalErr)
+		assert.EqualValues(t, 2, res.GetAccepted())
+		assert.EqualValues(t, 2, res.GetFailed())
+		require.Len(t, res.GetErrors(), 2)
+		assert.EqualValues(t, 2, res.GetErrors()[0].GetIndex())
+		assert.Equal(t, "rate limited", res.GetErrors()[0].GetMessage())
+		assert.EqualValues(t, 2, total.GetTotal())
+	})
+
+	t.Run("Should block the IP ranges like the REST API", func(t *testing.T) {
+		// Arrange
+		cfg := config.Default()
+		cfg.BlockedIPRanges = []string{"10.0.0."}
+		cfg.TrustedProxies = []string{"127.0.0.1"}
+		_, conn := newTestGRPCAPI(t, cfg)
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-real-ip", "10.0.0.7")
+		client := votev1.NewVoteServiceClient(conn)
+
+		// Act
+		_, unaryErr := client.GetTotalVotes(ctx, &votev1.RoundRequest{RoundId: "round1"})
+		bulk, err := client.BulkCreateVotes(ctx)
+		require.NoError(t, err)
+		_, streamErr := bulk.CloseAndRecv()
+		_, allowedErr := client.CreateVote(context.Background(), &votev1.CreateVoteRequest{RoundId: "round1", ParticipantId: "alice"})
+
+		// Assert
+		assert.Equal(t, codes.PermissionDenied, status.Code(unaryErr))
+		assert.Equal(t, codes.PermissionDenied, status.Code(streamErr))
+		assert.NoError(t, allowedErr)
+	})
+
+	t.Run("Should serve the metrics only when the metrics port is configured", func(t *testing.T) {
+		// Arrange
+		cfg := config.Default()
+		cfg.Metrics.Port = "9091"
+		api, _ := newTestGRPCAPI(t, cfg)
+		withoutPort, _ := newTestGRPCAPI(t, config.Default())
+		res := httptest.NewRecorder()
+
+		// Act
+		api.metrics.Handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		// Assert
+		assert.Equal(t, ":9091", api.metrics.Addr)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), "go_goroutines")
+		assert.Nil(t, withoutPort.metrics)
+	})
+
+	t.Run("Should report the health of the server", func(t *testing.T) {
+		// Arrange
+		api, conn := newTestGRPCAPI(t, config.Default())
+		client := healthpb.NewHealthClient(conn)
+
+		// Act
+		serving, servingErr := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		api.health.Shutdown()
+		draining, drainingErr := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+		// Assert
+		assert.NoError(t, servingErr)
+		assert.NoError(t, drainingErr)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, serving.GetStatus())
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, draining.GetStatus())
+	})
+}
+
+func TestServeGRPC(t *testing.T) {
+	t.Run("Should stop and wait for the background tasks when the context is done", func(t *testing.T) {
+		// Arrange
+		cfg := config.Default()
+		cfg.Pipeline = memoryPipeline(t)
+		deps, err := newContainer(cfg, logger.Discard())
+		require.NoError(t, err)
+		defer deps.Close()
+		api, err := newGRPCAPI(cfg, logger.Discard(), deps)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		waited := false
+		wait := func(ctx context.Context) error {
+			waited = true
+			return nil
+		}
+
+		// Act
+		cancel()
+		err = serveGRPC(ctx, api, "0", logger.Discard(), config.ShutdownConfig{Timeout: time.Second}, wait)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, waited)
+	})
+
+	t.Run("Should return the error when the server can not listen", func(t *testing.T) {
+		// Act
+		err := serveGRPC(context.Background(), &grpcAPI{}, "invalid", logger.Discard(), config.ShutdownConfig{}, nil)
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
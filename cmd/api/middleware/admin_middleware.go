@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sergiodii/bbb/pkg/problem"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The errors of the administrative requests
+const (
+	adminDisabledMessage     = "Administrative operations are disabled, no admin token is configured"
+	adminUnauthorizedMessage = "A valid admin token is required"
+)
+
+// bearerPrefix is the scheme of the Authorization header with the admin token
+const bearerPrefix = "Bearer "
+
+// adminAccess is the result of the check of an administrative request
+type adminAccess int
+
+const (
+	adminAllowed adminAccess = iota
+	adminDisabled
+	adminUnauthorized
+)
+
+// checkAdminToken checks the Authorization of the request against the admin token.
+// Every request is refused when there is no token, the administrative operations are then disabled.
+func checkAdminToken(token, authorization string) adminAccess {
+	if token == "" {
+		return adminDisabled
+	}
+	sent, ok := strings.CutPrefix(authorization, bearerPrefix)
+	if !ok || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+		return adminUnauthorized
+	}
+	return adminAllowed
+}
+
+// NewAdminMiddlewareV1 allows only the requests with the admin token in the header Authorization: Bearer <token>.
+// The others are answered with 401, or with 403 when no token is configured.
+func NewAdminMiddlewareV1(logger *slog.Logger, token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch checkAdminToken(token, c.GetHeader("Authorization")) {
+		case adminDisabled:
+			problem.Write(c, problem.New(http.StatusForbidden, problem.CodeForbidden, adminDisabledMessage))
+			return
+		case adminUnauthorized:
+			logger.InfoContext(c.Request.Context(), "invalid admin token", "client_ip", c.ClientIP(), "path", c.Request.URL.Path)
+			c.Header("WWW-Authenticate", "Bearer")
+			problem.Write(c, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, adminUnauthorizedMessage))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// NewAdminInterceptorV1 allows the calls of the methods, full names like /bbb.vote.v1.VoteService/CreateRound,
+// only with the admin token in the authorization metadata, like NewAdminMiddlewareV1.
+// The others are rejected with Unauthenticated, or with PermissionDenied when no token is configured.
+func NewAdminInterceptorV1(logger *slog.Logger, token string, methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		var authorization string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				authorization = values[0]
+			}
+		}
+
+		switch checkAdminToken(token, authorization) {
+		case adminDisabled:
+			return nil, status.Error(codes.PermissionDenied, adminDisabledMessage)
+		case adminUnauthorized:
+			logger.InfoContext(ctx, "invalid admin token", "client_ip", GRPCClientIP(ctx), "method", info.FullMethod)
+			return nil, status.Error(codes.Unauthenticated, adminUnauthorizedMessage)
+		}
+		return handler(ctx, req)
+	}
+}
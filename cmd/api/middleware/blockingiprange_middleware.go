@@ -1,33 +1,29 @@
 package middleware
 
 import (
-	"os"
-	"strings"
+	"log/slog"
+	"net/http"
+
+	"github.com/sergiodii/bbb/pkg/problem"
 
 	"github.com/gin-gonic/gin"
 )
 
-func NewBlockingIPRangeMiddlewareV1() gin.HandlerFunc {
+// blockedMessage is the error of the blocked requests
+const blockedMessage = "Access from your IP range is blocked"
+
+// NewBlockingIPRangeMiddlewareV1 answers with 403 the requests from the clients whose IP starts with one of the blocked ranges
+func NewBlockingIPRangeMiddlewareV1(logger *slog.Logger, blockedRanges []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientIP := getClientIP(c)
+		clientIP := c.ClientIP()
 
-		blockedRanges := getBlockedIPRanges()
-		for _, r := range blockedRanges {
-			if strings.HasPrefix(clientIP, strings.TrimSpace(r)) {
-				// Bloqueia o acesso
-				c.AbortWithStatusJSON(403, gin.H{"error": "Access from your IP range is blocked"})
-				return
-			}
+		if r, blocked := blockedRange(clientIP, blockedRanges); blocked {
+			// Bloqueia o acesso
+			logger.InfoContext(c.Request.Context(), "blocked ip range", "client_ip", clientIP, "range", r)
+			problem.Write(c, problem.New(http.StatusForbidden, problem.CodeForbidden, blockedMessage))
+			return
 		}
 
 		c.Next()
 	}
 }
-
-func getBlockedIPRanges() []string {
-	r := os.Getenv("BLOCKED_IP_RANGES")
-	if r == "" {
-		return []string{}
-	}
-	return strings.Split(r, ",")
-}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The gRPC interceptors apply the same rules of the gin middlewares, each one is returned
+// as a unary and a stream interceptor built from a single check of the request.
+
+// NewBlockingIPRangeInterceptorsV1 rejects with PermissionDenied the calls from the clients whose IP
+// starts with one of the blocked ranges, like NewBlockingIPRangeMiddlewareV1
+func NewBlockingIPRangeInterceptorsV1(logger *slog.Logger, blockedRanges []string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	return interceptors(func(ctx context.Context) error {
+		clientIP := GRPCClientIP(ctx)
+
+		if r, blocked := blockedRange(clientIP, blockedRanges); blocked {
+			logger.InfoContext(ctx, "blocked ip range", "client_ip", clientIP, "range", r)
+			return status.Error(codes.PermissionDenied, blockedMessage)
+		}
+		return nil
+	})
+}
+
+// RateLimitInterceptorsV1 rejects with ResourceExhausted the calls over the rate limit, like RateLimitMiddlewareV1.
+// The limit and the wait are sent in the header metadata.
+func RateLimitInterceptorsV1(logger *slog.Logger) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	return interceptors(GRPCRateLimitV1(logger))
+}
+
+// GRPCRateLimitV1 returns the check of the rate limit of RateLimitInterceptorsV1, for the streams that must
+// also check each message they receive, e.g. the votes of a client stream
+func GRPCRateLimitV1(logger *slog.Logger) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		clientIP := GRPCClientIP(ctx)
+
+		md := metadata.Pairs("x-ratelimit-limit", fmt.Sprint(rateLimit), "x-ratelimit-window", rateLimitWindow)
+		if !isAllowed(clientIP) {
+			logger.InfoContext(ctx, "rate limit exceeded", "client_ip", clientIP)
+
+			md.Set("retry-after", fmt.Sprint(retryAfterSeconds))
+			grpc.SetHeader(ctx, md)
+			return status.Error(codes.ResourceExhausted, rateLimitMessage())
+		}
+
+		grpc.SetHeader(ctx, md)
+		return nil
+	}
+}
+
+// interceptors adapts the check of a call to the unary and the stream interceptors
+func interceptors(check func(ctx context.Context) error) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := check(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := check(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
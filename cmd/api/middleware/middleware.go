@@ -1,6 +1,15 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"strings"
+)
+
+// The limits of the rate limiting, shared by the HTTP and the gRPC APIs
+const (
+	rateLimit         = 60
+	rateLimitWindow   = "1 minute"
+	retryAfterSeconds = 60
+)
 
 // isAllowed verifies if the IP can make a new request
 // For simplicity, this example allows all requests.
@@ -13,19 +22,12 @@ func isAllowed(ip string) bool {
 	return true
 }
 
-// getClientIP extracts the real IP of the client (considering proxies)
-func getClientIP(c *gin.Context) string {
-	// Verifica headers de proxy comuns
-	if ip := c.GetHeader("CF-Connecting-IP"); ip != "" {
-		return ip // Cloudflare
-	}
-	if ip := c.GetHeader("X-Real-IP"); ip != "" {
-		return ip // Nginx
+// blockedRange returns the blocked range the IP starts with
+func blockedRange(ip string, blockedRanges []string) (string, bool) {
+	for _, r := range blockedRanges {
+		if strings.HasPrefix(ip, strings.TrimSpace(r)) {
+			return r, true
+		}
 	}
-	if ip := c.GetHeader("X-Forwarded-For"); ip != "" {
-		return ip // Load balancers
-	}
-
-	// Fallback para IP direto
-	return c.ClientIP()
+	return "", false
 }
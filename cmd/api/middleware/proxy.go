@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// RemoteIPHeaders are the headers with the client IP set by the proxies (Cloudflare, Nginx and the load balancers),
+// in the order they are read. They are only read from the requests of a trusted proxy.
+var RemoteIPHeaders = []string{"CF-Connecting-IP", "X-Real-IP", "X-Forwarded-For"}
+
+// trustedProxyKey is the key of the gin context telling whether the request came from a trusted proxy
+const trustedProxyKey = "trusted_proxy"
+
+// TrustedProxies are the proxies and the authentication gateway in front of the API.
+// Only the headers of the requests coming from them are trusted: the client IP they forward and the voter they authenticated.
+type TrustedProxies struct {
+	nets []*net.IPNet
+}
+
+// NewTrustedProxies parses the IPs and the CIDRs of the trusted proxies, no proxy is trusted when there is none
+func NewTrustedProxies(proxies []string) (TrustedProxies, error) {
+	var t TrustedProxies
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return t, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			proxy = fmt.Sprintf("%s/%d", proxy, bits)
+		}
+
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return t, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		t.nets = append(t.nets, ipNet)
+	}
+	return t, nil
+}
+
+// Trusts returns true when the IP is of a trusted proxy
+func (t TrustedProxies) Trusts(ip net.IP) bool {
+	for _, n := range t.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedIP returns the client IP set by the proxies in the headers, like gin.Context.ClientIP:
+// each header is read from right to left, skipping the trusted proxies, so an address set by the client is not used
+func (t TrustedProxies) forwardedIP(header func(string) string) (string, bool) {
+	for _, name := range RemoteIPHeaders {
+		items := strings.Split(header(name), ",")
+		for i := len(items) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(items[i]))
+			if ip == nil {
+				break
+			}
+			if i == 0 || !t.Trusts(ip) {
+				return ip.String(), true
+			}
+		}
+	}
+	return "", false
+}
+
+// NewTrustedProxiesMiddleware marks the requests coming from a trusted proxy, see FromTrustedProxy.
+// The client IP is c.ClientIP, read by gin from RemoteIPHeaders only when the engine trusts the proxy.
+func NewTrustedProxiesMiddleware(trusted TrustedProxies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(trustedProxyKey, trusted.Trusts(net.ParseIP(c.RemoteIP())))
+		c.Next()
+	}
+}
+
+// FromTrustedProxy returns true when the request came from a trusted proxy, so the headers it set can be trusted
+func FromTrustedProxy(c *gin.Context) bool {
+	return c.GetBool(trustedProxyKey)
+}
+
+// caller is the client of a gRPC call, resolved by the trusted proxies interceptors
+type caller struct {
+	ip      string
+	trusted bool
+}
+
+type callerKey struct{}
+
+// NewTrustedProxiesInterceptors resolve the client of the calls before the other interceptors, like the gin engine
+// with its trusted proxies: the metadata set by the proxies is only read when the call comes from a trusted proxy.
+// See GRPCClientIP and GRPCFromTrustedProxy.
+func NewTrustedProxiesInterceptors(trusted TrustedProxies) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	resolve := func(ctx context.Context) context.Context {
+		c := caller{ip: peerIP(ctx)}
+		c.trusted = trusted.Trusts(net.ParseIP(c.ip))
+		if c.trusted {
+			md, _ := metadata.FromIncomingContext(ctx)
+			header := func(name string) string {
+				return strings.Join(md.Get(name), ",")
+			}
+			if ip, ok := trusted.forwardedIP(header); ok {
+				c.ip = ip
+			}
+		}
+		return context.WithValue(ctx, callerKey{}, c)
+	}
+
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(resolve(ctx), req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &callerStream{ServerStream: ss, ctx: resolve(ss.Context())})
+	}
+	return unary, stream
+}
+
+// callerStream is a server stream with the context holding the caller
+type callerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *callerStream) Context() context.Context {
+	return s.ctx
+}
+
+// GRPCClientIP returns the IP of the client of a call, resolved by NewTrustedProxiesInterceptors,
+// or the address of the connection when the call did not go through them
+func GRPCClientIP(ctx context.Context) string {
+	if c, ok := ctx.Value(callerKey{}).(caller); ok {
+		return c.ip
+	}
+	return peerIP(ctx)
+}
+
+// peerIP returns the IP of the connection of the call
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// GRPCFromTrustedProxy returns true when the call came from a trusted proxy, so the fields it set can be trusted
+func GRPCFromTrustedProxy(ctx context.Context) bool {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c.trusted
+}
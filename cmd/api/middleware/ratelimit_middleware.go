@@ -2,37 +2,39 @@ package middleware
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 
+	"github.com/sergiodii/bbb/pkg/problem"
+
 	"github.com/gin-gonic/gin"
 )
 
 // RateLimitMiddleware middleware do Gin para rate limiting
-func RateLimitMiddlewareV1() gin.HandlerFunc {
+func RateLimitMiddlewareV1(logger *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientIP := getClientIP(c)
+		clientIP := c.ClientIP()
 
-		// This method not should be here, but for simplicity in this example we keep it here
-		fmt.Println("Client IP:", clientIP)
 		if !isAllowed(clientIP) {
+			logger.InfoContext(c.Request.Context(), "rate limit exceeded", "client_ip", clientIP)
+
 			// Rate limit excedido
-			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", 60))
-			c.Header("X-RateLimit-Window", "1 minute")
-			c.Header("Retry-After", "60")
-
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Rate limit exceeded",
-				"message":     fmt.Sprintf("Maximum %d requests per %v allowed", 60, "1 minute"),
-				"retry_after": "60 seconds",
-			})
-			c.Abort()
+			c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", rateLimit))
+			c.Header("X-RateLimit-Window", rateLimitWindow)
+			c.Header("Retry-After", fmt.Sprintf("%d", retryAfterSeconds))
+
+			problem.Write(c, problem.New(http.StatusTooManyRequests, problem.CodeRateLimited, rateLimitMessage()))
 			return
 		}
 
 		// Adiciona headers informativos
-		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", 60))
-		c.Header("X-RateLimit-Window", "1 minute")
+		c.Header("X-RateLimit-Limit", fmt.Sprintf("%d", rateLimit))
+		c.Header("X-RateLimit-Window", rateLimitWindow)
 
 		c.Next()
 	}
 }
+
+func rateLimitMessage() string {
+	return fmt.Sprintf("Maximum %d requests per %v allowed", rateLimit, rateLimitWindow)
+}
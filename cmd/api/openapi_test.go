@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sergiodii/bbb/cmd/api/route/health"
+	"github.com/sergiodii/bbb/cmd/api/route/openapi"
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// documentedRoutes returns the routes of the vote package registered in the engine, as "METHOD /path" in the OpenAPI syntax
+func documentedRoutes(r *gin.Engine) []string {
+	var routes []string
+	for _, route := range r.Routes() {
+		if strings.Contains(route.Handler, "/cmd/api/route/vote.") {
+			routes = append(routes, route.Method+" "+openapi.Path(route.Path))
+		}
+	}
+	return routes
+}
+
+// specRoutes returns the operations of the specification, as "METHOD /path"
+func specRoutes(t *testing.T, r *gin.Engine) []string {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var spec struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, openapi.Version, spec.OpenAPI)
+
+	var routes []string
+	for path, methods := range spec.Paths {
+		for method := range methods {
+			routes = append(routes, strings.ToUpper(method)+" "+path)
+		}
+	}
+	return routes
+}
+
+func TestOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Should document every route of the unified API", func(t *testing.T) {
+		// Arrange
+		cfg := config.Default()
+		cfg.Pipeline = memoryPipeline(t)
+		engine, err := NewEngine(cfg, logger.Discard())
+		require.NoError(t, err)
+		defer engine.Close()
+
+		// Act
+		spec := specRoutes(t, engine.router)
+
+		// Assert
+		assert.ElementsMatch(t, documentedRoutes(engine.router), spec)
+		assert.Contains(t, spec, "POST /command/{round_id}")
+		assert.Contains(t, spec, "GET /query/{round_id}/ranking")
+	})
+
+	for name, register := range map[string]func(*gin.Engine, *openapi.Document, string, *slog.Logger, *container) ([]string, error){
+		"query":   queryApiRegister,
+		"command": commandApiRegister,
+	} {
+		t.Run("Should document every route of the "+name+" API", func(t *testing.T) {
+			// Arrange
+			deps := newTestContainer(t)
+			defer deps.Close()
+			r, doc, err := newRouter(config.Default(), health.NewChecker(logger.Discard()), logger.Discard())
+			require.NoError(t, err)
+			_, err = register(r, doc, "", logger.Discard(), deps)
+			require.NoError(t, err)
+
+			// Act
+			spec := specRoutes(t, r)
+
+			// Assert
+			assert.NotEmpty(t, spec)
+			assert.ElementsMatch(t, documentedRoutes(r), spec)
+		})
+	}
+
+	t.Run("Should serve the docs page", func(t *testing.T) {
+		// Arrange
+		r, _, err := newRouter(config.Default(), health.NewChecker(logger.Discard()), logger.Discard())
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+
+		// Act
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, w.Body.String(), "openapi.json")
+	})
+}
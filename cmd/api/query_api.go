@@ -1,21 +1,25 @@
 package api
 
 import (
-	"os"
+	"log/slog"
 
+	"github.com/sergiodii/bbb/cmd/api/route/openapi"
 	"github.com/sergiodii/bbb/cmd/api/route/vote"
-	"github.com/sergiodii/bbb/internal/usecase/vote/aggregator"
-	"github.com/sergiodii/bbb/pkg/redis"
 
 	"github.com/gin-gonic/gin"
 )
 
-func queryApiRegister(g *gin.Engine, rootPath string) {
-	queryAggregator := aggregator.NewQueryAggregator(
-		redis.NewRedisRoundRepository(os.Getenv("REDIS_ADDR")),
-		// localsql.NewLocalSqlRoundRepository(), // This is a example, in real case we could have a different repository for command
-		// can be added other repositories if needed for example: postgres, mongodb, etc
-	)
+// queryApiRegister registers the routes that query the votes, built from the pipeline of the container,
+// and adds their operations to the OpenAPI document.
+// It returns the names of the repositories used by the routes, to be checked by the readiness probe.
+func queryApiRegister(g *gin.Engine, doc *openapi.Document, rootPath string, logger *slog.Logger, deps *container) ([]string, error) {
+	queryAggregator, used, err := deps.QueryAggregator()
+	if err != nil {
+		return nil, err
+	}
 
-	vote.NewQueryRoute(queryAggregator, g.Group(rootPath))
+	vote.NewQueryRoute(queryAggregator, g.Group(rootPath), logger, deps.queryCacheTTL)
+	doc.Add(rootPath, vote.QueryOperations()...)
+
+	return used, nil
 }
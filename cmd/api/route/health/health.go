@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/sergiodii/bbb/internal/domain/repository"
+)
+
+// checkTimeout is the maximum time a single dependency check can take
+const checkTimeout = 2 * time.Second
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// The results of each dependency in the report, the errors are only logged so they are not exposed by the probe
+const (
+	CheckUp   = "up"
+	CheckDown = "down"
+)
+
+// Report is the result of a readiness check
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Checker verifies the dependencies of the API.
+// Only repositories that implement repository.HealthChecker are checked,
+// the others are considered always healthy.
+type Checker struct {
+	checkers map[string]repository.HealthChecker
+	draining atomic.Bool
+	logger   *slog.Logger
+}
+
+// Register adds the repository to the dependencies checked by the readiness probe, with its name in the pipeline.
+// Registering the same name again replaces the repository.
+func (c *Checker) Register(name string, repo repository.RoundRepository) {
+	hc, ok := repo.(repository.HealthChecker)
+	if !ok {
+		return
+	}
+	c.checkers[name] = hc
+}
+
+// SetDraining marks the API as draining, so the readiness probe starts failing
+// and the load balancer stops sending new requests to this instance
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// IsDraining returns true when the API is shutting down
+func (c *Checker) IsDraining() bool {
+	return c.draining.Load()
+}
+
+// Check pings every registered dependency and returns the aggregated report
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.IsDraining() {
+		return Report{Status: StatusDraining}
+	}
+
+	report := Report{Status: StatusOK, Checks: map[string]string{}}
+	for name, hc := range c.checkers {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := hc.Ping(checkCtx)
+		cancel()
+
+		if err != nil {
+			c.logger.WarnContext(ctx, "readiness check failed", "repository", name, "error", err)
+			report.Status = StatusUnavailable
+			report.Checks[name] = CheckDown
+			continue
+		}
+		report.Checks[name] = CheckUp
+	}
+
+	return report
+}
+
+// NewChecker creates a new Checker without dependencies, see Register.
+// The failed checks are logged with the logger.
+func NewChecker(logger *slog.Logger) *Checker {
+	return &Checker{
+		checkers: map[string]repository.HealthChecker{},
+		logger:   logger,
+	}
+}
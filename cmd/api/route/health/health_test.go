@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type repositoryMock struct {
+	err error
+}
+
+func (r *repositoryMock) VoteRegister(ctx context.Context, vote entity.Vote) error { return nil }
+func (r *repositoryMock) GetTotalVotes(ctx context.Context, roundID string) (int, error) {
+	return 0, nil
+}
+func (r *repositoryMock) GetTotalForParticipant(ctx context.Context, roundID string) (map[string]int, error) {
+	return nil, nil
+}
+func (r *repositoryMock) GetTotalForHour(ctx context.Context, roundID string) (map[string]int, error) {
+	return nil, nil
+}
+func (r *repositoryMock) Ping(ctx context.Context) error { return r.err }
+
+func doRequest(checker *Checker, path string) (*httptest.ResponseRecorder, Report) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewHealthRoute(checker, r.Group(""))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report Report
+	json.Unmarshal(w.Body.Bytes(), &report)
+	return w, report
+}
+
+func TestHealthRoute(t *testing.T) {
+	t.Run("Should answer liveness even when a dependency is down", func(t *testing.T) {
+		// Arrange
+		checker := NewChecker(logger.Discard())
+		checker.Register("redis", &repositoryMock{err: assert.AnError})
+
+		// Act
+		w, report := doRequest(checker, "/healthz")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, StatusOK, report.Status)
+	})
+
+	t.Run("Should be ready when every dependency answers", func(t *testing.T) {
+		// Arrange
+		checker := NewChecker(logger.Discard())
+		checker.Register("redis-a", &repositoryMock{})
+		checker.Register("redis-b", &repositoryMock{})
+
+		// Act
+		w, report := doRequest(checker, "/readyz")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, StatusOK, report.Status)
+		assert.Equal(t, map[string]string{"redis-a": CheckUp, "redis-b": CheckUp}, report.Checks)
+	})
+
+	t.Run("Should not be ready when a dependency fails", func(t *testing.T) {
+		// Arrange
+		checker := NewChecker(logger.Discard())
+		checker.Register("redis-a", &repositoryMock{})
+		checker.Register("redis-b", &repositoryMock{err: assert.AnError})
+
+		// Act
+		w, report := doRequest(checker, "/readyz")
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, StatusUnavailable, report.Status)
+		assert.Equal(t, map[string]string{"redis-a": CheckUp, "redis-b": CheckDown}, report.Checks)
+		assert.NotContains(t, w.Body.String(), assert.AnError.Error())
+	})
+
+	t.Run("Should not be ready while draining", func(t *testing.T) {
+		// Arrange
+		checker := NewChecker(logger.Discard())
+		checker.Register("redis", &repositoryMock{})
+		checker.SetDraining()
+
+		// Act
+		w, report := doRequest(checker, "/readyz")
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, StatusDraining, report.Status)
+	})
+}
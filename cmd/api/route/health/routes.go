@@ -0,0 +1,26 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewHealthRoute registers the liveness (/healthz) and readiness (/readyz) probes
+func NewHealthRoute(checker *Checker, g *gin.RouterGroup) {
+
+	// liveness only tells that the process is able to answer requests,
+	// dependencies are not checked here to avoid restarting pods when Redis is down
+	g.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, Report{Status: StatusOK})
+	})
+
+	g.GET("/readyz", func(c *gin.Context) {
+		report := checker.Check(c.Request.Context())
+		if report.Status != StatusOK {
+			c.JSON(http.StatusServiceUnavailable, report)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	})
+}
@@ -0,0 +1,202 @@
+// Package openapi builds the OpenAPI 3 specification of the API from the description of its routes
+// and serves it, with a docs page. The schemas are generated from the Go types of the request and
+// response bodies, so the specification follows the DTOs.
+package openapi
+
+import (
+	"cmp"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification produced
+const Version = "3.0.3"
+
+const contentTypeJSON = "application/json"
+
+// Parameter is a parameter of an operation, the path parameters of the route are added automatically
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Example     string
+}
+
+// Response is a response of an operation
+type Response struct {
+	Status      int
+	Description string
+
+	// Body is a value of the type of the body, nil when the response has no body
+	Body any
+
+	// Example is an example of the body, optional
+	Example any
+
+	// ContentType is the media type of the body, application/json when empty
+	ContentType string
+}
+
+// Operation describes a route
+type Operation struct {
+	Method string
+
+	// Path is the path of the route relative to its group, in the gin syntax (e.g. /:round_id/hour)
+	Path string
+
+	ID          string
+	Tag         string
+	Summary     string
+	Description string
+	Parameters  []Parameter
+
+	// RequestBody is a value of the type of the request body, nil when there is none
+	RequestBody any
+
+	Responses []Response
+}
+
+// Document collects the operations of the API.
+// The operations are added while the routes are registered, before the server starts.
+type Document struct {
+	title       string
+	version     string
+	description string
+	operations  []Operation
+}
+
+// NewDocument creates an empty document
+func NewDocument(title, version, description string) *Document {
+	return &Document{title: title, version: version, description: description}
+}
+
+// Add adds the operations of the routes registered under the base path
+func (d *Document) Add(basePath string, ops ...Operation) {
+	for _, op := range ops {
+		op.Path = strings.TrimSuffix(basePath, "/") + op.Path
+		d.operations = append(d.operations, op)
+	}
+}
+
+// Operations returns the operations of the document, with the full paths
+func (d *Document) Operations() []Operation {
+	return d.operations
+}
+
+// ginParam matches the parameters of a gin path, :name and *name
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// Path converts a gin path to an OpenAPI path, /:round_id becomes /{round_id}
+func Path(ginPath string) string {
+	return ginParam.ReplaceAllString(ginPath, "{$1}")
+}
+
+// Spec builds the OpenAPI specification of the document
+func (d *Document) Spec() map[string]any {
+	s := newSchemas()
+	paths := map[string]map[string]any{}
+
+	for _, op := range d.operations {
+		path := Path(op.Path)
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(op.Method)] = s.operation(op)
+	}
+
+	info := map[string]any{"title": d.title, "version": d.version}
+	if d.description != "" {
+		info["description"] = d.description
+	}
+
+	spec := map[string]any{
+		"openapi": Version,
+		"info":    info,
+		"paths":   paths,
+	}
+	if len(s.components) > 0 {
+		spec["components"] = map[string]any{"schemas": s.components}
+	}
+	return spec
+}
+
+func (s *schemas) operation(op Operation) map[string]any {
+	o := map[string]any{"operationId": op.ID, "summary": op.Summary}
+	if op.Description != "" {
+		o["description"] = op.Description
+	}
+	if op.Tag != "" {
+		o["tags"] = []string{op.Tag}
+	}
+
+	if params := parameters(op); len(params) > 0 {
+		o["parameters"] = params
+	}
+
+	if op.RequestBody != nil {
+		o["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{contentTypeJSON: map[string]any{"schema": s.of(reflect.TypeOf(op.RequestBody))}},
+		}
+	}
+
+	responses := map[string]any{}
+	for _, res := range op.Responses {
+		r := map[string]any{"description": res.Description}
+		if r["description"] == "" {
+			r["description"] = http.StatusText(res.Status)
+		}
+		if res.Body != nil {
+			media := map[string]any{"schema": s.of(reflect.TypeOf(res.Body))}
+			if res.Example != nil {
+				media["example"] = res.Example
+			}
+			r["content"] = map[string]any{cmp.Or(res.ContentType, contentTypeJSON): media}
+		}
+		responses[strconv.Itoa(res.Status)] = r
+	}
+	o["responses"] = responses
+	return o
+}
+
+// parameters returns the parameters of the operation, with a parameter for each path parameter of the route
+func parameters(op Operation) []map[string]any {
+	described := map[string]Parameter{}
+	for _, p := range op.Parameters {
+		described[p.In+":"+p.Name] = p
+	}
+
+	var params []map[string]any
+	add := func(p Parameter, required bool) {
+		param := map[string]any{
+			"name":     p.Name,
+			"in":       p.In,
+			"required": required,
+			"schema":   map[string]any{"type": "string"},
+		}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		if p.Example != "" {
+			param["example"] = p.Example
+		}
+		params = append(params, param)
+	}
+
+	for _, match := range ginParam.FindAllStringSubmatch(op.Path, -1) {
+		p, ok := described["path:"+match[1]]
+		if !ok {
+			p = Parameter{Name: match[1], In: "path"}
+		}
+		add(p, true)
+	}
+	for _, p := range op.Parameters {
+		if p.In != "path" {
+			add(p, false)
+		}
+	}
+	return params
+}
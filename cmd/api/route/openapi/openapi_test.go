@@ -0,0 +1,96 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type person struct {
+	Name     string         `json:"name" doc:"Nome completo"`
+	Age      int            `json:"age,omitempty"`
+	Score    float64        `json:"score"`
+	Active   bool           `json:"active"`
+	Tags     []string       `json:"tags"`
+	Counters map[string]int `json:"counters"`
+	Address  *address       `json:"address" doc:"Endereço"`
+	Friends  []person       `json:"friends,omitempty"`
+	Ignored  string         `json:"-"`
+	internal string
+}
+
+func TestSpec(t *testing.T) {
+	t.Run("Should generate the schemas from the Go types", func(t *testing.T) {
+		// Arrange
+		doc := NewDocument("Test", "1.0.0", "")
+		doc.Add("/people", Operation{
+			Method:      http.MethodPost,
+			Path:        "/:team_id",
+			ID:          "createPerson",
+			RequestBody: person{},
+			Responses:   []Response{{Status: http.StatusCreated, Body: map[string]int{}}},
+		})
+
+		// Act
+		spec := doc.Spec()
+
+		// Assert
+		components := spec["components"].(map[string]any)["schemas"].(map[string]any)
+		assert.Equal(t, map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":     map[string]any{"type": "string", "description": "Nome completo"},
+				"age":      map[string]any{"type": "integer"},
+				"score":    map[string]any{"type": "number"},
+				"active":   map[string]any{"type": "boolean"},
+				"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"counters": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "integer"}},
+				"address": map[string]any{
+					"allOf":       []any{map[string]any{"$ref": "#/components/schemas/address"}},
+					"description": "Endereço",
+				},
+				"friends": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/person"}},
+			},
+			"required": []string{"name", "score", "active", "tags", "counters", "address"},
+		}, components["person"])
+		assert.Contains(t, components, "address")
+
+		op := spec["paths"].(map[string]map[string]any)["/people/{team_id}"]["post"].(map[string]any)
+		assert.Equal(t, "createPerson", op["operationId"])
+		assert.Equal(t, []map[string]any{{"name": "team_id", "in": "path", "required": true, "schema": map[string]any{"type": "string"}}}, op["parameters"])
+		assert.Equal(t, "Created", op["responses"].(map[string]any)["201"].(map[string]any)["description"])
+	})
+
+	t.Run("Should describe the body with the media type of the response", func(t *testing.T) {
+		// Arrange
+		doc := NewDocument("Test", "1.0.0", "")
+		doc.Add("", Operation{
+			Method: http.MethodGet,
+			Path:   "/:id",
+			ID:     "getPerson",
+			Responses: []Response{
+				{Status: http.StatusOK, Body: person{}},
+				{Status: http.StatusNotFound, Body: address{}, ContentType: "application/problem+json"},
+			},
+		})
+
+		// Act
+		spec := doc.Spec()
+
+		// Assert
+		responses := spec["paths"].(map[string]map[string]any)["/{id}"]["get"].(map[string]any)["responses"].(map[string]any)
+		assert.Contains(t, responses["200"].(map[string]any)["content"], "application/json")
+		assert.Contains(t, responses["404"].(map[string]any)["content"], "application/problem+json")
+	})
+
+	t.Run("Should convert the gin paths", func(t *testing.T) {
+		assert.Equal(t, "/query/{round_id}/hour", Path("/query/:round_id/hour"))
+		assert.Equal(t, "/files/{path}", Path("/files/*path"))
+		assert.Equal(t, "/", Path("/"))
+	})
+}
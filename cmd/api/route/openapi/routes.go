@@ -0,0 +1,22 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+// NewRoute registers the specification (/openapi.json) and the docs page (/docs) that renders it
+func NewRoute(doc *Document, g *gin.RouterGroup) {
+	g.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc.Spec())
+	})
+
+	g.GET("/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+	})
+}
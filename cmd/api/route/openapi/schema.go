@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// schemas generates the schemas of the Go types, the named structs are added to the components
+// and referenced, so each DTO is described once
+type schemas struct {
+	components map[string]any
+}
+
+func newSchemas() *schemas {
+	return &schemas{components: map[string]any{}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// of returns the schema of the type
+func (s *schemas) of(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		if _, ok := s.components[t.Name()]; !ok {
+			// reserve the name before the fields, for the recursive types
+			s.components[t.Name()] = nil
+			s.components[t.Name()] = s.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return s.object(t)
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": s.of(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	return map[string]any{}
+}
+
+// object returns the schema of a struct, following the json tags of its fields.
+// The fields without omitempty are required; the doc tag describes a field.
+func (s *schemas) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := s.of(f.Type)
+		if doc := f.Tag.Get("doc"); doc != "" {
+			if _, ref := schema["$ref"]; ref {
+				// the siblings of a $ref are ignored in OpenAPI 3.0
+				schema = map[string]any{"allOf": []any{schema}}
+			}
+			schema["description"] = doc
+		}
+		properties[name] = schema
+
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	o := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		o["required"] = required
+	}
+	return o
+}
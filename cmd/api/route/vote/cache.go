@@ -0,0 +1,56 @@
+package vote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cacheControl returns the Cache-Control of the query responses.
+// The max-age is the cache TTL rounded up to whole seconds, the unit of the header;
+// without a cache the clients must revalidate each response with its ETag.
+func cacheControl(ttl time.Duration) string {
+	if ttl <= 0 {
+		return "no-cache"
+	}
+	return "public, max-age=" + strconv.Itoa(int(math.Ceil(ttl.Seconds())))
+}
+
+// writeCacheable writes the body as JSON with an ETag computed from it and the Cache-Control of the route.
+// A request whose If-None-Match has the ETag is answered with 304 and no body.
+func (q *queryRoute) writeCacheable(c *gin.Context, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		writeError(c, q.logger, "encode response", err)
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", q.cacheControl)
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// etagMatches returns true when the If-None-Match header has the ETag, compared weakly as the RFC 9110 asks
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
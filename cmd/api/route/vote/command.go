@@ -1,50 +1,168 @@
 package vote
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
+	"net/http"
 	"time"
 
+	"github.com/sergiodii/bbb/cmd/api/middleware"
 	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
 	commandUsecase "github.com/sergiodii/bbb/internal/usecase/vote/command"
+	"github.com/sergiodii/bbb/pkg/problem"
+	"github.com/sergiodii/bbb/pkg/tracing"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// VoterIDHeader is the header with the ID of the authenticated voter, set by the authentication gateway.
+// It is only read from the requests of a trusted proxy (trusted_proxies), any other client could choose it.
+// The votes without it are counted as unique voters by the IP of the client.
+const VoterIDHeader = "X-Voter-ID"
+
+// IdempotencyKeyHeader is the header with the key of a vote, the same in every attempt of the vote.
+// A vote whose key was already registered is answered as created again, without being registered.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set in the response of a vote whose key was already registered
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength is the maximum length of an idempotency key
+const maxIdempotencyKeyLength = 128
+
 type commandRoute struct {
-	uc commandUsecase.CommandVoteUseCase
+	uc     commandUsecase.CommandVoteUseCase
+	logger *slog.Logger
+
+	// keys stores the idempotency keys of the votes for the keyTTL, nil when they are ignored
+	keys   repository.IdempotencyRepository
+	keyTTL time.Duration
+}
+
+// reserveKey reserves the idempotency key of the vote. It returns false when the request was answered:
+// the vote of the key was already registered or is being registered, or the key is invalid.
+func (q *commandRoute) reserveKey(c *gin.Context, key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		problem.Write(c, problem.New(http.StatusBadRequest, problem.CodeValidation, "the idempotency key is too long"))
+		return false
+	}
+
+	state, err := q.keys.Reserve(c.Request.Context(), key, q.keyTTL)
+	if err != nil {
+		writeError(c, q.logger, "CreateVote", err, "idempotency_key", key)
+		return false
+	}
+
+	switch state {
+	case repository.IdempotencyDone:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.JSON(http.StatusCreated, VoteCreatedResponse{Status: "vote created"})
+		return false
+	case repository.IdempotencyPending:
+		c.Header("Retry-After", "1")
+		problem.Write(c, problem.New(http.StatusConflict, problem.CodeConflict, "a vote with the same idempotency key is being registered"))
+		return false
+	}
+	return true
+}
+
+// finishKey marks the reserved key as registered, or releases it when the vote failed, so it can be retried.
+// The key is stored even when the request is canceled, otherwise it would stay pending.
+func (q *commandRoute) finishKey(c *gin.Context, key string, voteErr error) {
+	ctx := context.WithoutCancel(c.Request.Context())
+	if voteErr != nil {
+		if err := q.keys.Release(ctx, key); err != nil {
+			q.logger.WarnContext(ctx, "idempotency key not released", "idempotency_key", key, "error", err)
+		}
+		return
+	}
+	if err := q.keys.Complete(ctx, key, q.keyTTL); err != nil {
+		q.logger.WarnContext(ctx, "idempotency key not completed", "idempotency_key", key, "error", err)
+	}
 }
 
 func (q *commandRoute) postCreateVote() func(c *gin.Context) {
 	return func(c *gin.Context) {
 		roundId := c.Param("round_id")
 
-		var body struct {
-			ParticipantID string `json:"participant_id"`
-		}
+		var body VoteRequest
 
-		if err := c.BindJSON(&body); err != nil {
-			c.JSON(400, gin.H{"error": "invalid request body", "details": err.Error()})
+		if err := c.ShouldBindJSON(&body); err != nil {
+			problem.Write(c, problem.New(http.StatusBadRequest, problem.CodeValidation, "invalid request body: "+err.Error()))
 			return
 		}
 
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(tracing.ParticipantIDKey.String(body.ParticipantID))
+
 		ev := entity.Vote{
 			RoundID:       roundId,
 			ParticipantID: body.ParticipantID,
 			Timestamp:     time.Now().Unix(),
+			IP:            c.ClientIP(),
+		}
+		if middleware.FromTrustedProxy(c) {
+			ev.VoterID = c.GetHeader(VoterIDHeader)
+		}
+
+		// the key is scoped by the round, the keys of different rounds never collide
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key != "" && q.keys != nil {
+			key = roundId + ":" + key
+			if !q.reserveKey(c, key) {
+				return
+			}
+		} else {
+			key = ""
 		}
 
 		err := q.uc.CreateVote(c.Request.Context(), ev)
+		if key != "" {
+			q.finishKey(c, key, err)
+		}
+		if err != nil {
+			writeError(c, q.logger, "CreateVote", err, "round_id", roundId, "participant_id", body.ParticipantID)
+			return
+		}
+		c.JSON(201, VoteCreatedResponse{Status: "vote created"})
+	}
+}
+
+func (q *commandRoute) putCreateRound() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		roundId := c.Param("round_id")
+
+		var body RoundRequest
+
+		if err := c.ShouldBindJSON(&body); err != nil {
+			problem.Write(c, problem.New(http.StatusBadRequest, problem.CodeValidation, "invalid request body: "+err.Error()))
+			return
+		}
+
+		round := entity.Round{
+			ID:        roundId,
+			Nome:      body.Name,
+			CreatedAt: time.Now().Unix(),
+		}
+		for _, p := range body.Participants {
+			round.Participants = append(round.Participants, entity.Participant{ID: p.ID, Nome: p.Name})
+		}
+
+		err := q.uc.CreateRound(c.Request.Context(), round)
 		if err != nil {
-			fmt.Printf("[ERROR] CreateVote failed for round %s, participant %s: %v\n", roundId, body.ParticipantID, err)
-			c.JSON(500, gin.H{"error": err.Error()})
+			writeError(c, q.logger, "CreateRound", err, "round_id", roundId)
 			return
 		}
-		c.JSON(201, gin.H{"status": "vote created"})
+		c.JSON(201, RoundCreatedResponse{Status: "round created"})
 	}
 }
 
-func newCommandRoute(uc commandUsecase.CommandVoteUseCase) *commandRoute {
+func newCommandRoute(uc commandUsecase.CommandVoteUseCase, logger *slog.Logger, keys repository.IdempotencyRepository, keyTTL time.Duration) *commandRoute {
 	return &commandRoute{
-		uc: uc,
+		uc:     uc,
+		logger: logger,
+		keys:   keys,
+		keyTTL: keyTTL,
 	}
 }
@@ -0,0 +1,33 @@
+package vote
+
+// VoteRequest is the body of a vote
+type VoteRequest struct {
+	ParticipantID string `json:"participant_id" doc:"ID do participante votado"`
+}
+
+// VoteCreatedResponse is the body of a registered vote
+type VoteCreatedResponse struct {
+	Status string `json:"status"`
+}
+
+// RoundRequest is the body of a round, its metadata
+type RoundRequest struct {
+	Name         string               `json:"name" doc:"Nome da rodada"`
+	Participants []ParticipantRequest `json:"participants" doc:"Participantes da rodada"`
+}
+
+// ParticipantRequest is a participant of a round
+type ParticipantRequest struct {
+	ID   string `json:"id" doc:"ID do participante"`
+	Name string `json:"name" doc:"Nome do participante"`
+}
+
+// RoundCreatedResponse is the body of a created round
+type RoundCreatedResponse struct {
+	Status string `json:"status"`
+}
+
+// TotalResponse is the total number of votes of a round
+type TotalResponse struct {
+	Total int `json:"total"`
+}
@@ -1,13 +1,20 @@
 package vote
 
 import (
+	"log/slog"
+	"time"
+
 	"github.com/gin-gonic/gin"
 
 	queryUsecase "github.com/sergiodii/bbb/internal/usecase/vote/query"
 )
 
 type queryRoute struct {
-	uc queryUsecase.QueryVoteUseCase
+	uc     queryUsecase.QueryVoteUseCase
+	logger *slog.Logger
+
+	// cacheControl is the Cache-Control header of the responses
+	cacheControl string
 }
 
 func (q *queryRoute) getTotalVotes() func(c *gin.Context) {
@@ -16,12 +23,10 @@ func (q *queryRoute) getTotalVotes() func(c *gin.Context) {
 
 		total, err := q.uc.GetTotalVotes(c.Request.Context(), pid)
 		if err != nil {
-			c.JSON(500, gin.H{"error": err.Error()})
+			writeError(c, q.logger, "GetTotalVotes", err, "round_id", pid)
 			return
 		}
-		c.JSON(200, gin.H{
-			"total": total,
-		})
+		q.writeCacheable(c, TotalResponse{Total: total})
 	}
 }
 
@@ -31,10 +36,10 @@ func (q *queryRoute) getTotalVotesForParticipant() func(c *gin.Context) {
 
 		totalMap, err := q.uc.GetTotalVotesForParticipant(c.Request.Context(), pid)
 		if err != nil {
-			c.JSON(500, gin.H{"error": err.Error()})
+			writeError(c, q.logger, "GetTotalVotesForParticipant", err, "round_id", pid)
 			return
 		}
-		c.JSON(200, totalMap)
+		q.writeCacheable(c, totalMap)
 	}
 }
 
@@ -44,15 +49,69 @@ func (q *queryRoute) getTotalVotesForHour() func(c *gin.Context) {
 
 		totalMap, err := q.uc.GetTotalVotesForHour(c.Request.Context(), pid)
 		if err != nil {
-			c.JSON(500, gin.H{"error": err.Error()})
+			writeError(c, q.logger, "GetTotalVotesForHour", err, "round_id", pid)
+			return
+		}
+		q.writeCacheable(c, totalMap)
+	}
+}
+
+func (q *queryRoute) getWinner() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		pid := c.Param("round_id")
+
+		winner, err := q.uc.GetWinner(c.Request.Context(), pid)
+		if err != nil {
+			writeError(c, q.logger, "GetWinner", err, "round_id", pid)
+			return
+		}
+		q.writeCacheable(c, winner)
+	}
+}
+
+func (q *queryRoute) getRanking() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		pid := c.Param("round_id")
+
+		ranking, err := q.uc.GetRanking(c.Request.Context(), pid)
+		if err != nil {
+			writeError(c, q.logger, "GetRanking", err, "round_id", pid)
+			return
+		}
+		q.writeCacheable(c, ranking)
+	}
+}
+
+func (q *queryRoute) getTimeSeries() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		pid := c.Param("round_id")
+
+		series, err := q.uc.GetTimeSeries(c.Request.Context(), pid)
+		if err != nil {
+			writeError(c, q.logger, "GetTimeSeries", err, "round_id", pid)
+			return
+		}
+		q.writeCacheable(c, series)
+	}
+}
+
+func (q *queryRoute) getUniqueVoters() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		pid := c.Param("round_id")
+
+		voters, err := q.uc.GetUniqueVoters(c.Request.Context(), pid)
+		if err != nil {
+			writeError(c, q.logger, "GetUniqueVoters", err, "round_id", pid)
 			return
 		}
-		c.JSON(200, totalMap)
+		q.writeCacheable(c, voters)
 	}
 }
 
-func newQueryRoute(uc queryUsecase.QueryVoteUseCase) *queryRoute {
+func newQueryRoute(uc queryUsecase.QueryVoteUseCase, logger *slog.Logger, cacheTTL time.Duration) *queryRoute {
 	return &queryRoute{
-		uc: uc,
+		uc:           uc,
+		logger:       logger,
+		cacheControl: cacheControl(cacheTTL),
 	}
 }
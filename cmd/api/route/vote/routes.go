@@ -1,23 +1,48 @@
 package vote
 
 import (
+	"log/slog"
+	"time"
+
+	"github.com/sergiodii/bbb/internal/domain/repository"
 	"github.com/sergiodii/bbb/internal/usecase/vote/aggregator"
+	"github.com/sergiodii/bbb/pkg/problem"
 
 	"github.com/gin-gonic/gin"
 )
 
-func NewQueryRoute(aggregator aggregator.QueryAggregator, g *gin.RouterGroup) {
+// NewQueryRoute registers the query routes. Their responses have an ETag and a Cache-Control
+// whose max-age is the cache TTL of the query aggregator, zero when the results are not cached.
+func NewQueryRoute(aggregator aggregator.QueryAggregator, g *gin.RouterGroup, logger *slog.Logger, cacheTTL time.Duration) {
 
-	queryRoute := newQueryRoute(aggregator.GetAggregatedUseCase())
+	queryRoute := newQueryRoute(aggregator.GetAggregatedUseCase(), logger, cacheTTL)
 
 	g.GET("/:round_id", queryRoute.getTotalVotes())
 	g.GET("/:round_id/participant", queryRoute.getTotalVotesForParticipant())
 	g.GET("/:round_id/hour", queryRoute.getTotalVotesForHour())
+	g.GET("/:round_id/winner", queryRoute.getWinner())
+	g.GET("/:round_id/ranking", queryRoute.getRanking())
+	g.GET("/:round_id/timeseries", queryRoute.getTimeSeries())
+	g.GET("/:round_id/voters", queryRoute.getUniqueVoters())
 }
 
-func NewCommandRoute(aggregator aggregator.CommandAggregator, g *gin.RouterGroup) {
+// NewCommandRoute registers the command routes. The idempotency keys of the votes are stored in keys for the keyTTL,
+// so the retries of a registered vote are not registered again; they are ignored when keys is nil.
+// The creation of the rounds goes through admin first, the middleware that authorizes the administrative requests.
+func NewCommandRoute(aggregator aggregator.CommandAggregator, g *gin.RouterGroup, logger *slog.Logger, keys repository.IdempotencyRepository, keyTTL time.Duration, admin gin.HandlerFunc) {
 
-	commandRoute := newCommandRoute(aggregator.GetAggregatedUseCase())
+	commandRoute := newCommandRoute(aggregator.GetAggregatedUseCase(), logger, keys, keyTTL)
 
 	g.POST("/:round_id", commandRoute.postCreateVote())
+	g.PUT("/:round_id", admin, commandRoute.putCreateRound())
+}
+
+// writeError writes the error of the use case as a problem.
+// The errors answered with 5xx are logged with the attributes, the others are errors of the client.
+func writeError(c *gin.Context, logger *slog.Logger, operation string, err error, attrs ...any) {
+	p := problem.FromError(err)
+	if p.Status >= 500 {
+		logger.ErrorContext(c.Request.Context(), operation+" failed", append(attrs, "error", err)...)
+	}
+	problem.Write(c, p)
 }
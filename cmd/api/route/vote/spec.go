@@ -0,0 +1,163 @@
+package vote
+
+import (
+	"net/http"
+
+	"github.com/sergiodii/bbb/cmd/api/route/openapi"
+	queryUsecase "github.com/sergiodii/bbb/internal/usecase/vote/query"
+	"github.com/sergiodii/bbb/pkg/problem"
+)
+
+// The tags of the operations in the OpenAPI specification
+const (
+	tagCommand = "command"
+	tagQuery   = "query"
+)
+
+var roundIDParameter = openapi.Parameter{
+	Name:        "round_id",
+	In:          "path",
+	Description: "ID da rodada (paredão)",
+	Example:     "round-001",
+}
+
+var voterIDParameter = openapi.Parameter{
+	Name:        VoterIDHeader,
+	In:          "header",
+	Description: "ID do eleitor autenticado, usado para contar os eleitores únicos; só é aceito de um proxy confiável (TRUSTED_PROXIES). Sem ele, o eleitor é identificado pelo IP",
+	Example:     "voter-123",
+}
+
+var idempotencyKeyParameter = openapi.Parameter{
+	Name:        IdempotencyKeyHeader,
+	In:          "header",
+	Description: "Chave única do voto, a mesma em todas as tentativas (até 128 caracteres). Uma tentativa de um voto já registrado responde 201 com Idempotent-Replayed, sem registrá-lo de novo",
+	Example:     "6f1c2a9e0b7d4c3e8a5f1d2b3c4e5f60",
+}
+
+var authorizationParameter = openapi.Parameter{
+	Name:        "Authorization",
+	In:          "header",
+	Description: "Token de administração (ADMIN_TOKEN) no formato Bearer; sem token configurado a criação de rodadas fica desativada",
+	Example:     "Bearer s3cr3t",
+}
+
+var ifNoneMatchParameter = openapi.Parameter{
+	Name:        "If-None-Match",
+	In:          "header",
+	Description: "ETag de uma resposta anterior, a resposta é 304 quando o resultado não mudou",
+	Example:     `"9f86d081884c7d659a2feaa0c55ad015"`,
+}
+
+// problemResponse is an error response of the routes, the body is a problem (RFC 7807)
+func problemResponse(status int, description, code, detail string) openapi.Response {
+	return openapi.Response{
+		Status:      status,
+		Description: description,
+		Body:        problem.Problem{},
+		Example:     problem.New(status, code, detail),
+		ContentType: problem.ContentType,
+	}
+}
+
+// notFoundResponse is the error of the queries of a round that was not created and has no votes
+var notFoundResponse = problemResponse(http.StatusNotFound, "Rodada desconhecida: não foi criada e não tem votos", problem.CodeNotFound, "round not found")
+
+// The errors of every route: the repositories unreachable and the unexpected errors, whose message is not exposed
+var (
+	unavailableResponse = problemResponse(http.StatusServiceUnavailable, "Repositórios de votos indisponíveis", problem.CodeUnavailable, "the vote store is unavailable")
+	internalResponse    = problemResponse(http.StatusInternalServerError, "Erro inesperado, os detalhes ficam apenas no log", problem.CodeInternal, "an unexpected error occurred")
+)
+
+// CommandOperations describes the routes registered by NewCommandRoute, for the OpenAPI specification
+func CommandOperations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:      http.MethodPost,
+			Path:        "/:round_id",
+			ID:          "createVote",
+			Tag:         tagCommand,
+			Summary:     "Registra um voto",
+			Description: "Registra um voto para o participante na rodada.",
+			Parameters:  []openapi.Parameter{roundIDParameter, voterIDParameter, idempotencyKeyParameter},
+			RequestBody: VoteRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Description: "Voto registrado", Body: VoteCreatedResponse{}, Example: VoteCreatedResponse{Status: "vote created"}},
+				problemResponse(http.StatusBadRequest, "Corpo da requisição inválido ou voto sem participant_id", problem.CodeValidation, "participant_id is required"),
+				problemResponse(http.StatusConflict, "Um voto com a mesma Idempotency-Key está sendo registrado, tente de novo após o Retry-After", problem.CodeConflict, "a vote with the same idempotency key is being registered"),
+				unavailableResponse,
+				internalResponse,
+			},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/:round_id",
+			ID:          "createRound",
+			Tag:         tagCommand,
+			Summary:     "Cria uma rodada",
+			Description: "Registra os metadados da rodada, substituindo os de uma rodada existente. Uma rodada criada e ainda sem votos é consultada com totais zerados; sem ela, as consultas só conhecem a rodada após o primeiro voto. Operação administrativa, exige o token de administração.",
+			Parameters:  []openapi.Parameter{roundIDParameter, authorizationParameter},
+			RequestBody: RoundRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusCreated, Description: "Rodada criada", Body: RoundCreatedResponse{}, Example: RoundCreatedResponse{Status: "round created"}},
+				problemResponse(http.StatusBadRequest, "Corpo da requisição inválido ou participante sem id", problem.CodeValidation, "the id of every participant is required"),
+				problemResponse(http.StatusUnauthorized, "Token de administração ausente ou inválido", problem.CodeUnauthorized, "A valid admin token is required"),
+				problemResponse(http.StatusForbidden, "Nenhum token de administração configurado, a criação de rodadas está desativada", problem.CodeForbidden, "Administrative operations are disabled, no admin token is configured"),
+				unavailableResponse,
+				internalResponse,
+			},
+		},
+	}
+}
+
+// QueryOperations describes the routes registered by NewQueryRoute, for the OpenAPI specification
+func QueryOperations() []openapi.Operation {
+	query := func(path, id, summary, description string, body, example any) openapi.Operation {
+		return openapi.Operation{
+			Method:      http.MethodGet,
+			Path:        path,
+			ID:          id,
+			Tag:         tagQuery,
+			Summary:     summary,
+			Description: description,
+			Parameters:  []openapi.Parameter{roundIDParameter, ifNoneMatchParameter},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Description: "Resultado com ETag e Cache-Control, o max-age é o tempo em cache das consultas", Body: body, Example: example},
+				{Status: http.StatusNotModified, Description: "O resultado não mudou desde o ETag do If-None-Match"},
+				notFoundResponse,
+				unavailableResponse,
+				internalResponse,
+			},
+		}
+	}
+
+	return []openapi.Operation{
+		query("/:round_id", "getTotalVotes", "Total de votos da rodada",
+			"Total de votos registrados na rodada, somando todos os participantes.", TotalResponse{}, TotalResponse{Total: 15420}),
+		query("/:round_id/participant", "getTotalVotesForParticipant", "Votos por participante",
+			"Total de votos de cada participante da rodada.",
+			map[string]int{}, map[string]int{"participant1": 1500, "participant2": 750}),
+		query("/:round_id/hour", "getTotalVotesForHour", "Votos por hora",
+			"Total de votos de cada hora da rodada, a chave é o número de horas desde a epoch (timestamp Unix / 3600).",
+			map[string]int{}, map[string]int{"488272": 18616, "488273": 750}),
+		query("/:round_id/winner", "getWinner", "Vencedor da rodada",
+			"Participante com mais votos. Em caso de empate, tie é true e o vencedor é o primeiro em ordem alfabética. Sem votos, retorna o vencedor vazio.",
+			queryUsecase.Winner{}, queryUsecase.Winner{ParticipantID: "participant1", Votes: 1500, Percentage: 50}),
+		query("/:round_id/ranking", "getRanking", "Ranking da rodada",
+			"Participantes ordenados pelo número de votos, os empatados compartilham a mesma posição.",
+			[]queryUsecase.RankingEntry{}, []queryUsecase.RankingEntry{
+				{Position: 1, ParticipantID: "participant1", Votes: 1500, Percentage: 50},
+				{Position: 2, ParticipantID: "participant2", Votes: 750, Percentage: 25},
+				{Position: 2, ParticipantID: "participant3", Votes: 750, Percentage: 25},
+			}),
+		query("/:round_id/timeseries", "getTimeSeries", "Série temporal da rodada",
+			"Votos de cada hora em ordem cronológica, com o acumulado. timestamp é o timestamp Unix do início da hora.",
+			[]queryUsecase.TimeSeriesPoint{}, []queryUsecase.TimeSeriesPoint{
+				{Timestamp: 1625079600, Votes: 1500, Cumulative: 1500},
+				{Timestamp: 1625083200, Votes: 750, Cumulative: 2250},
+			}),
+		query("/:round_id/voters", "getUniqueVoters", "Eleitores únicos da rodada",
+			"Número aproximado (HyperLogLog, erro padrão de 0,81%) de eleitores distintos da rodada e de cada participante. O eleitor é o X-Voter-ID do voto ou, sem ele, o IP do cliente.",
+			queryUsecase.UniqueVoters{}, queryUsecase.UniqueVoters{Total: 1200, Participants: map[string]int{"participant1": 800, "participant2": 450}}),
+	}
+}
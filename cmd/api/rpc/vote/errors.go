@@ -0,0 +1,38 @@
+package vote
+
+import (
+	"github.com/sergiodii/bbb/internal/domain/errs"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// internalMessage is the message of the unexpected errors, like in the problems of the REST API
+const internalMessage = "an unexpected error occurred"
+
+// kindCodes are the gRPC codes of the kinds of domain errors
+var kindCodes = map[error]codes.Code{
+	errs.ErrValidation:  codes.InvalidArgument,
+	errs.ErrNotFound:    codes.NotFound,
+	errs.ErrConflict:    codes.FailedPrecondition,
+	errs.ErrRateLimited: codes.ResourceExhausted,
+	errs.ErrUnavailable: codes.Unavailable,
+}
+
+// statusError converts the error of a use case into a gRPC status.
+// The errors of the domain keep their message, the others are Internal and their message is not exposed.
+func statusError(err error) error {
+	if code, ok := kindCodes[errs.KindOf(err)]; ok {
+		return status.Error(code, errs.Message(err))
+	}
+	return status.Error(codes.Internal, internalMessage)
+}
+
+// internal returns true when the error is not a failure of the client, so it must be logged
+func internal(err error) bool {
+	switch errs.KindOf(err) {
+	case errs.ErrValidation, errs.ErrNotFound, errs.ErrConflict, errs.ErrRateLimited:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package vote
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sergiodii/bbb/internal/domain/errs"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{"validation", errs.New(errs.ErrValidation, "participant_id is required"), codes.InvalidArgument, "participant_id is required"},
+		{"not found", errs.New(errs.ErrNotFound, "round not found"), codes.NotFound, "round not found"},
+		{"conflict", errs.New(errs.ErrConflict, "the round is closed"), codes.FailedPrecondition, "the round is closed"},
+		{"rate limited", errs.New(errs.ErrRateLimited, "slow down"), codes.ResourceExhausted, "slow down"},
+		{"unavailable", errs.Wrap(errs.ErrUnavailable, errors.New("dial tcp 10.0.0.1:6379"), "the vote store is unavailable"), codes.Unavailable, "the vote store is unavailable"},
+		{"internal", errors.New("redis: nil"), codes.Internal, internalMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run("Should convert the "+tt.name+" errors", func(t *testing.T) {
+			// Act
+			s := status.Convert(statusError(tt.err))
+
+			// Assert
+			assert.Equal(t, tt.code, s.Code())
+			assert.Equal(t, tt.message, s.Message())
+		})
+	}
+}
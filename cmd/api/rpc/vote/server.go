@@ -0,0 +1,313 @@
+package vote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/sergiodii/bbb/cmd/api/middleware"
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/errs"
+	"github.com/sergiodii/bbb/internal/usecase/vote/aggregator"
+	commandUsecase "github.com/sergiodii/bbb/internal/usecase/vote/command"
+	queryUsecase "github.com/sergiodii/bbb/internal/usecase/vote/query"
+	votev1 "github.com/sergiodii/bbb/pkg/pb/vote/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The intervals of WatchTotals
+const (
+	DefaultWatchInterval = time.Second
+	MinWatchInterval     = 100 * time.Millisecond
+)
+
+// maxBulkErrors is how many failures of a bulk vote are returned
+const maxBulkErrors = 100
+
+// Server is the gRPC VoteService, backed by the same use cases of the REST routes
+type Server struct {
+	votev1.UnimplementedVoteServiceServer
+
+	command commandUsecase.CommandVoteUseCase
+	query   queryUsecase.QueryVoteUseCase
+	logger  *slog.Logger
+
+	// limit checks the rate limit of each vote of BulkCreateVotes, the other calls are limited by the interceptors
+	limit func(ctx context.Context) error
+
+	// closed ends the streams of WatchTotals, which would otherwise hold the graceful stop
+	closed chan struct{}
+}
+
+// NewVoteServer registers the VoteService in the gRPC server, with the use cases of the aggregators.
+// Each vote of a BulkCreateVotes stream is checked by limit, as a CreateVote call is checked by the rate limit interceptor.
+func NewVoteServer(command aggregator.CommandAggregator, query aggregator.QueryAggregator, s *grpc.Server, logger *slog.Logger, limit func(ctx context.Context) error) *Server {
+	srv := &Server{
+		command: command.GetAggregatedUseCase(),
+		query:   query.GetAggregatedUseCase(),
+		logger:  logger,
+		limit:   limit,
+		closed:  make(chan struct{}),
+	}
+	votev1.RegisterVoteServiceServer(s, srv)
+	return srv
+}
+
+// Close ends the WatchTotals streams, it must be called before the graceful stop of the gRPC server
+func (s *Server) Close() {
+	close(s.closed)
+}
+
+func (s *Server) CreateVote(ctx context.Context, req *votev1.CreateVoteRequest) (*votev1.CreateVoteResponse, error) {
+	if err := s.createVote(ctx, req); err != nil {
+		return nil, err
+	}
+	return &votev1.CreateVoteResponse{Status: "vote created"}, nil
+}
+
+func (s *Server) BulkCreateVotes(stream grpc.ClientStreamingServer[votev1.CreateVoteRequest, votev1.BulkCreateVotesResponse]) error {
+	res := &votev1.BulkCreateVotesResponse{}
+
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			// the client closed the stream, every vote was received
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+
+		// a stream is a single call for the interceptors, the votes over the rate limit are failed one by one
+		err = s.limit(stream.Context())
+		if err == nil {
+			err = s.createVote(stream.Context(), req)
+		}
+		if err != nil {
+			res.Failed++
+			if len(res.Errors) < maxBulkErrors {
+				res.Errors = append(res.Errors, &votev1.BulkVoteError{Index: index, Message: status.Convert(err).Message()})
+			}
+			continue
+		}
+		res.Accepted++
+	}
+}
+
+func (s *Server) createVote(ctx context.Context, req *votev1.CreateVoteRequest) error {
+	vote := entity.Vote{
+		RoundID:       req.GetRoundId(),
+		ParticipantID: req.GetParticipantId(),
+		Timestamp:     time.Now().Unix(),
+		IP:            middleware.GRPCClientIP(ctx),
+	}
+
+	// the voter is authenticated by the gateway, a voter_id sent by any other client is ignored
+	if middleware.GRPCFromTrustedProxy(ctx) {
+		vote.VoterID = req.GetVoterId()
+	}
+
+	err := s.command.CreateVote(ctx, vote)
+	if err != nil {
+		if internal(err) {
+			s.logger.ErrorContext(ctx, "CreateVote failed", "round_id", req.GetRoundId(), "participant_id", req.GetParticipantId(), "error", err)
+		}
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *Server) CreateRound(ctx context.Context, req *votev1.CreateRoundRequest) (*votev1.CreateRoundResponse, error) {
+	round := entity.Round{
+		ID:        req.GetRoundId(),
+		Nome:      req.GetName(),
+		CreatedAt: time.Now().Unix(),
+	}
+	for _, p := range req.GetParticipants() {
+		round.Participants = append(round.Participants, entity.Participant{ID: p.GetId(), Nome: p.GetName()})
+	}
+
+	if err := s.command.CreateRound(ctx, round); err != nil {
+		if internal(err) {
+			s.logger.ErrorContext(ctx, "CreateRound failed", "round_id", req.GetRoundId(), "error", err)
+		}
+		return nil, statusError(err)
+	}
+	return &votev1.CreateRoundResponse{Status: "round created"}, nil
+}
+
+func (s *Server) GetTotalVotes(ctx context.Context, req *votev1.RoundRequest) (*votev1.TotalVotesResponse, error) {
+	total, err := query(ctx, s, "GetTotalVotes", req, s.query.GetTotalVotes)
+	if err != nil {
+		return nil, err
+	}
+	return &votev1.TotalVotesResponse{Total: int64(total)}, nil
+}
+
+func (s *Server) GetTotalVotesForParticipant(ctx context.Context, req *votev1.RoundRequest) (*votev1.VotesByKeyResponse, error) {
+	votes, err := query(ctx, s, "GetTotalVotesForParticipant", req, s.query.GetTotalVotesForParticipant)
+	if err != nil {
+		return nil, err
+	}
+	return &votev1.VotesByKeyResponse{Votes: toInt64Map(votes)}, nil
+}
+
+func (s *Server) GetTotalVotesForHour(ctx context.Context, req *votev1.RoundRequest) (*votev1.VotesByKeyResponse, error) {
+	votes, err := query(ctx, s, "GetTotalVotesForHour", req, s.query.GetTotalVotesForHour)
+	if err != nil {
+		return nil, err
+	}
+	return &votev1.VotesByKeyResponse{Votes: toInt64Map(votes)}, nil
+}
+
+func (s *Server) GetWinner(ctx context.Context, req *votev1.RoundRequest) (*votev1.Winner, error) {
+	winner, err := query(ctx, s, "GetWinner", req, s.query.GetWinner)
+	if err != nil {
+		return nil, err
+	}
+	return &votev1.Winner{
+		ParticipantId: winner.ParticipantID,
+		Votes:         int64(winner.Votes),
+		Percentage:    winner.Percentage,
+		Tie:           winner.Tie,
+	}, nil
+}
+
+func (s *Server) GetRanking(ctx context.Context, req *votev1.RoundRequest) (*votev1.RankingResponse, error) {
+	ranking, err := query(ctx, s, "GetRanking", req, s.query.GetRanking)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &votev1.RankingResponse{Entries: make([]*votev1.RankingEntry, 0, len(ranking))}
+	for _, e := range ranking {
+		res.Entries = append(res.Entries, &votev1.RankingEntry{
+			Position:      int64(e.Position),
+			ParticipantId: e.ParticipantID,
+			Votes:         int64(e.Votes),
+			Percentage:    e.Percentage,
+		})
+	}
+	return res, nil
+}
+
+func (s *Server) GetTimeSeries(ctx context.Context, req *votev1.RoundRequest) (*votev1.TimeSeriesResponse, error) {
+	series, err := query(ctx, s, "GetTimeSeries", req, s.query.GetTimeSeries)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &votev1.TimeSeriesResponse{Points: make([]*votev1.TimeSeriesPoint, 0, len(series))}
+	for _, p := range series {
+		res.Points = append(res.Points, &votev1.TimeSeriesPoint{
+			Timestamp:  p.Timestamp,
+			Votes:      int64(p.Votes),
+			Cumulative: int64(p.Cumulative),
+		})
+	}
+	return res, nil
+}
+
+func (s *Server) GetUniqueVoters(ctx context.Context, req *votev1.RoundRequest) (*votev1.UniqueVotersResponse, error) {
+	voters, err := query(ctx, s, "GetUniqueVoters", req, s.query.GetUniqueVoters)
+	if err != nil {
+		return nil, err
+	}
+	return &votev1.UniqueVotersResponse{Total: int64(voters.Total), Participants: toInt64Map(voters.Participants)}, nil
+}
+
+// WatchTotals checks the totals of the round on every interval and sends them when they change,
+// the first totals are sent right away
+func (s *Server) WatchTotals(req *votev1.WatchTotalsRequest, stream grpc.ServerStreamingServer[votev1.LiveTotals]) error {
+	ctx := stream.Context()
+	if req.GetRoundId() == "" {
+		return statusError(errs.New(errs.ErrValidation, "round_id is required"))
+	}
+
+	interval := DefaultWatchInterval
+	if req.GetInterval() != nil {
+		interval = max(req.GetInterval().AsDuration(), MinWatchInterval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *votev1.LiveTotals
+	for {
+		totals, err := s.totals(ctx, req.GetRoundId())
+		if err != nil {
+			return err
+		}
+
+		if last == nil || totals.Total != last.Total || !maps.Equal(totals.Participants, last.Participants) {
+			if err := stream.Send(totals); err != nil {
+				return err
+			}
+			last = totals
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.closed:
+			return status.Error(codes.Unavailable, "the server is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) totals(ctx context.Context, roundID string) (*votev1.LiveTotals, error) {
+	total, err := s.query.GetTotalVotes(ctx, roundID)
+	if err != nil {
+		if internal(err) {
+			s.logger.ErrorContext(ctx, "GetTotalVotes failed", "round_id", roundID, "error", err)
+		}
+		return nil, statusError(err)
+	}
+
+	participants, err := s.query.GetTotalVotesForParticipant(ctx, roundID)
+	if err != nil {
+		if internal(err) {
+			s.logger.ErrorContext(ctx, "GetTotalVotesForParticipant failed", "round_id", roundID, "error", err)
+		}
+		return nil, statusError(err)
+	}
+
+	return &votev1.LiveTotals{
+		RoundId:      roundID,
+		Total:        int64(total),
+		Participants: toInt64Map(participants),
+		Timestamp:    time.Now().Unix(),
+	}, nil
+}
+
+// query validates the request and calls the query of the use case, the errors are converted by statusError
+func query[T any](ctx context.Context, s *Server, name string, req *votev1.RoundRequest, fn func(context.Context, string) (T, error)) (T, error) {
+	if req.GetRoundId() == "" {
+		var zero T
+		return zero, statusError(errs.New(errs.ErrValidation, "round_id is required"))
+	}
+
+	result, err := fn(ctx, req.GetRoundId())
+	if err != nil {
+		if internal(err) {
+			s.logger.ErrorContext(ctx, name+" failed", "round_id", req.GetRoundId(), "error", err)
+		}
+		return result, statusError(err)
+	}
+	return result, nil
+}
+
+func toInt64Map(m map[string]int) map[string]int64 {
+	out := make(map[string]int64, len(m))
+	for k, v := range m {
+		out[k] = int64(v)
+	}
+	return out
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/gin-gonic/gin"
-	"github.com/sergiodii/bbb/cmd/api/middleware"
+	"github.com/sergiodii/bbb/cmd/api/route/health"
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/internal/domain/repository"
 	"github.com/spf13/cobra"
 )
 
@@ -14,27 +16,31 @@ func ApiCommand() *cobra.Command {
 		Short: "Inicia a API de comandos e consultas",
 	}
 
-	c.Flags().StringP("port", "p", "8080", "Porta que a API irá escutar")
-	c.Run = func(cmd *cobra.Command, args []string) {
-		port, _ := cmd.Flags().GetString("port")
-		fmt.Printf("\n[STARTING API] Iniciando API de comandos na porta %s...\n", port)
-		r := gin.Default()
-
-		// This middleware simulate the blocking of IP ranges
-		// You can set the environment variable BLOCKED_IP_RANGES to a comma-separated list of IP prefixes to block
-		// Example: export BLOCKED_IP_RANGES="192.168.1.,10.0.0."
-		// Any request from an IP starting with these prefixes will be blocked with a 403 response
-		r.Use(middleware.NewBlockingIPRangeMiddlewareV1())
-
-		// This middleware simulate a rate limiting of 60 requests per minute per IP
-		// In a real implementation, you would use a more robust solution with a datastore or in-memory structure
-		// to track requests per IP and enforce limits.
-		// Here, for simplicity, we just allow all requests.
-		r.Use(middleware.RateLimitMiddlewareV1())
-
-		queryApiRegister(r, "/query")
-		commandApiRegister(r, "/command")
-		r.Run(":" + port)
+	defaults := defaultConfig("8080")
+	config.BindFlags(c.Flags(), defaults)
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(defaults, cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		logger, closeLogger := setupLogger(cfg.Log)
+		defer closeLogger()
+		logger.Info("starting bbb-api", "port", cfg.Port)
+
+		shutdownTracing, err := setupTracing("bbb-api", cfg.Tracing)
+		if err != nil {
+			return err
+		}
+		defer shutdownTracing()
+
+		engine, err := NewEngine(cfg, logger)
+		if err != nil {
+			return err
+		}
+		defer engine.Close()
+
+		return serve(cmd.Context(), engine.router, cfg.Port, engine.checker, logger, cfg.Shutdown, engine.Wait)
 	}
 
 	return &c
@@ -46,13 +52,42 @@ func QueryApiCommand() *cobra.Command {
 		Short: "Inicia a API de consultas",
 	}
 
-	c.Flags().StringP("port", "p", "8081", "Porta que a API irá escutar")
-	c.Run = func(cmd *cobra.Command, args []string) {
-		port, _ := cmd.Flags().GetString("port")
-		fmt.Printf("\n[STARTING QUERY-API] Iniciando API de consultas na porta %s...\n", port)
-		r := gin.Default()
-		queryApiRegister(r, "")
-		r.Run(":" + port)
+	defaults := defaultConfig("8081")
+	config.BindFlags(c.Flags(), defaults)
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(defaults, cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		logger, closeLogger := setupLogger(cfg.Log)
+		defer closeLogger()
+		logger.Info("starting bbb-query-api", "port", cfg.Port)
+
+		shutdownTracing, err := setupTracing("bbb-query-api", cfg.Tracing)
+		if err != nil {
+			return err
+		}
+		defer shutdownTracing()
+
+		deps, err := newContainer(cfg, logger)
+		if err != nil {
+			return err
+		}
+		defer deps.Close()
+
+		checker := health.NewChecker(logger)
+		r, doc, err := newRouter(cfg, checker, logger)
+		if err != nil {
+			return err
+		}
+		used, err := queryApiRegister(r, doc, "", logger, deps)
+		if err != nil {
+			return err
+		}
+
+		deps.CheckRepositories(checker, used)
+		return serve(cmd.Context(), r, cfg.Port, checker, logger, cfg.Shutdown, deps.Wait)
 	}
 
 	return &c
@@ -64,13 +99,169 @@ func CommandApiCommand() *cobra.Command {
 		Short: "Inicia a API de comandos",
 	}
 
-	c.Flags().StringP("port", "p", "8082", "Porta que a API irá escutar")
-	c.Run = func(cmd *cobra.Command, args []string) {
-		port, _ := cmd.Flags().GetString("port")
-		fmt.Printf("\n[STARTING COMMAND-API] Iniciando API de comandos na porta %s...\n", port)
-		r := gin.Default()
-		commandApiRegister(r, "")
-		r.Run(":" + port)
+	defaults := defaultConfig("8082")
+	config.BindFlags(c.Flags(), defaults)
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(defaults, cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		logger, closeLogger := setupLogger(cfg.Log)
+		defer closeLogger()
+		logger.Info("starting bbb-command-api", "port", cfg.Port)
+
+		shutdownTracing, err := setupTracing("bbb-command-api", cfg.Tracing)
+		if err != nil {
+			return err
+		}
+		defer shutdownTracing()
+
+		deps, err := newContainer(cfg, logger)
+		if err != nil {
+			return err
+		}
+		defer deps.Close()
+
+		checker := health.NewChecker(logger)
+		r, doc, err := newRouter(cfg, checker, logger)
+		if err != nil {
+			return err
+		}
+		used, err := commandApiRegister(r, doc, "", logger, deps)
+		if err != nil {
+			return err
+		}
+
+		deps.CheckRepositories(checker, used)
+		return serve(cmd.Context(), r, cfg.Port, checker, logger, cfg.Shutdown, deps.Wait)
+	}
+
+	return &c
+}
+
+func GrpcApiCommand() *cobra.Command {
+	c := cobra.Command{
+		Use:   "grpc-api",
+		Short: "Inicia a API gRPC de comandos e consultas",
+	}
+
+	defaults := defaultConfig("9090")
+	defaults.Metrics.Port = "9091"
+	config.BindFlags(c.Flags(), defaults)
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(defaults, cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		logger, closeLogger := setupLogger(cfg.Log)
+		defer closeLogger()
+		logger.Info("starting bbb-grpc-api", "port", cfg.Port)
+
+		shutdownTracing, err := setupTracing("bbb-grpc-api", cfg.Tracing)
+		if err != nil {
+			return err
+		}
+		defer shutdownTracing()
+
+		deps, err := newContainer(cfg, logger)
+		if err != nil {
+			return err
+		}
+		defer deps.Close()
+
+		api, err := newGRPCAPI(cfg, logger, deps)
+		if err != nil {
+			return err
+		}
+
+		return serveGRPC(cmd.Context(), api, cfg.Port, logger, cfg.Shutdown, deps.Wait)
+	}
+
+	return &c
+}
+
+func DeadLetterReplayCommand() *cobra.Command {
+	c := cobra.Command{
+		Use:   "deadletter-replay",
+		Short: "Registra novamente os votos que falharam nos repositórios secundários",
+	}
+
+	defaults := config.Default()
+	config.BindFlags(c.Flags(), defaults)
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(defaults, cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		logger, closeLogger := setupLogger(cfg.Log)
+		defer closeLogger()
+
+		deps, err := newContainer(cfg, logger)
+		if err != nil {
+			return err
+		}
+		defer deps.Close()
+
+		commandAggregator, _, err := deps.CommandAggregator()
+		if err != nil {
+			return err
+		}
+
+		replayed, err := commandAggregator.ReplayDeadLetters(cmd.Context())
+		logger.Info("dead letters replayed", "replayed", replayed)
+		return err
+	}
+
+	return &c
+}
+
+// legacyKeyMigrator is implemented by the repositories that can move the data stored in the keys of their first version
+type legacyKeyMigrator interface {
+	MigrateLegacyKeys(ctx context.Context) (int, error)
+}
+
+func RedisMigrateCommand() *cobra.Command {
+	c := cobra.Command{
+		Use:   "redis-migrate",
+		Short: "Move os contadores das chaves antigas do Redis para as chaves atuais",
+		Long: "Move os contadores das chaves antigas do Redis para as chaves atuais, em cada repositório redis do pipeline.\n" +
+			"Execute com a API de comandos parada: os contadores são copiados sobre as chaves atuais. " +
+			"Cada chave antiga é apagada depois de copiada, então o comando pode ser executado novamente após uma falha.",
+	}
+
+	defaults := config.Default()
+	config.BindFlags(c.Flags(), defaults)
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(defaults, cmd.Flags())
+		if err != nil {
+			return err
+		}
+
+		logger, closeLogger := setupLogger(cfg.Log)
+		defer closeLogger()
+
+		deps, err := newContainer(cfg, logger)
+		if err != nil {
+			return err
+		}
+		defer deps.Close()
+
+		for _, name := range deps.repos.Names() {
+			migrator, ok := repository.Unwrap(deps.repos.Get(name)).(legacyKeyMigrator)
+			if !ok {
+				continue
+			}
+
+			moved, err := migrator.MigrateLegacyKeys(cmd.Context())
+			logger.Info("legacy keys migrated", "repository", name, "moved", moved)
+			if err != nil {
+				return fmt.Errorf("migrating the legacy keys of %s: %w", name, err)
+			}
+		}
+		return nil
 	}
 
 	return &c
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sergiodii/bbb/cmd/api/route/health"
+	"github.com/sergiodii/bbb/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serve starts the HTTP server and blocks until a SIGINT or SIGTERM is received or the context is done.
+// On shutdown the readiness probe is flipped to failing, the server stops after the drain delay and
+// waits for the in-flight requests, then wait is called for the background tasks (e.g. the votes being
+// registered in the secondary repositories), so the repositories can be closed safely.
+// Both steps share the shutdown timeout.
+func serve(ctx context.Context, r *gin.Engine, port string, checker *health.Checker, logger *slog.Logger, cfg config.ShutdownConfig, wait func(context.Context) error) error {
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: r,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	failed := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			failed <- err
+		}
+	}()
+
+	select {
+	case err := <-failed:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down, draining connections", "drain_delay", cfg.DrainDelay, "timeout", cfg.Timeout)
+	checker.SetDraining()
+	time.Sleep(cfg.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	var errs []error
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("shutdown failed", "error", err)
+		errs = append(errs, err)
+	}
+	if err := wait(shutdownCtx); err != nil {
+		logger.Error("background tasks did not finish", "error", err)
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
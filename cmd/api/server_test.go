@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/cmd/api/route/health"
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+	t.Run("Should drain and wait for the background tasks when the context is done", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		checker := health.NewChecker(logger.Discard())
+		waited := false
+		wait := func(ctx context.Context) error {
+			waited = checker.IsDraining()
+			return nil
+		}
+
+		// Act
+		cancel()
+		err := serve(ctx, gin.New(), "0", checker, logger.Discard(), config.ShutdownConfig{Timeout: time.Second}, wait)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, waited)
+	})
+
+	t.Run("Should return the error of the background tasks", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		wait := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		// Act
+		cancel()
+		err := serve(ctx, gin.New(), "0", health.NewChecker(logger.Discard()), logger.Discard(), config.ShutdownConfig{Timeout: 10 * time.Millisecond}, wait)
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Should return the error when the server can not listen", func(t *testing.T) {
+		// Act
+		err := serve(context.Background(), gin.New(), "invalid", health.NewChecker(logger.Discard()), logger.Discard(), config.ShutdownConfig{}, nil)
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
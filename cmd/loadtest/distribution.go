@@ -0,0 +1,111 @@
+package loadtest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The distributions of the participants of the votes
+const (
+	DistributionUniform  = "uniform"
+	DistributionWeighted = "weighted"
+	DistributionZipf     = "zipf"
+)
+
+// ErrInvalidDistribution is returned when the distribution of the participants can not be built
+var ErrInvalidDistribution = errors.New("invalid distribution")
+
+// defaultParticipants are used when no participant is given
+var defaultParticipants = []string{"apple", "banana", "cherry", "date", "elderberry"}
+
+// distribution chooses the participant of each vote
+type distribution struct {
+	kind         string
+	participants []string
+
+	// cumulative are the cumulative weights of the participants, used by the weighted distribution
+	cumulative []float64
+
+	// s is the exponent of the zipf distribution, the first participant is the most voted
+	s float64
+}
+
+// newDistribution creates the distribution of the participants.
+// The weighted distribution reads the participants and their weights from weights, e.g. "alice=5,bob=3";
+// the other distributions use participants.
+func newDistribution(kind string, participants []string, weights string, s float64) (distribution, error) {
+	d := distribution{kind: kind, participants: participants, s: s}
+
+	switch kind {
+	case DistributionUniform:
+	case DistributionZipf:
+		// math/rand only generates zipf values with s > 1
+		if s <= 1 {
+			return d, fmt.Errorf("%w: the zipf exponent must be greater than 1, got %v", ErrInvalidDistribution, s)
+		}
+	case DistributionWeighted:
+		var err error
+		if d.participants, d.cumulative, err = parseWeights(weights); err != nil {
+			return d, err
+		}
+	default:
+		return d, fmt.Errorf("%w: unknown distribution %q, expected one of uniform, weighted or zipf", ErrInvalidDistribution, kind)
+	}
+
+	if len(d.participants) == 0 {
+		return d, fmt.Errorf("%w: no participant", ErrInvalidDistribution)
+	}
+	return d, nil
+}
+
+// parseWeights reads a list of participant=weight, it returns the participants and their cumulative weights
+func parseWeights(weights string) ([]string, []float64, error) {
+	var (
+		participants []string
+		cumulative   []float64
+		total        float64
+	)
+
+	for _, item := range strings.Split(weights, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		participant, raw, ok := strings.Cut(item, "=")
+		weight, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if !ok || err != nil || weight <= 0 {
+			return nil, nil, fmt.Errorf("%w: invalid weight %q, expected participant=weight with a positive weight", ErrInvalidDistribution, item)
+		}
+
+		total += weight
+		participants = append(participants, strings.TrimSpace(participant))
+		cumulative = append(cumulative, total)
+	}
+	return participants, cumulative, nil
+}
+
+// sampler returns the function that chooses the participants with the random generator.
+// The generator is not safe for concurrent use, so the sampler must be called by a single goroutine.
+func (d distribution) sampler(r *rand.Rand) func() string {
+	switch d.kind {
+	case DistributionWeighted:
+		total := d.cumulative[len(d.cumulative)-1]
+		return func() string {
+			i, _ := slices.BinarySearch(d.cumulative, r.Float64()*total)
+			return d.participants[min(i, len(d.participants)-1)]
+		}
+	case DistributionZipf:
+		zipf := rand.NewZipf(r, d.s, 1, uint64(len(d.participants)-1))
+		return func() string {
+			return d.participants[zipf.Uint64()]
+		}
+	default:
+		return func() string {
+			return d.participants[r.IntN(len(d.participants))]
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package loadtest
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sample(t *testing.T, d distribution, n int) map[string]int {
+	t.Helper()
+	pick := d.sampler(rand.New(rand.NewPCG(1, 1)))
+	counts := map[string]int{}
+	for range n {
+		counts[pick()]++
+	}
+	return counts
+}
+
+func TestDistribution(t *testing.T) {
+	t.Run("Should choose every participant with the uniform distribution", func(t *testing.T) {
+		// Arrange
+		d, err := newDistribution(DistributionUniform, []string{"a", "b", "c"}, "", 0)
+		require.NoError(t, err)
+
+		// Act
+		counts := sample(t, d, 3000)
+
+		// Assert
+		for _, participant := range []string{"a", "b", "c"} {
+			assert.InDelta(t, 1000, counts[participant], 100, participant)
+		}
+	})
+
+	t.Run("Should choose the participants in proportion to their weights", func(t *testing.T) {
+		// Arrange
+		d, err := newDistribution(DistributionWeighted, nil, "alice=5, bob=3,carol=2", 0)
+		require.NoError(t, err)
+
+		// Act
+		counts := sample(t, d, 10000)
+
+		// Assert
+		assert.InDelta(t, 5000, counts["alice"], 300)
+		assert.InDelta(t, 3000, counts["bob"], 300)
+		assert.InDelta(t, 2000, counts["carol"], 300)
+	})
+
+	t.Run("Should choose the first participants more often with the zipf distribution", func(t *testing.T) {
+		// Arrange
+		d, err := newDistribution(DistributionZipf, []string{"a", "b", "c", "d"}, "", 1.5)
+		require.NoError(t, err)
+
+		// Act
+		counts := sample(t, d, 10000)
+
+		// Assert
+		assert.Greater(t, counts["a"], counts["b"])
+		assert.Greater(t, counts["b"], counts["c"])
+		assert.Greater(t, counts["c"], counts["d"])
+	})
+
+	t.Run("Should repeat the same votes with the same seed", func(t *testing.T) {
+		// Arrange
+		d, err := newDistribution(DistributionUniform, defaultParticipants, "", 0)
+		require.NoError(t, err)
+
+		// Act
+		first, second := sample(t, d, 100), sample(t, d, 100)
+
+		// Assert
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("Should reject an invalid distribution", func(t *testing.T) {
+		for name, build := range map[string]func() (distribution, error){
+			"unknown":        func() (distribution, error) { return newDistribution("normal", defaultParticipants, "", 0) },
+			"no participant": func() (distribution, error) { return newDistribution(DistributionUniform, nil, "", 0) },
+			"bad weight":     func() (distribution, error) { return newDistribution(DistributionWeighted, nil, "alice=x", 0) },
+			"zero weight":    func() (distribution, error) { return newDistribution(DistributionWeighted, nil, "alice=0", 0) },
+			"no weights":     func() (distribution, error) { return newDistribution(DistributionWeighted, nil, "", 0) },
+			"zipf exponent":  func() (distribution, error) { return newDistribution(DistributionZipf, defaultParticipants, "", 1) },
+		} {
+			// Act
+			_, err := build()
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidDistribution, name)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package loadtest
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// The load profiles, both are open-loop: the requests are sent on schedule, whatever the latency of the API
+const (
+	ProfileConstant = "constant"
+	ProfileRamp     = "ramp"
+)
+
+// ErrInvalidProfile is returned when the load profile is not valid
+var ErrInvalidProfile = errors.New("invalid profile")
+
+// profile describes the rate of the requests over time
+type profile struct {
+	kind string
+
+	// rate is the number of requests per second, the final rate of a ramp
+	rate float64
+
+	// startRate is the initial rate of a ramp
+	startRate float64
+
+	duration time.Duration
+
+	// maxRequests stops the test before the end of the duration, zero means no limit
+	maxRequests int
+}
+
+// validate checks the profile
+func (p profile) validate() error {
+	switch p.kind {
+	case ProfileConstant, ProfileRamp:
+	default:
+		return fmt.Errorf("%w: unknown profile %q, expected one of constant or ramp", ErrInvalidProfile, p.kind)
+	}
+
+	switch {
+	case p.rate <= 0:
+		return fmt.Errorf("%w: the rate must be positive", ErrInvalidProfile)
+	case interval(p.rate) <= 0:
+		return fmt.Errorf("%w: the rate must be at most %d requests per second", ErrInvalidProfile, time.Second)
+	case p.kind == ProfileRamp && p.startRate <= 0:
+		return fmt.Errorf("%w: the start rate of the ramp must be positive", ErrInvalidProfile)
+	case p.kind == ProfileRamp && interval(p.startRate) <= 0:
+		return fmt.Errorf("%w: the start rate of the ramp must be at most %d requests per second", ErrInvalidProfile, time.Second)
+	case p.duration <= 0:
+		return fmt.Errorf("%w: the duration must be positive", ErrInvalidProfile)
+	case p.maxRequests < 0:
+		return fmt.Errorf("%w: the number of requests must not be negative", ErrInvalidProfile)
+	}
+	return nil
+}
+
+// rateAt returns the rate of the requests after the elapsed time
+func (p profile) rateAt(elapsed time.Duration) float64 {
+	if p.kind != ProfileRamp {
+		return p.rate
+	}
+	progress := min(float64(elapsed)/float64(p.duration), 1)
+	return p.startRate + (p.rate-p.startRate)*progress
+}
+
+// interval returns the time between two requests at the rate
+func interval(rate float64) time.Duration {
+	return time.Duration(float64(time.Second) / rate)
+}
+
+// schedule yields when each request must be sent, as offsets from the start of the test.
+// The offsets are computed as they are read, so the memory does not grow with the rate and the duration.
+func (p profile) schedule() iter.Seq[time.Duration] {
+	return func(yield func(time.Duration) bool) {
+		sent := 0
+		for at := time.Duration(0); at < p.duration; sent++ {
+			if p.maxRequests > 0 && sent == p.maxRequests {
+				return
+			}
+			if !yield(at) {
+				return
+			}
+
+			// a rate too high for the resolution of time.Duration would never reach the end, see validate
+			step := interval(p.rateAt(at))
+			if step <= 0 {
+				return
+			}
+			at += step
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package loadtest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile(t *testing.T) {
+	t.Run("Should schedule a constant rate during the duration", func(t *testing.T) {
+		// Arrange
+		p := profile{kind: ProfileConstant, rate: 10, duration: time.Second}
+
+		// Act
+		offsets := slices.Collect(p.schedule())
+
+		// Assert
+		assert.Len(t, offsets, 10)
+		assert.Equal(t, time.Duration(0), offsets[0])
+		assert.Equal(t, 900*time.Millisecond, offsets[9])
+	})
+
+	t.Run("Should stop the schedule at the maximum number of requests", func(t *testing.T) {
+		// Arrange
+		p := profile{kind: ProfileConstant, rate: 10, duration: time.Second, maxRequests: 3}
+
+		// Act
+		offsets := slices.Collect(p.schedule())
+
+		// Assert
+		assert.Len(t, offsets, 3)
+	})
+
+	t.Run("Should increase the rate of a ramp from the start rate", func(t *testing.T) {
+		// Arrange
+		p := profile{kind: ProfileRamp, rate: 100, startRate: 10, duration: 10 * time.Second}
+
+		// Act
+		offsets := slices.Collect(p.schedule())
+
+		// Assert
+		assert.Equal(t, 10.0, p.rateAt(0))
+		assert.Equal(t, 55.0, p.rateAt(5*time.Second))
+		assert.Equal(t, 100.0, p.rateAt(20*time.Second))
+		first, last := offsets[1]-offsets[0], offsets[len(offsets)-1]-offsets[len(offsets)-2]
+		assert.Greater(t, first, last)
+		// the mean rate of the ramp is 55 req/s
+		assert.InDelta(t, 550, len(offsets), 30)
+	})
+
+	t.Run("Should stop reading the schedule when the caller stops", func(t *testing.T) {
+		// Arrange
+		p := profile{kind: ProfileConstant, rate: 1e6, duration: time.Hour}
+		read := 0
+
+		// Act
+		for range p.schedule() {
+			read++
+			if read == 5 {
+				break
+			}
+		}
+
+		// Assert
+		assert.Equal(t, 5, read)
+	})
+
+	t.Run("Should reject an invalid profile", func(t *testing.T) {
+		for _, p := range []profile{
+			{kind: "spike", rate: 10, duration: time.Second},
+			{kind: ProfileConstant, rate: 0, duration: time.Second},
+			{kind: ProfileRamp, rate: 10, duration: time.Second},
+			{kind: ProfileConstant, rate: 10},
+			{kind: ProfileConstant, rate: 10, duration: time.Second, maxRequests: -1},
+			{kind: ProfileConstant, rate: 2e9, duration: time.Second},
+			{kind: ProfileRamp, rate: 10, startRate: 2e9, duration: time.Second},
+		} {
+			// Act
+			err := p.validate()
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidProfile, "%+v", p)
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The operations of the workload
+const (
+	OperationVote  = "vote"
+	OperationQuery = "query"
+)
+
+// statusError is the status of the requests that failed without a response, e.g. a timeout
+const statusError = "error"
+
+// result is the outcome of a request
+type result struct {
+	operation   string
+	participant string
+	status      int
+	err         error
+	latency     time.Duration
+}
+
+// statusKey is the key of the result in the breakdown by status
+func (r result) statusKey() string {
+	if r.err != nil {
+		return statusError
+	}
+	return strconv.Itoa(r.status)
+}
+
+// failed returns true when the request failed or the API answered with an error
+func (r result) failed() bool {
+	return r.err != nil || r.status >= 400
+}
+
+// recorder collects the results of the requests, it is safe for concurrent use
+type recorder struct {
+	m          sync.Mutex
+	operations map[string]*operationResults
+	dropped    int
+
+	// accepted are the votes answered with 201 per participant, compared by the verification
+	accepted map[string]int
+}
+
+type operationResults struct {
+	latencies []time.Duration
+	status    map[string]int
+	errors    int
+}
+
+func newRecorder() *recorder {
+	return &recorder{operations: map[string]*operationResults{}, accepted: map[string]int{}}
+}
+
+func (r *recorder) record(res result) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	op, ok := r.operations[res.operation]
+	if !ok {
+		op = &operationResults{status: map[string]int{}}
+		r.operations[res.operation] = op
+	}
+
+	op.latencies = append(op.latencies, res.latency)
+	op.status[res.statusKey()]++
+	if res.failed() {
+		op.errors++
+	}
+	if res.operation == OperationVote && res.err == nil && res.status == http.StatusCreated {
+		r.accepted[res.participant]++
+	}
+}
+
+// acceptedVotes returns the votes answered with 201 per participant
+func (r *recorder) acceptedVotes() map[string]int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return maps.Clone(r.accepted)
+}
+
+// drop counts a request that was not sent because every worker was busy
+func (r *recorder) drop() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.dropped++
+}
+
+// Report is the result of a load test
+type Report struct {
+	Profile    string                     `json:"profile"`
+	Duration   float64                    `json:"duration_seconds"`
+	Requests   int                        `json:"requests"`
+	Errors     int                        `json:"errors"`
+	Dropped    int                        `json:"dropped"`
+	Throughput float64                    `json:"throughput_rps"`
+	Operations map[string]OperationReport `json:"operations"`
+
+	// Verification is only filled with --verify
+	Verification *Verification `json:"verification,omitempty"`
+}
+
+// OperationReport is the result of the requests of an operation
+type OperationReport struct {
+	Requests int            `json:"requests"`
+	Errors   int            `json:"errors"`
+	Latency  LatencyReport  `json:"latency_ms"`
+	Status   map[string]int `json:"status"`
+}
+
+// LatencyReport are the latencies of the requests, in milliseconds
+type LatencyReport struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// report summarizes the results collected in the elapsed time
+func (r *recorder) report(profileKind string, elapsed time.Duration) Report {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	report := Report{
+		Profile:    profileKind,
+		Duration:   elapsed.Seconds(),
+		Dropped:    r.dropped,
+		Operations: map[string]OperationReport{},
+	}
+
+	for name, op := range r.operations {
+		report.Operations[name] = OperationReport{
+			Requests: len(op.latencies),
+			Errors:   op.errors,
+			Latency:  newLatencyReport(op.latencies),
+			Status:   maps.Clone(op.status),
+		}
+		report.Requests += len(op.latencies)
+		report.Errors += op.errors
+	}
+
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		report.Throughput = float64(report.Requests) / seconds
+	}
+	return report
+}
+
+func newLatencyReport(latencies []time.Duration) LatencyReport {
+	if len(latencies) == 0 {
+		return LatencyReport{}
+	}
+
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+
+	return LatencyReport{
+		Mean: milliseconds(total / time.Duration(len(sorted))),
+		P50:  milliseconds(percentile(sorted, 50)),
+		P95:  milliseconds(percentile(sorted, 95)),
+		P99:  milliseconds(percentile(sorted, 99)),
+		Max:  milliseconds(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank-1, 0), len(sorted)-1)]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// writeJSON writes the report in JSON, to be compared between runs
+func (r Report) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// writeText writes the report for humans
+func (r Report) writeText(w io.Writer) {
+	icon := "✅"
+	if r.Errors > 0 || r.Dropped > 0 {
+		icon = "⚠️"
+	}
+
+	fmt.Fprintf(w, "\n%s [FINISHED LOAD TEST] Teste de carga finalizado (perfil %s):\n\n", icon, r.Profile)
+	fmt.Fprintf(w, "- ⏰ Tempo: %.2f segundos\n", r.Duration)
+	fmt.Fprintf(w, "- 📈 Throughput: %.2f req/s\n", r.Throughput)
+	fmt.Fprintf(w, "- 📨 Requisições: %d (erros: %d, descartadas: %d)\n", r.Requests, r.Errors, r.Dropped)
+
+	for _, name := range slices.Sorted(maps.Keys(r.Operations)) {
+		op := r.Operations[name]
+		fmt.Fprintf(w, "\n  %s: %d requisições, %d erros\n", name, op.Requests, op.Errors)
+		fmt.Fprintf(w, "    latência (ms): média %.2f | p50 %.2f | p95 %.2f | p99 %.2f | máx %.2f\n",
+			op.Latency.Mean, op.Latency.P50, op.Latency.P95, op.Latency.P99, op.Latency.Max)
+		for _, status := range slices.Sorted(maps.Keys(op.Status)) {
+			fmt.Fprintf(w, "    status %s: %d\n", status, op.Status[status])
+		}
+	}
+	fmt.Fprintln(w)
+
+	if r.Verification != nil {
+		r.Verification.writeText(w)
+	}
+}
@@ -0,0 +1,50 @@
+package loadtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("Should compute the latency percentiles of each operation", func(t *testing.T) {
+		// Arrange
+		rec := newRecorder()
+		for i := 1; i <= 100; i++ {
+			rec.record(result{operation: OperationVote, status: 201, latency: time.Duration(i) * time.Millisecond})
+		}
+
+		// Act
+		report := rec.report(ProfileConstant, 2*time.Second)
+
+		// Assert
+		latency := report.Operations[OperationVote].Latency
+		assert.Equal(t, 50.5, latency.Mean)
+		assert.Equal(t, 50.0, latency.P50)
+		assert.Equal(t, 95.0, latency.P95)
+		assert.Equal(t, 99.0, latency.P99)
+		assert.Equal(t, 100.0, latency.Max)
+		assert.Equal(t, 50.0, report.Throughput)
+	})
+
+	t.Run("Should count the errors and the dropped requests", func(t *testing.T) {
+		// Arrange
+		rec := newRecorder()
+		rec.record(result{operation: OperationVote, status: 201})
+		rec.record(result{operation: OperationVote, status: 500})
+		rec.record(result{operation: OperationQuery, err: errors.New("timeout")})
+		rec.drop()
+
+		// Act
+		report := rec.report(ProfileConstant, time.Second)
+
+		// Assert
+		assert.Equal(t, 3, report.Requests)
+		assert.Equal(t, 2, report.Errors)
+		assert.Equal(t, 1, report.Dropped)
+		assert.Equal(t, map[string]int{"201": 1, "500": 1}, report.Operations[OperationVote].Status)
+		assert.Equal(t, map[string]int{statusError: 1}, report.Operations[OperationQuery].Status)
+	})
+}
@@ -2,105 +2,173 @@ package loadtest
 
 import (
 	"fmt"
-	"math/rand"
+	"math/rand/v2"
 	"net/http"
-	"strings"
-	"sync"
+	"os"
+	"os/signal"
 	"time"
 
-	"github.com/sergiodii/bbb/extension/slice"
 	"github.com/spf13/cobra"
 )
 
-func getRandomStringFromSlice() string {
-	slice := []string{"apple", "banana", "cherry", "date", "elderberry"}
-
-	rand.Seed(time.Now().UnixNano())
-
-	randomIndex := rand.Intn(len(slice))
-
-	// Retorna a string no índice aleatório.
-	return slice[randomIndex]
-}
+// The formats of the report
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
 
 func LoadTestCommand() *cobra.Command {
 	c := cobra.Command{
 		Use:   "loadtest",
-		Short: "Inicia o teste de carga chamando a api de inserção de dados N vezes",
+		Short: "Inicia o teste de carga enviando votos (e consultas) em uma taxa definida pelo perfil",
 	}
 
 	c.Flags().StringP("url", "c", "localhost:8082", "URL da API de Inserção de votos")
-	c.Flags().IntP("requests", "r", 1000, "Número de requisições a serem feitas")
-	c.Flags().IntP("concurrent", "n", 100, "Número de requisições concorrentes")
+	c.Flags().String("query-url", "localhost:8081", "URL da API de consultas, usada quando --read-ratio > 0")
 	c.Flags().String("round-id", "round1", "ID da rodada para a qual os votos serão enviados")
 
-	c.Run = func(cmd *cobra.Command, args []string) {
+	c.Flags().String("profile", ProfileConstant, "Perfil de carga: constant (taxa fixa) ou ramp (taxa crescente de --start-rate até --rate)")
+	c.Flags().Float64("rate", 100, "Requisições por segundo (taxa final do ramp)")
+	c.Flags().Float64("start-rate", 1, "Requisições por segundo no início do ramp")
+	c.Flags().Duration("duration", 10*time.Second, "Duração do teste")
+	c.Flags().IntP("requests", "r", 0, "Número máximo de requisições (0 = sem limite, apenas a duração)")
+	c.Flags().IntP("concurrent", "n", 100, "Número máximo de requisições em andamento, as excedentes são descartadas")
+	c.Flags().Duration("timeout", 30*time.Second, "Timeout de cada requisição")
+
+	c.Flags().String("distribution", DistributionUniform, "Distribuição dos participantes: uniform, weighted ou zipf")
+	c.Flags().StringSlice("participants", defaultParticipants, "Participantes das distribuições uniform e zipf (o primeiro é o mais votado no zipf)")
+	c.Flags().String("weights", "", "Participantes e pesos da distribuição weighted (ex: alice=5,bob=3,carol=2)")
+	c.Flags().Float64("zipf-s", 1.2, "Expoente da distribuição zipf (maior que 1)")
+	c.Flags().Float64("read-ratio", 0, "Fração das requisições que são consultas na API de consultas (0 a 1)")
+	c.Flags().Uint64("seed", 0, "Semente do gerador aleatório, para repetir uma carga (0 = aleatória)")
+
+	c.Flags().StringP("output", "o", OutputText, "Formato do relatório: text ou json")
+
+	c.Flags().Bool("verify", false, "Confere se os votos aceitos (201) foram contabilizados pela API de consultas (--query-url) e falha em caso de divergência")
+	c.Flags().Duration("settle", 2*time.Second, "Tempo de espera após o teste para os votos serem contabilizados antes da verificação")
+
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+
+		var p profile
+		p.kind, _ = flags.GetString("profile")
+		p.rate, _ = flags.GetFloat64("rate")
+		p.startRate, _ = flags.GetFloat64("start-rate")
+		p.duration, _ = flags.GetDuration("duration")
+		p.maxRequests, _ = flags.GetInt("requests")
+		if err := p.validate(); err != nil {
+			return err
+		}
+
+		kind, _ := flags.GetString("distribution")
+		participants, _ := flags.GetStringSlice("participants")
+		weights, _ := flags.GetString("weights")
+		s, _ := flags.GetFloat64("zipf-s")
+		dist, err := newDistribution(kind, participants, weights, s)
+		if err != nil {
+			return err
+		}
+
+		output, _ := flags.GetString("output")
+		if output != OutputText && output != OutputJSON {
+			return fmt.Errorf("unknown output %q, expected one of text or json", output)
+		}
 
-		// Get flags
-		maxIncrements, _ := cmd.Flags().GetInt("requests")
-		url, _ := cmd.Flags().GetString("url")
-		roundID, _ := cmd.Flags().GetString("round-id")
-		concurrent, _ := cmd.Flags().GetInt("concurrent")
+		readRatio, _ := flags.GetFloat64("read-ratio")
+		if readRatio < 0 || readRatio > 1 {
+			return fmt.Errorf("the read ratio must be between 0 and 1, got %v", readRatio)
+		}
 
-		// Generate random participant IDs
-		participants := []string{}
-		for i := 0; i < maxIncrements; i++ {
-			participants = append(participants, getRandomStringFromSlice())
+		concurrent, _ := flags.GetInt("concurrent")
+		if concurrent < 1 {
+			return fmt.Errorf("the number of concurrent requests must be at least 1, got %d", concurrent)
 		}
 
-		// Start load test time tracking
-		start := time.Now()
-		fmt.Printf("\n 🏁 [STARTING LOAD TEST] Iniciando teste de carga com %d requisições para a API de inserção de votos %s...\n", maxIncrements, url)
+		seed, _ := flags.GetUint64("seed")
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		rng := rand.New(rand.NewPCG(seed, seed))
+
+		commandURL, _ := flags.GetString("url")
+		queryURL, _ := flags.GetString("query-url")
+		roundID, _ := flags.GetString("round-id")
+		w := &workload{
+			commandURL: baseURL(commandURL),
+			queryURL:   baseURL(queryURL),
+			roundID:    roundID,
+			readRatio:  readRatio,
+			pick:       dist.sampler(rng),
+			rng:        rng,
+		}
 
-		// Client HTTP otimizado
+		timeout, _ := flags.GetDuration("timeout")
 		client := &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   timeout,
+			Transport: &http.Transport{MaxIdleConnsPerHost: concurrent},
+		}
+
+		if output == OutputText {
+			fmt.Fprintf(cmd.OutOrStdout(), "\n 🏁 [STARTING LOAD TEST] Perfil %s a %.0f req/s por %s contra %s (seed %d)...\n", p.kind, p.rate, p.duration, w.commandURL, seed)
 		}
 
-		for _, l := range slice.TransformSliceToMultipleSlices(participants, concurrent) {
-			wg := sync.WaitGroup{}
-			finalUrl := "http://" + url + "/" + roundID
-			for _, participant := range l {
-				wg.Add(1)
-				go func(p string) {
-					defer wg.Done()
-					body := fmt.Sprintf(`{"participant_id": "%v"}`, p)
-					resp, err := client.Post(finalUrl, "application/json", strings.NewReader(body))
-					if err != nil {
-						fmt.Println("Participant ID:", body, ", Error:", err)
-					} else if resp.StatusCode >= 400 {
-						fmt.Printf("Participant ID: %s, HTTP Status: %d\n", body, resp.StatusCode)
-						resp.Body.Close()
-					} else {
-						resp.Body.Close()
-					}
-				}(participant)
+		// the flags are valid, a failure from now on is not a usage error
+		cmd.SilenceUsage = true
 
-			}
-			wg.Wait()
-			// Small delay between batches to avoid overwhelming the server
-			time.Sleep(10 * time.Millisecond)
+		verifyVotes, _ := flags.GetBool("verify")
+		settle, _ := flags.GetDuration("settle")
+
+		// the counters before the test, so the verification works on a round that already has votes
+		var before snapshot
+		baseline := false
+		if verifyVotes {
+			var err error
+			before, err = fetchSnapshot(cmd.Context(), client, w.queryURL, w.roundID)
+			baseline = err == nil
 		}
 
-		// End load test time tracking
-		elapsed := time.Since(start)
+		// Ctrl+C stops the test and still prints the report
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		rec := newRecorder()
+		elapsed := execute(ctx, client, p, concurrent, w.next, rec)
+		report := rec.report(p.kind, elapsed)
+
+		if verifyVotes {
+			if output == OutputText {
+				fmt.Fprintf(cmd.OutOrStdout(), "\n 🔎 [VERIFY] Aguardando %s para os votos serem contabilizados...\n", settle)
+			}
+
+			select {
+			case <-cmd.Context().Done():
+				return cmd.Context().Err()
+			case <-time.After(settle):
+			}
+
+			after, err := fetchSnapshot(cmd.Context(), client, w.queryURL, w.roundID)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrVerificationFailed, err)
+			}
 
-		// Se o teste demorar mais de 1 segundo, exibe um aviso.
-		if elapsed.Seconds() > 1 {
-			fmt.Printf("\n⚠️ - [FINISHED LOAD TEST] O teste de carga demorou mais de 1 segundo:\n\n- ⏰ Tempo: %f segundos\n- 📈 Throughput: %f req/s\n\n", elapsed.Seconds(), calculateThroughput(maxIncrements, elapsed))
-			return
+			v := verify(before, after, rec.acceptedVotes())
+			v.Settle, v.Baseline = settle.Seconds(), baseline
+			report.Verification = &v
 		}
-		fmt.Printf("\n✅ [FINISHED LOAD TEST] Teste de carga finalizado:\n\n- ⏰ Tempo: %f segundos\n- 📈 Throughput: %f req/s\n\n", elapsed.Seconds(), calculateThroughput(maxIncrements, elapsed))
 
+		if output == OutputJSON {
+			if err := report.writeJSON(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+		} else {
+			report.writeText(cmd.OutOrStdout())
+		}
+
+		if report.Verification != nil && !report.Verification.OK {
+			return ErrVerificationFailed
+		}
+		return nil
 	}
 
 	return &c
 }
-
-func calculateThroughput(requests int, duration time.Duration) float64 {
-	seconds := duration.Seconds()
-	if seconds == 0 {
-		return 0
-	}
-	return (float64(requests) / seconds)
-}
@@ -0,0 +1,211 @@
+package loadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sergiodii/bbb/pkg/apitest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves the command and the query routes of a round, counting the votes in memory.
+// It answers 201 to every vote but only counts the ones kept by keep, to simulate lost votes.
+type fakeAPI struct {
+	m            sync.Mutex
+	votes        int
+	participants map[string]int
+	keep         func(n int) bool
+}
+
+func newFakeAPI(t *testing.T, roundID string, keep func(n int) bool) (*fakeAPI, *httptest.Server) {
+	api := &fakeAPI{participants: map[string]int{}, keep: keep}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /"+roundID, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ParticipantID string `json:"participant_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		api.m.Lock()
+		defer api.m.Unlock()
+		api.votes++
+		if api.keep(api.votes) {
+			api.participants[body.ParticipantID]++
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /"+roundID, func(w http.ResponseWriter, r *http.Request) {
+		api.m.Lock()
+		defer api.m.Unlock()
+		total := 0
+		for _, votes := range api.participants {
+			total += votes
+		}
+		json.NewEncoder(w).Encode(map[string]int{"total": total})
+	})
+	mux.HandleFunc("GET /"+roundID+"/participant", func(w http.ResponseWriter, r *http.Request) {
+		api.m.Lock()
+		defer api.m.Unlock()
+		json.NewEncoder(w).Encode(api.participants)
+	})
+	mux.HandleFunc("GET /"+roundID+"/hour", func(w http.ResponseWriter, r *http.Request) {
+		api.m.Lock()
+		defer api.m.Unlock()
+		total := 0
+		for _, votes := range api.participants {
+			total += votes
+		}
+		json.NewEncoder(w).Encode(map[string]int{"490000": total})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func runVerify(t *testing.T, commandURL, queryURL string) (Report, error) {
+	var out bytes.Buffer
+	cmd := LoadTestCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{
+		"--url", commandURL, "--query-url", queryURL, "--round-id", "round1",
+		"--rate", "1000", "--requests", "100", "--verify", "--settle", "10ms", "--output", "json",
+	})
+
+	err := cmd.Execute()
+
+	var report Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.NotNil(t, report.Verification)
+	return report, err
+}
+
+func TestLoadTestCommand(t *testing.T) {
+	t.Run("Should send the votes and the queries and report them in JSON", func(t *testing.T) {
+		// Arrange
+		var (
+			m     sync.Mutex
+			votes = map[string]int{}
+			paths = map[string]int{}
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.Lock()
+			defer m.Unlock()
+			if r.Method == http.MethodPost {
+				var body struct {
+					ParticipantID string `json:"participant_id"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				votes[body.ParticipantID]++
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			paths[r.URL.Path]++
+		}))
+		defer server.Close()
+
+		var out bytes.Buffer
+		cmd := LoadTestCommand()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{
+			"--url", server.URL, "--query-url", strings.TrimPrefix(server.URL, "http://"),
+			"--round-id", "round7", "--rate", "1000", "--duration", "1s", "--requests", "200",
+			"--distribution", "weighted", "--weights", "alice=1,bob=1",
+			"--read-ratio", "0.5", "--seed", "42", "--output", "json",
+		})
+
+		// Act
+		err := cmd.Execute()
+
+		// Assert
+		require.NoError(t, err)
+		var report Report
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		assert.Equal(t, ProfileConstant, report.Profile)
+		assert.Equal(t, 200, report.Requests+report.Dropped)
+		assert.Zero(t, report.Errors)
+
+		vote, query := report.Operations[OperationVote], report.Operations[OperationQuery]
+		assert.Equal(t, vote.Status["201"], vote.Requests)
+		assert.Equal(t, query.Status["200"], query.Requests)
+		assert.Equal(t, vote.Requests, votes["alice"]+votes["bob"])
+		assert.Equal(t, query.Requests, paths["/round7"]+paths["/round7/participant"]+paths["/round7/hour"])
+		assert.NotZero(t, vote.Requests)
+		assert.NotZero(t, query.Requests)
+	})
+
+	t.Run("Should verify that every accepted vote was counted", func(t *testing.T) {
+		// Arrange
+		api, server := newFakeAPI(t, "round1", func(int) bool { return true })
+		api.participants["banana"] = 10
+
+		// Act
+		report, err := runVerify(t, server.URL, server.URL)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, report.Verification.OK)
+		assert.True(t, report.Verification.Baseline)
+		assert.Equal(t, report.Operations[OperationVote].Status["201"], report.Verification.Accepted)
+	})
+
+	t.Run("Should fail the verification when votes are lost", func(t *testing.T) {
+		// Arrange
+		_, server := newFakeAPI(t, "round1", func(n int) bool { return n%10 != 0 })
+
+		// Act
+		report, err := runVerify(t, server.URL, server.URL)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrVerificationFailed)
+		assert.False(t, report.Verification.OK)
+		assert.Contains(t, report.Verification.Mismatches, Mismatch{
+			Counter:  CounterTotal,
+			Expected: report.Verification.Accepted,
+			Actual:   report.Verification.Accepted - report.Verification.Accepted/10,
+		})
+	})
+
+	t.Run("Should verify the votes counted by the whole API", func(t *testing.T) {
+		// Arrange
+		srv := apitest.Start(t, apitest.WithBackend(apitest.BackendMiniredis))
+
+		// Act
+		report, err := runVerify(t, srv.CommandURL(), srv.QueryURL())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, report.Verification.OK)
+		assert.Equal(t, 100, report.Verification.Accepted+report.Dropped)
+	})
+
+	t.Run("Should reject an invalid flag value", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--profile", "spike"},
+			{"--distribution", "normal"},
+			{"--output", "xml"},
+			{"--read-ratio", "2"},
+			{"--concurrent", "0"},
+		} {
+			// Arrange
+			cmd := LoadTestCommand()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(args)
+
+			// Act
+			err := cmd.Execute()
+
+			// Assert
+			assert.Error(t, err, args)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// queryPaths are the queries of the mixed workload, relative to the round
+var queryPaths = []string{"", "/participant", "/hour"}
+
+// request is a request of the workload
+type request struct {
+	operation   string
+	method      string
+	url         string
+	body        string
+	participant string
+}
+
+// workload generates the requests: votes to the command API and, with the read ratio, queries to the query API
+type workload struct {
+	commandURL string
+	queryURL   string
+	roundID    string
+	readRatio  float64
+	pick       func() string
+	rng        *rand.Rand
+}
+
+// next returns the next request, it must be called by a single goroutine
+func (w *workload) next() request {
+	if w.readRatio > 0 && w.rng.Float64() < w.readRatio {
+		path := queryPaths[w.rng.IntN(len(queryPaths))]
+		return request{
+			operation: OperationQuery,
+			method:    http.MethodGet,
+			url:       w.queryURL + "/" + w.roundID + path,
+		}
+	}
+
+	participant := w.pick()
+	return request{
+		operation:   OperationVote,
+		method:      http.MethodPost,
+		url:         w.commandURL + "/" + w.roundID,
+		body:        fmt.Sprintf(`{"participant_id": %q}`, participant),
+		participant: participant,
+	}
+}
+
+// baseURL adds the http scheme to an address without one
+func baseURL(addr string) string {
+	if strings.Contains(addr, "://") {
+		return strings.TrimSuffix(addr, "/")
+	}
+	return "http://" + strings.TrimSuffix(addr, "/")
+}
+
+// execute sends the requests of the workload following the schedule of the profile (open-loop).
+// A request is dropped when maxInFlight requests are still waiting for a response, so a slow API
+// does not slow down the load. When the context is done no request is sent anymore, but the
+// ones in flight are awaited. It returns the elapsed time.
+func execute(ctx context.Context, client *http.Client, p profile, maxInFlight int, next func() request, rec *recorder) time.Duration {
+	slots := make(chan struct{}, maxInFlight)
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for at := range p.schedule() {
+		timer.Reset(time.Until(start.Add(at)))
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return time.Since(start)
+		case <-timer.C:
+		}
+
+		req := next()
+		select {
+		case slots <- struct{}{}:
+		default:
+			rec.drop()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			rec.record(send(client, req))
+		}()
+	}
+
+	wg.Wait()
+	return time.Since(start)
+}
+
+// send sends the request and measures its latency
+func send(client *http.Client, req request) result {
+	res := result{operation: req.operation, participant: req.participant}
+
+	httpReq, err := http.NewRequest(req.method, req.url, strings.NewReader(req.body))
+	if err != nil {
+		res.err = err
+		return res
+	}
+	if req.body != "" {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		res.latency, res.err = time.Since(start), err
+		return res
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	res.latency, res.status = time.Since(start), resp.StatusCode
+	return res
+}
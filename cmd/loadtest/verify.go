@@ -0,0 +1,159 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+// ErrVerificationFailed is returned when the counters of the query API do not match the accepted votes
+var ErrVerificationFailed = errors.New("verification failed")
+
+// The counters compared by the verification
+const (
+	CounterTotal       = "total"
+	CounterHour        = "hour"
+	CounterParticipant = "participant"
+)
+
+// snapshot are the counters of a round read from the query API
+type snapshot struct {
+	total        int
+	participants map[string]int
+
+	// hours is the sum of the votes per hour
+	hours int
+}
+
+// fetchSnapshot reads the counters of the round from /:round_id, /:round_id/participant and /:round_id/hour
+func fetchSnapshot(ctx context.Context, client *http.Client, queryURL, roundID string) (snapshot, error) {
+	var (
+		s     snapshot
+		total struct {
+			Total int `json:"total"`
+		}
+		hours map[string]int
+	)
+
+	base := queryURL + "/" + roundID
+	if err := getJSON(ctx, client, base, &total); err != nil {
+		return s, err
+	}
+	if err := getJSON(ctx, client, base+"/participant", &s.participants); err != nil {
+		return s, err
+	}
+	if err := getJSON(ctx, client, base+"/hour", &hours); err != nil {
+		return s, err
+	}
+
+	s.total = total.Total
+	for _, votes := range hours {
+		s.hours += votes
+	}
+	return s, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %s: unexpected status %d: %s", url, resp.StatusCode, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Verification compares the votes accepted by the command API (201) with the counters of the query API
+type Verification struct {
+	OK bool `json:"ok"`
+
+	// Settle is how long the verification waited for the votes to be counted
+	Settle float64 `json:"settle_seconds"`
+
+	// Baseline is false when the counters could not be read before the test, e.g. a new round,
+	// and the round is assumed to be empty
+	Baseline bool `json:"baseline"`
+
+	Accepted   int        `json:"accepted"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+// Mismatch is a counter of the query API that does not match the accepted votes
+type Mismatch struct {
+	Counter     string `json:"counter"`
+	Participant string `json:"participant,omitempty"`
+	Expected    int    `json:"expected"`
+	Actual      int    `json:"actual"`
+}
+
+// verify compares the counters after the test with the counters before it plus the accepted votes
+func verify(before, after snapshot, accepted map[string]int) Verification {
+	var v Verification
+
+	expected := maps.Clone(before.participants)
+	if expected == nil {
+		expected = map[string]int{}
+	}
+	for participant, votes := range accepted {
+		expected[participant] += votes
+		v.Accepted += votes
+	}
+
+	if want := before.total + v.Accepted; after.total != want {
+		v.Mismatches = append(v.Mismatches, Mismatch{Counter: CounterTotal, Expected: want, Actual: after.total})
+	}
+	if want := before.hours + v.Accepted; after.hours != want {
+		v.Mismatches = append(v.Mismatches, Mismatch{Counter: CounterHour, Expected: want, Actual: after.hours})
+	}
+
+	participants := slices.Collect(maps.Keys(expected))
+	for participant := range after.participants {
+		if _, ok := expected[participant]; !ok {
+			participants = append(participants, participant)
+		}
+	}
+	slices.Sort(participants)
+	for _, participant := range participants {
+		if want, got := expected[participant], after.participants[participant]; want != got {
+			v.Mismatches = append(v.Mismatches, Mismatch{Counter: CounterParticipant, Participant: participant, Expected: want, Actual: got})
+		}
+	}
+
+	v.OK = len(v.Mismatches) == 0
+	return v
+}
+
+// writeText writes the verification for humans
+func (v Verification) writeText(w io.Writer) {
+	if v.OK {
+		fmt.Fprintf(w, "✅ [VERIFY] %d votos aceitos (201) e contabilizados na API de consultas\n", v.Accepted)
+	} else {
+		fmt.Fprintf(w, "❌ [VERIFY] %d divergências entre os %d votos aceitos (201) e a API de consultas:\n", len(v.Mismatches), v.Accepted)
+	}
+	if !v.Baseline {
+		fmt.Fprintln(w, "   ⚠️ contadores da rodada indisponíveis antes do teste, a rodada foi considerada vazia")
+	}
+
+	for _, m := range v.Mismatches {
+		counter := m.Counter
+		if m.Participant != "" {
+			counter += " " + m.Participant
+		}
+		fmt.Fprintf(w, "   - %s: esperado %d, obtido %d (%+d)\n", counter, m.Expected, m.Actual, m.Actual-m.Expected)
+	}
+	fmt.Fprintln(w)
+}
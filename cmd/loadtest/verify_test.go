@@ -0,0 +1,39 @@
+package loadtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	t.Run("Should match the counters with the baseline plus the accepted votes", func(t *testing.T) {
+		// Arrange
+		before := snapshot{total: 3, participants: map[string]int{"alice": 3}, hours: 3}
+		after := snapshot{total: 8, participants: map[string]int{"alice": 5, "bob": 3}, hours: 8}
+
+		// Act
+		v := verify(before, after, map[string]int{"alice": 2, "bob": 3})
+
+		// Assert
+		assert.True(t, v.OK)
+		assert.Equal(t, 5, v.Accepted)
+		assert.Empty(t, v.Mismatches)
+	})
+
+	t.Run("Should report the lost votes and the unexpected participants", func(t *testing.T) {
+		// Arrange
+		after := snapshot{total: 4, participants: map[string]int{"alice": 1, "bob": 2, "mallory": 1}, hours: 5}
+
+		// Act
+		v := verify(snapshot{}, after, map[string]int{"alice": 3, "bob": 2})
+
+		// Assert
+		assert.False(t, v.OK)
+		assert.Equal(t, []Mismatch{
+			{Counter: CounterTotal, Expected: 5, Actual: 4},
+			{Counter: CounterParticipant, Participant: "alice", Expected: 3, Actual: 1},
+			{Counter: CounterParticipant, Participant: "mallory", Expected: 0, Actual: 1},
+		}, v.Mismatches)
+	})
+}
@@ -16,6 +16,10 @@ func Execute() {
 	rootCmd.AddCommand(api.ApiCommand())
 	rootCmd.AddCommand(api.QueryApiCommand())
 	rootCmd.AddCommand(api.CommandApiCommand())
+	rootCmd.AddCommand(api.GrpcApiCommand())
+	rootCmd.AddCommand(api.DeadLetterReplayCommand())
+	rootCmd.AddCommand(api.RedisMigrateCommand())
+	rootCmd.AddCommand(api.ConfigCommand())
 	rootCmd.AddCommand(loadtest.LoadTestCommand())
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
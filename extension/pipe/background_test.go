@@ -0,0 +1,182 @@
+package pipe
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/extension/channel"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundTasks(t *testing.T) {
+	t.Run("Should report the errors of the non-blocking tasks to the error sink", func(t *testing.T) {
+		// Arrange
+		errs := channel.NewSafeChannel[error](1)
+		pipe, err := New[int](SEQUENTIAL_BLOCKING_ONLY_FIRST, WithErrorSink(NewChannelErrorSink(errs)))
+		assert.NoError(t, err)
+
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, assert.AnError
+		})
+
+		// Act
+		_, err = pipe.Execute(context.Background(), 7)
+		reported := <-errs.Receive()
+
+		// Assert
+		assert.NoError(t, err)
+
+		var taskErr *TaskError
+		assert.True(t, errors.As(reported, &taskErr))
+		assert.Equal(t, 1, taskErr.Index)
+		assert.Equal(t, 7, taskErr.Input)
+		assert.ErrorIs(t, reported, assert.AnError)
+	})
+
+	t.Run("Should retry the non-blocking tasks before reporting them", func(t *testing.T) {
+		// Arrange
+		var attempts atomic.Int32
+		done := make(chan error)
+		pipe, _ := New[int](SEQUENTIAL_BLOCKING_ONLY_FIRST,
+			WithBackgroundRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+			WithErrorSink(func(ctx context.Context, err error) { done <- err }),
+		)
+
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			if attempts.Add(1) < 3 {
+				return i, assert.AnError
+			}
+			close(done)
+			return i, nil
+		})
+
+		// Act
+		_, err := pipe.Execute(context.Background(), 1)
+		reported := <-done
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, reported)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("Should limit the number of non-blocking tasks running at the same time", func(t *testing.T) {
+		// Arrange
+		release := make(chan struct{})
+		var running, peak atomic.Int32
+		reported := make(chan error, 1)
+
+		pipe, _ := New[int](SEQUENTIAL_BLOCKING_ONLY_FIRST,
+			WithMaxBackground(2),
+			WithErrorSink(func(ctx context.Context, err error) { reported <- err }),
+		)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			n := running.Add(1)
+			if n > peak.Load() {
+				peak.Store(n)
+			}
+			<-release
+			running.Add(-1)
+			return i, nil
+		})
+
+		// Act
+		pipe.Execute(context.Background(), 1)
+		pipe.Execute(context.Background(), 2)
+		assert.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond)
+
+		start := time.Now()
+		_, err := pipe.Execute(context.Background(), 3) // there is no free slot, so the task is reported without waiting
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+		var taskErr *TaskError
+		assert.ErrorAs(t, <-reported, &taskErr)
+		assert.ErrorIs(t, taskErr, ErrBackgroundFull)
+		assert.Equal(t, 3, taskErr.Input)
+		close(release)
+		assert.Equal(t, int32(2), peak.Load())
+	})
+
+	t.Run("Should not start the non-blocking tasks when the first task fails", func(t *testing.T) {
+		// Arrange
+		var started atomic.Int32
+		pipe, _ := New[int](SEQUENTIAL_BLOCKING_ONLY_FIRST)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			if started.Load() != 0 {
+				t.Error("the non-blocking task started before the first task")
+			}
+			return i, assert.AnError
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			started.Add(1)
+			return i, nil
+		})
+
+		// Act
+		_, err := pipe.Execute(context.Background(), 1)
+		waitErr := Wait(context.Background(), pipe)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, waitErr)
+		assert.Zero(t, started.Load())
+	})
+
+	t.Run("Should wait for the non-blocking tasks", func(t *testing.T) {
+		// Arrange
+		release := make(chan struct{})
+		var finished atomic.Int32
+		SetDefaultObserver(NewMultiObserver())
+		defer SetDefaultObserver(nil)
+
+		pipe, _ := New[int](SEQUENTIAL_BLOCKING_ONLY_FIRST)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			<-release
+			finished.Add(1)
+			return i, nil
+		})
+		pipe.Execute(context.Background(), 1)
+
+		// Act
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		timeoutErr := Wait(ctx, pipe)
+
+		close(release)
+		err := Wait(context.Background(), pipe)
+
+		// Assert
+		assert.ErrorIs(t, timeoutErr, context.DeadlineExceeded)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), finished.Load())
+	})
+
+	t.Run("Should not wait for the pipes without background tasks", func(t *testing.T) {
+		// Arrange
+		pipe, _ := New[int](SEQUENTIAL)
+
+		// Act
+		err := Wait(context.Background(), pipe)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,90 @@
+package pipe
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by the CircuitBreaker decorator while the breaker is open.
+// It wraps ONF, so the pipes skip the task and the next one answers. The tasks that must not be skipped,
+// such as the writes, must return an error that does not match ONF instead.
+var ErrCircuitOpen = errors.Join(errors.New("circuit breaker is open"), ONF)
+
+// BreakerPolicy configures a circuit breaker
+type BreakerPolicy struct {
+	// Threshold is the number of consecutive failures that opens the breaker
+	Threshold int
+
+	// Cooldown is the time the breaker stays open before letting a trial call through
+	Cooldown time.Duration
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// Breaker is a circuit breaker shared by every execution of a task.
+// After Threshold consecutive failures it opens and rejects the calls during the Cooldown,
+// then a single trial call is let through: if it succeeds the breaker closes, otherwise it opens again.
+type Breaker struct {
+	policy   BreakerPolicy
+	m        sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow returns true when the call can go through
+func (b *Breaker) allow() bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.policy.Cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// only the trial call goes through while half open
+		return false
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the result of a call
+func (b *Breaker) record(err error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err == nil || errors.Is(err, ONF) {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.policy.Threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// IsOpen returns true while the breaker is rejecting the calls
+func (b *Breaker) IsOpen() bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.state != breakerClosed
+}
+
+// NewBreaker creates a closed circuit breaker
+func NewBreaker(policy BreakerPolicy) *Breaker {
+	return &Breaker{policy: policy}
+}
@@ -0,0 +1,134 @@
+package pipe
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// inFlight counts the tasks running at the same time and keeps the maximum
+type inFlight struct {
+	running atomic.Int32
+	max     atomic.Int32
+}
+
+func (f *inFlight) task(ctx context.Context, i int) (int, error) {
+	n := f.running.Add(1)
+	defer f.running.Add(-1)
+	for {
+		m := f.max.Load()
+		if n <= m || f.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return i, nil
+}
+
+func TestConcurrency(t *testing.T) {
+	for _, executionType := range []ExecutionType{CONCURRENT, CONCURRENT_MERGE, QUORUM} {
+		t.Run("Should limit the tasks running at the same time in "+executionType.String(), func(t *testing.T) {
+			// Arrange
+			var f inFlight
+			pipe, err := New[int](executionType, WithConcurrency(3), WithReducer(func(input int, outputs []int) (int, error) {
+				return len(outputs), nil
+			}))
+			assert.NoError(t, err)
+			for range 20 {
+				pipe.Enqueue(f.task)
+			}
+
+			// Act
+			_, err = pipe.Execute(context.Background(), 1)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, f.max.Load(), int32(3))
+			assert.Greater(t, f.max.Load(), int32(1))
+		})
+	}
+
+	t.Run("Should run every task at the same time without limit", func(t *testing.T) {
+		// Arrange
+		var f inFlight
+		pipe, _ := New[int](CONCURRENT, WithConcurrency(0))
+		for range 20 {
+			pipe.Enqueue(f.task)
+		}
+
+		// Act
+		_, err := pipe.Execute(context.Background(), 1)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Greater(t, f.max.Load(), int32(10))
+	})
+
+	t.Run("Should cancel the other tasks on the first error", func(t *testing.T) {
+		// Arrange
+		var cancelled atomic.Bool
+		pipe, _ := New[int](CONCURRENT, WithCancelOnError())
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, assert.AnError
+		}, func(ctx context.Context, i int) (int, error) {
+			select {
+			case <-ctx.Done():
+				cancelled.Store(true)
+			case <-time.After(time.Second):
+			}
+			return i, nil
+		})
+
+		// Act
+		_, err := pipe.Execute(context.Background(), 1)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, cancelled.Load())
+	})
+
+	t.Run("Should run the other tasks until the end without cancel on error", func(t *testing.T) {
+		// Arrange
+		var finished atomic.Bool
+		pipe, _ := New[int](CONCURRENT)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, assert.AnError
+		}, func(ctx context.Context, i int) (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			finished.Store(ctx.Err() == nil)
+			return i, nil
+		})
+
+		// Act
+		_, err := pipe.Execute(context.Background(), 1)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, finished.Load())
+	})
+
+	t.Run("Should not start the tasks left when the context is done", func(t *testing.T) {
+		// Arrange
+		var started atomic.Int32
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pipe, _ := New[int](CONCURRENT, WithConcurrency(1))
+		for range 5 {
+			pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+				started.Add(1)
+				cancel()
+				return i, nil
+			})
+		}
+
+		// Act
+		_, err := pipe.Execute(ctx, 1)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, started.Load(), int32(5))
+	})
+}
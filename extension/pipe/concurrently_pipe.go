@@ -4,20 +4,21 @@ import (
 	"context"
 	"errors"
 
-	"github.com/sergiodii/bbb/extension/slice"
 	"golang.org/x/sync/errgroup"
 )
 
 type concurrentlyPipe[T any] struct {
 	tasks []func(context.Context, T) (T, error)
+	opts  options
 }
 
 // NewConcurrentlyPipe creates a new Pipe that executes tasks concurrently
 // Concurrently means that all tasks will be executed at the same time
 // and the output of one task will not be the input of the next task
 // The input of all tasks will be the same
+// At most 10 tasks run at the same time, use New with WithConcurrency to change the limit
 func NewConcurrentlyPipe[T any](tasks ...func(context.Context, T) (T, error)) Pipe[T] {
-	pipe := &concurrentlyPipe[T]{}
+	pipe := &concurrentlyPipe[T]{opts: newOptions()}
 
 	if len(tasks) == 0 {
 		return pipe
@@ -32,27 +33,41 @@ func (p *concurrentlyPipe[T]) Enqueue(tasks ...func(context.Context, T) (T, erro
 }
 
 func (p *concurrentlyPipe[T]) Execute(ctx context.Context, input T) (T, error) {
-	var eg errgroup.Group
-	for _, taskList := range slice.TransformSliceToMultipleSlices(p.tasks, 10) {
-		// this loop is to limit the number of goroutines running concurrently
-		// to avoid overwhelming the system
-		// you can adjust the number 10 to a value that makes sense for your use case
-		for _, task := range taskList {
-			eg.Go(func() error {
-				_, err := task(ctx, input)
-
-				// if the error is ObjectNotFound, continue to the next task
-				if errors.Is(err, ONF) {
-					return nil
-				}
-				return err
-			})
+	eg, taskCtx := &errgroup.Group{}, ctx
+	if p.opts.cancelOnError {
+		// the context of the tasks is cancelled when a task fails
+		eg, taskCtx = errgroup.WithContext(ctx)
+	}
+
+	// the number of goroutines running at the same time is limited to avoid overwhelming the system,
+	// Go blocks until a running task finishes, see WithConcurrency
+	eg.SetLimit(p.opts.limit())
+
+	var skipped error
+	for _, task := range p.tasks {
+		if skipped = taskCtx.Err(); skipped != nil {
+			// the context is done, the tasks not started yet are skipped
+			break
 		}
+
+		eg.Go(func() error {
+			_, err := task(taskCtx, input)
+
+			// if the error is ObjectNotFound, continue to the next task
+			if errors.Is(err, ONF) {
+				return nil
+			}
+			return err
+		})
 	}
 
 	if err := eg.Wait(); err != nil {
 		return input, err
 	}
 
+	if skipped != nil {
+		return input, skipped
+	}
+
 	return input, nil
 }
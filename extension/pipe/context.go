@@ -0,0 +1,19 @@
+package pipe
+
+import "context"
+
+type originKey struct{}
+
+// detach returns a context for a fire-and-forget task.
+// The context keeps the values of the request (trace, request id...) but it is not cancelled when the request finishes.
+// The original context can be retrieved with Origin.
+func detach(ctx context.Context) context.Context {
+	return context.WithValue(context.WithoutCancel(ctx), originKey{}, ctx)
+}
+
+// Origin returns the context of the request that launched a fire-and-forget task.
+// The boolean is false when the task is not running detached from its request.
+func Origin(ctx context.Context) (context.Context, bool) {
+	origin, ok := ctx.Value(originKey{}).(context.Context)
+	return origin, ok
+}
@@ -0,0 +1,114 @@
+package pipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTaskTimeout is returned by the Timeout decorator when the task takes too long.
+// It wraps context.DeadlineExceeded.
+var ErrTaskTimeout = fmt.Errorf("task timeout: %w", context.DeadlineExceeded)
+
+// Decorator wraps a task to add a behaviour around it, like a timeout or retries
+type Decorator[T any] func(func(context.Context, T) (T, error)) func(context.Context, T) (T, error)
+
+// Decorate applies the decorators to the task.
+// The first decorator is the outermost one, so Decorate(task, a, b) is a(b(task)).
+func Decorate[T any](task func(context.Context, T) (T, error), decorators ...Decorator[T]) func(context.Context, T) (T, error) {
+	for i := len(decorators) - 1; i >= 0; i-- {
+		task = decorators[i](task)
+	}
+	return task
+}
+
+// Timeout cancels the context of the task after d and returns ErrTaskTimeout.
+// The decorator returns on time even when the task does not honour the context.
+func Timeout[T any](d time.Duration) Decorator[T] {
+	return func(task func(context.Context, T) (T, error)) func(context.Context, T) (T, error) {
+		return func(ctx context.Context, input T) (T, error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			type result struct {
+				output T
+				err    error
+			}
+			ch := make(chan result, 1)
+			go func() {
+				output, err := task(ctx, input)
+				ch <- result{output, err}
+			}()
+
+			select {
+			case r := <-ch:
+				return r.output, r.err
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return input, ErrTaskTimeout
+				}
+				return input, ctx.Err()
+			}
+		}
+	}
+}
+
+// Retry executes the task again when it fails, following the policy
+func Retry[T any](policy RetryPolicy) Decorator[T] {
+	return func(task func(context.Context, T) (T, error)) func(context.Context, T) (T, error) {
+		return func(ctx context.Context, input T) (T, error) {
+			return retry(ctx, policy, func() (T, error) {
+				return task(ctx, input)
+			})
+		}
+	}
+}
+
+// CircuitBreaker stops calling the task while the breaker is open, returning ErrCircuitOpen.
+// As ErrCircuitOpen wraps ONF, the pipes move on to the next task.
+func CircuitBreaker[T any](b *Breaker) Decorator[T] {
+	return func(task func(context.Context, T) (T, error)) func(context.Context, T) (T, error) {
+		return func(ctx context.Context, input T) (T, error) {
+			if !b.allow() {
+				return input, ErrCircuitOpen
+			}
+
+			output, err := task(ctx, input)
+			b.record(err)
+			return output, err
+		}
+	}
+}
+
+// TaskPolicy groups the decorators applied to a task.
+// The zero value does not change the task.
+type TaskPolicy struct {
+	// Timeout of each attempt, zero means no timeout
+	Timeout time.Duration
+
+	// Retry policy, nil means no retry
+	Retry *RetryPolicy
+
+	// Breaker policy, nil means no circuit breaker
+	Breaker *BreakerPolicy
+}
+
+// ApplyPolicy decorates the task following the policy.
+// The circuit breaker wraps the retries, that wrap the timeout of each attempt.
+// A new Breaker is created on each call, so each task has its own breaker.
+func ApplyPolicy[T any](task func(context.Context, T) (T, error), policy TaskPolicy) func(context.Context, T) (T, error) {
+	var decorators []Decorator[T]
+
+	if policy.Breaker != nil {
+		decorators = append(decorators, CircuitBreaker[T](NewBreaker(*policy.Breaker)))
+	}
+	if policy.Retry != nil {
+		decorators = append(decorators, Retry[T](*policy.Retry))
+	}
+	if policy.Timeout > 0 {
+		decorators = append(decorators, Timeout[T](policy.Timeout))
+	}
+
+	return Decorate(task, decorators...)
+}
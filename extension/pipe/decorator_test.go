@@ -0,0 +1,184 @@
+package pipe
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorators(t *testing.T) {
+	t.Run("Should apply the decorators from the outermost to the innermost", func(t *testing.T) {
+		// Arrange
+		var calls []string
+		decorator := func(name string) Decorator[int] {
+			return func(task func(context.Context, int) (int, error)) func(context.Context, int) (int, error) {
+				return func(ctx context.Context, i int) (int, error) {
+					calls = append(calls, name)
+					return task(ctx, i)
+				}
+			}
+		}
+		task := Decorate(func(ctx context.Context, i int) (int, error) {
+			calls = append(calls, "task")
+			return i, nil
+		}, decorator("a"), decorator("b"))
+
+		// Act
+		_, err := task(context.Background(), 1)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "task"}, calls)
+	})
+
+	t.Run("Should return ErrTaskTimeout when the task is too slow", func(t *testing.T) {
+		// Arrange
+		task := Timeout[int](10 * time.Millisecond)(func(ctx context.Context, i int) (int, error) {
+			time.Sleep(200 * time.Millisecond)
+			return i + 1, nil
+		})
+
+		// Act
+		start := time.Now()
+		result, err := task(context.Background(), 1)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrTaskTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, result)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("Should retry only the configured errors", func(t *testing.T) {
+		// Arrange
+		transient := errors.New("transient")
+		attempts := 0
+		task := Retry[int](RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			Jitter:         0.5,
+			RetryOn:        RetryOnErrors(transient),
+		})(func(ctx context.Context, i int) (int, error) {
+			attempts++
+			if attempts == 1 {
+				return i, transient
+			}
+			return i, assert.AnError
+		})
+
+		// Act
+		_, err := task(context.Background(), 1)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("Should keep the jittered backoff around the exponential backoff", func(t *testing.T) {
+		// Arrange
+		policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Jitter: 0.2}
+
+		// Act & Assert
+		for range 100 {
+			d := policy.backoff(2)
+			assert.GreaterOrEqual(t, d, 160*time.Millisecond)
+			assert.LessOrEqual(t, d, 240*time.Millisecond)
+		}
+	})
+
+	t.Run("Should open the circuit after the threshold and let the next task answer", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		breaker := NewBreaker(BreakerPolicy{Threshold: 2, Cooldown: time.Hour})
+		pipe := NewSequentiallyWithFirstResultPipe[int](
+			CircuitBreaker[int](breaker)(func(ctx context.Context, i int) (int, error) {
+				calls++
+				return i, assert.AnError
+			}),
+			func(ctx context.Context, i int) (int, error) {
+				return i * 10, nil
+			},
+		)
+
+		// Act
+		var results []int
+		for range 4 {
+			result, err := pipe.Execute(context.Background(), 1)
+			assert.NoError(t, err)
+			results = append(results, result)
+		}
+
+		// Assert
+		assert.Equal(t, 2, calls)
+		assert.True(t, breaker.IsOpen())
+		assert.Equal(t, []int{10, 10, 10, 10}, results)
+	})
+
+	t.Run("Should close the circuit when the trial call succeeds after the cooldown", func(t *testing.T) {
+		// Arrange
+		fail := true
+		breaker := NewBreaker(BreakerPolicy{Threshold: 1, Cooldown: 10 * time.Millisecond})
+		task := CircuitBreaker[int](breaker)(func(ctx context.Context, i int) (int, error) {
+			if fail {
+				return i, assert.AnError
+			}
+			return i, nil
+		})
+
+		// Act
+		_, firstErr := task(context.Background(), 1)
+		_, openErr := task(context.Background(), 1)
+		time.Sleep(20 * time.Millisecond)
+		fail = false
+		_, trialErr := task(context.Background(), 1)
+
+		// Assert
+		assert.ErrorIs(t, firstErr, assert.AnError)
+		assert.ErrorIs(t, openErr, ErrCircuitOpen)
+		assert.ErrorIs(t, openErr, ONF)
+		assert.NoError(t, trialErr)
+		assert.False(t, breaker.IsOpen())
+	})
+
+	t.Run("Should not count ObjectNotFound as a failure", func(t *testing.T) {
+		// Arrange
+		breaker := NewBreaker(BreakerPolicy{Threshold: 1, Cooldown: time.Hour})
+		task := CircuitBreaker[int](breaker)(func(ctx context.Context, i int) (int, error) {
+			return i, ONF
+		})
+
+		// Act
+		task(context.Background(), 1)
+
+		// Assert
+		assert.False(t, breaker.IsOpen())
+	})
+
+	t.Run("Should retry the timeouts of each attempt when applying a policy", func(t *testing.T) {
+		// Arrange
+		var attempts atomic.Int32
+		task := ApplyPolicy(func(ctx context.Context, i int) (int, error) {
+			if attempts.Add(1) == 1 {
+				<-ctx.Done()
+				return i, ctx.Err()
+			}
+			return i + 1, nil
+		}, TaskPolicy{
+			Timeout: 10 * time.Millisecond,
+			Retry:   &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, RetryOn: RetryOnErrors(ErrTaskTimeout)},
+			Breaker: &BreakerPolicy{Threshold: 1, Cooldown: time.Hour},
+		})
+
+		// Act
+		result, err := task(context.Background(), 1)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result)
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+}
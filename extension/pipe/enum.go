@@ -7,6 +7,8 @@ const (
 	CONCURRENT                     ExecutionType = "CONCURRENT"
 	SEQUENTIAL_WITH_FIRST_RESULT   ExecutionType = "SEQUENTIAL_WITH_FIRST_RESULT"
 	SEQUENTIAL_BLOCKING_ONLY_FIRST ExecutionType = "SEQUENTIAL_BLOCKING_ONLY_FIRST"
+	QUORUM                         ExecutionType = "QUORUM"
+	CONCURRENT_MERGE               ExecutionType = "CONCURRENT_MERGE"
 )
 
 func (e ExecutionType) String() string {
@@ -23,6 +25,10 @@ func ParseExecutionType(s string) ExecutionType {
 		return SEQUENTIAL_WITH_FIRST_RESULT
 	case SEQUENTIAL_BLOCKING_ONLY_FIRST.String():
 		return SEQUENTIAL_BLOCKING_ONLY_FIRST
+	case QUORUM.String():
+		return QUORUM
+	case CONCURRENT_MERGE.String():
+		return CONCURRENT_MERGE
 	default:
 		return ""
 	}
@@ -0,0 +1,41 @@
+package pipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sergiodii/bbb/extension/channel"
+)
+
+// ErrBackgroundFull is reported to the ErrorSink when a fire-and-forget task is not started
+// because the limit of tasks running at the same time was reached, see WithMaxBackground.
+var ErrBackgroundFull = errors.New("too many background tasks running")
+
+// TaskError is the error reported to the ErrorSink when a task fails.
+// It keeps the position of the task in the pipe and the input it received, so the work can be replayed later.
+type TaskError struct {
+	Index int
+	Input any
+	Err   error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.Index, e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorSink receives the errors that can not be returned to the caller of Execute,
+// like the errors of the fire-and-forget tasks. The errors are always a *TaskError.
+type ErrorSink func(ctx context.Context, err error)
+
+// NewChannelErrorSink creates an ErrorSink that sends the errors to a SafeChannel.
+// Errors sent after the channel is closed are dropped.
+func NewChannelErrorSink(ch channel.SafeChannel[error]) ErrorSink {
+	return func(ctx context.Context, err error) {
+		ch.Send(err)
+	}
+}
@@ -6,3 +6,18 @@ type Pipe[T any] interface {
 	Enqueue(...func(context.Context, T) (T, error))
 	Execute(context.Context, T) (T, error)
 }
+
+// Waiter is implemented by the pipes that run tasks in background, see SEQUENTIAL_BLOCKING_ONLY_FIRST.
+// Wait blocks until the background tasks started so far are done or the context is done.
+type Waiter interface {
+	Wait(context.Context) error
+}
+
+// Wait waits for the background tasks of the pipe.
+// It returns immediately when the pipe does not run tasks in background.
+func Wait[T any](ctx context.Context, p Pipe[T]) error {
+	if w, ok := p.(Waiter); ok {
+		return w.Wait(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package pipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidReducer is returned by New when a CONCURRENT_MERGE pipe has no reducer,
+// or when the reducer does not match the type of the pipe
+var ErrInvalidReducer = errors.New("invalid reducer")
+
+// ErrNotEnoughResults is returned by the CONCURRENT_MERGE pipe when less tasks than required succeeded
+var ErrNotEnoughResults = errors.New("not enough results")
+
+// Reducer combines the outputs of the tasks of a CONCURRENT_MERGE pipe.
+// It receives the input of the pipe and the outputs of the successful tasks, in the order the tasks were enqueued.
+type Reducer[T any] func(input T, outputs []T) (T, error)
+
+// FailurePolicy defines how a CONCURRENT_MERGE pipe handles the tasks that fail
+type FailurePolicy int
+
+const (
+	// FAIL_FAST cancels the other tasks and returns the error of the first task that fails
+	FAIL_FAST FailurePolicy = iota
+
+	// BEST_EFFORT merges the outputs of the tasks that succeeded and logs the errors of the others.
+	// The errors are returned only when every task failed.
+	BEST_EFFORT
+
+	// MIN_SUCCESS merges the outputs when at least the minimum number of tasks succeeded, see WithMinSuccess
+	MIN_SUCCESS
+)
+
+// WithReducer sets the function that combines the outputs of a CONCURRENT_MERGE pipe.
+// The reducer must have the same type of the pipe, otherwise New returns ErrInvalidReducer.
+func WithReducer[T any](reducer Reducer[T]) Option {
+	return func(o *options) {
+		o.reducer = reducer
+	}
+}
+
+// WithFailurePolicy sets how a CONCURRENT_MERGE pipe handles the tasks that fail. The default is FAIL_FAST.
+func WithFailurePolicy(policy FailurePolicy) Option {
+	return func(o *options) {
+		o.failurePolicy = policy
+	}
+}
+
+// WithMinSuccess sets the MIN_SUCCESS failure policy, requiring at least n successful tasks
+func WithMinSuccess(n int) Option {
+	return func(o *options) {
+		o.failurePolicy = MIN_SUCCESS
+		o.minSuccess = n
+	}
+}
+
+type mergeResult[T any] struct {
+	index  int
+	output T
+	err    error
+}
+
+type concurrentMergePipe[T any] struct {
+	tasks   []func(context.Context, T) (T, error)
+	opts    options
+	reducer Reducer[T]
+}
+
+// NewConcurrentMergePipe creates a new Pipe that executes tasks concurrently
+// and combines the outputs of every task with the reducer
+// The tasks that return an ONF (ObjectNotFound) error are ignored
+// If no task has an output, the input is returned without error
+// The failures are handled following the failure policy, see WithFailurePolicy
+func NewConcurrentMergePipe[T any](reducer Reducer[T], opts ...Option) Pipe[T] {
+	return newConcurrentMergePipe(newOptions(opts...), reducer)
+}
+
+func newConcurrentMergePipe[T any](opts options, reducer Reducer[T]) *concurrentMergePipe[T] {
+	return &concurrentMergePipe[T]{opts: opts, reducer: reducer}
+}
+
+func (p *concurrentMergePipe[T]) Enqueue(tasks ...func(context.Context, T) (T, error)) {
+	p.tasks = append(p.tasks, tasks...)
+}
+
+func (p *concurrentMergePipe[T]) Execute(ctx context.Context, input T) (T, error) {
+	if len(p.tasks) == 0 {
+		return input, nil
+	}
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the channel is buffered so the tasks never block when Execute returns early
+	results := make(chan mergeResult[T], len(p.tasks))
+
+	// slots limits the number of tasks running at the same time, see WithConcurrency
+	var slots chan struct{}
+	if limit := p.opts.limit(); limit > 0 {
+		slots = make(chan struct{}, limit)
+	}
+
+	for i, task := range p.tasks {
+		go func() {
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-taskCtx.Done():
+					results <- mergeResult[T]{index: i, output: input, err: taskCtx.Err()}
+					return
+				}
+			}
+
+			output, err := task(taskCtx, input)
+			results <- mergeResult[T]{index: i, output: output, err: err}
+		}()
+	}
+
+	var (
+		succeeded []mergeResult[T]
+		errs      []error
+	)
+	for range p.tasks {
+		select {
+		case <-ctx.Done():
+			return input, ctx.Err()
+		case r := <-results:
+			switch {
+			case r.err == nil:
+				succeeded = append(succeeded, r)
+			case errors.Is(r.err, ONF):
+				// the task has nothing to merge
+			case p.opts.failurePolicy == FAIL_FAST:
+				return input, r.err
+			default:
+				errs = append(errs, r.err)
+			}
+		}
+	}
+
+	if err := p.checkFailures(ctx, len(succeeded), errs); err != nil {
+		return input, err
+	}
+
+	if len(succeeded) == 0 {
+		// every task returned ObjectNotFound, there is nothing to merge
+		return input, nil
+	}
+
+	sort.Slice(succeeded, func(i, j int) bool { return succeeded[i].index < succeeded[j].index })
+	outputs := make([]T, len(succeeded))
+	for i, r := range succeeded {
+		outputs[i] = r.output
+	}
+
+	return p.reducer(input, outputs)
+}
+
+// checkFailures applies the failure policy once every task finished
+func (p *concurrentMergePipe[T]) checkFailures(ctx context.Context, succeeded int, errs []error) error {
+	if p.opts.failurePolicy == MIN_SUCCESS && succeeded < p.opts.minSuccess {
+		return errors.Join(append([]error{fmt.Errorf("%w: %d of %d", ErrNotEnoughResults, succeeded, p.opts.minSuccess)}, errs...)...)
+	}
+
+	if succeeded == 0 && len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if len(errs) > 0 {
+		// the outputs are merged anyway, so the errors are only logged
+		p.opts.logger.WarnContext(ctx, "merging partial results", "succeeded", succeeded, "failed", len(errs), "error", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package pipe
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sumMaps is the reducer used by the tests, it sums the per-participant maps
+func sumMaps(input map[string]int, outputs []map[string]int) (map[string]int, error) {
+	total := map[string]int{}
+	for _, output := range outputs {
+		for k, v := range output {
+			total[k] += v
+		}
+	}
+	return total, nil
+}
+
+func shard(m map[string]int) func(context.Context, map[string]int) (map[string]int, error) {
+	return func(ctx context.Context, input map[string]int) (map[string]int, error) {
+		return maps.Clone(m), nil
+	}
+}
+
+func failing(err error) func(context.Context, map[string]int) (map[string]int, error) {
+	return func(ctx context.Context, input map[string]int) (map[string]int, error) {
+		return input, err
+	}
+}
+
+func TestConcurrentMergePipe(t *testing.T) {
+	t.Run("Should merge the outputs of every task with the reducer", func(t *testing.T) {
+		// Arrange
+		pipe, err := New[map[string]int](CONCURRENT_MERGE, WithReducer(sumMaps))
+		assert.NoError(t, err)
+		pipe.Enqueue(shard(map[string]int{"alice": 1, "bob": 2}), shard(map[string]int{"alice": 3}), failing(ONF))
+
+		// Act
+		result, err := pipe.Execute(context.Background(), nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"alice": 4, "bob": 2}, result)
+	})
+
+	t.Run("Should give the outputs to the reducer in the order of the tasks", func(t *testing.T) {
+		// Arrange
+		pipe := NewConcurrentMergePipe(func(input int, outputs []int) (int, error) {
+			result := 0
+			for _, o := range outputs {
+				result = result*10 + o
+			}
+			return result, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			time.Sleep(20 * time.Millisecond)
+			return 1, nil
+		}, func(ctx context.Context, i int) (int, error) {
+			return 2, nil
+		})
+
+		// Act
+		result, err := pipe.Execute(context.Background(), 0)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 12, result)
+	})
+
+	t.Run("Should return an error when the reducer is missing or has another type", func(t *testing.T) {
+		// Act
+		_, missingErr := New[int](CONCURRENT_MERGE)
+		_, typeErr := New[int](CONCURRENT_MERGE, WithReducer(sumMaps))
+
+		// Assert
+		assert.ErrorIs(t, missingErr, ErrInvalidReducer)
+		assert.ErrorIs(t, typeErr, ErrInvalidReducer)
+	})
+
+	t.Run("Should cancel the other tasks and return the first error when failing fast", func(t *testing.T) {
+		// Arrange
+		cancelled := make(chan bool, 1)
+		pipe, _ := New[map[string]int](CONCURRENT_MERGE, WithReducer(sumMaps))
+		pipe.Enqueue(failing(assert.AnError), func(ctx context.Context, input map[string]int) (map[string]int, error) {
+			select {
+			case <-ctx.Done():
+				cancelled <- true
+			case <-time.After(time.Second):
+				cancelled <- false
+			}
+			return input, ctx.Err()
+		})
+
+		// Act
+		_, err := pipe.Execute(context.Background(), nil)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, <-cancelled)
+	})
+
+	t.Run("Should merge the partial results when using best effort", func(t *testing.T) {
+		// Arrange
+		pipe, _ := New[map[string]int](CONCURRENT_MERGE, WithReducer(sumMaps), WithFailurePolicy(BEST_EFFORT))
+		pipe.Enqueue(shard(map[string]int{"alice": 1}), failing(assert.AnError))
+
+		// Act
+		result, err := pipe.Execute(context.Background(), nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"alice": 1}, result)
+	})
+
+	t.Run("Should return the errors when every task fails using best effort", func(t *testing.T) {
+		// Arrange
+		otherErr := errors.New("other error")
+		pipe, _ := New[map[string]int](CONCURRENT_MERGE, WithReducer(sumMaps), WithFailurePolicy(BEST_EFFORT))
+		pipe.Enqueue(failing(assert.AnError), failing(otherErr))
+
+		// Act
+		_, err := pipe.Execute(context.Background(), nil)
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorIs(t, err, otherErr)
+	})
+
+	t.Run("Should require the minimum number of successful tasks", func(t *testing.T) {
+		// Arrange
+		pipe, _ := New[map[string]int](CONCURRENT_MERGE, WithReducer(sumMaps), WithMinSuccess(2))
+		pipe.Enqueue(shard(map[string]int{"alice": 1}), failing(assert.AnError), failing(ONF))
+
+		// Act
+		_, err := pipe.Execute(context.Background(), nil)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrNotEnoughResults)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("Should return the input when no task has an output", func(t *testing.T) {
+		// Arrange
+		input := map[string]int{"alice": 0}
+		pipe, _ := New[map[string]int](CONCURRENT_MERGE, WithReducer(sumMaps))
+		pipe.Enqueue(failing(ONF), failing(ONF))
+
+		// Act
+		result, err := pipe.Execute(context.Background(), input)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, input, result)
+	})
+
+	t.Run("Should return when the context is done", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		pipe, _ := New[map[string]int](CONCURRENT_MERGE, WithReducer(sumMaps))
+		pipe.Enqueue(func(ctx context.Context, input map[string]int) (map[string]int, error) {
+			time.Sleep(200 * time.Millisecond)
+			return input, nil
+		})
+
+		// Act
+		start := time.Now()
+		_, err := pipe.Execute(ctx, nil)
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+}
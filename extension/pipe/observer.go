@@ -0,0 +1,119 @@
+package pipe
+
+import (
+	"context"
+	"sync"
+)
+
+// TaskInfo describes a task executed by a pipe
+type TaskInfo struct {
+	ExecutionType ExecutionType
+
+	// Index is the position of the task in the pipe, following the Enqueue order
+	Index int
+}
+
+// Observer is notified about every task executed by an instrumented pipe.
+// ObserveTask is called right before the task runs and returns the context the task will receive
+// and a function that must be called with the task error once the task finishes.
+type Observer interface {
+	ObserveTask(ctx context.Context, info TaskInfo) (context.Context, func(error))
+}
+
+var (
+	defaultObserver  Observer
+	defaultObserverM sync.RWMutex
+)
+
+// SetDefaultObserver sets the observer used by every pipe created by NewPipe after this call.
+// Pass nil to disable the instrumentation.
+func SetDefaultObserver(o Observer) {
+	defaultObserverM.Lock()
+	defer defaultObserverM.Unlock()
+	defaultObserver = o
+}
+
+func getDefaultObserver() Observer {
+	defaultObserverM.RLock()
+	defer defaultObserverM.RUnlock()
+	return defaultObserver
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) ObserveTask(ctx context.Context, info TaskInfo) (context.Context, func(error)) {
+	dones := make([]func(error), 0, len(m))
+	for _, o := range m {
+		var done func(error)
+		ctx, done = o.ObserveTask(ctx, info)
+		dones = append(dones, done)
+	}
+
+	return ctx, func(err error) {
+		// finish in the reverse order, like deferred calls
+		for i := len(dones) - 1; i >= 0; i-- {
+			dones[i](err)
+		}
+	}
+}
+
+func (m multiObserver) ObserveDivergence(ctx context.Context, executionType ExecutionType, d Divergence) {
+	for _, o := range m {
+		if do, ok := o.(DivergenceObserver); ok {
+			do.ObserveDivergence(ctx, executionType, d)
+		}
+	}
+}
+
+// NewMultiObserver combines several observers into one.
+// The context returned by each observer is passed to the next one.
+func NewMultiObserver(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
+
+type instrumentedPipe[T any] struct {
+	pipe          Pipe[T]
+	executionType ExecutionType
+	observer      Observer
+	m             sync.Mutex
+	count         int
+}
+
+// Instrument wraps a pipe so every enqueued task notifies the observer.
+// It works with any Pipe implementation, so custom pipes get the same instrumentation
+// as the ones created by NewPipe.
+func Instrument[T any](p Pipe[T], executionType ExecutionType, o Observer) Pipe[T] {
+	return &instrumentedPipe[T]{
+		pipe:          p,
+		executionType: executionType,
+		observer:      o,
+	}
+}
+
+func (p *instrumentedPipe[T]) Enqueue(tasks ...func(context.Context, T) (T, error)) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	wrapped := make([]func(context.Context, T) (T, error), 0, len(tasks))
+	for _, task := range tasks {
+		info := TaskInfo{ExecutionType: p.executionType, Index: p.count}
+		p.count++
+
+		wrapped = append(wrapped, func(ctx context.Context, input T) (T, error) {
+			ctx, done := p.observer.ObserveTask(ctx, info)
+			output, err := task(ctx, input)
+			done(err)
+			return output, err
+		})
+	}
+
+	p.pipe.Enqueue(wrapped...)
+}
+
+func (p *instrumentedPipe[T]) Execute(ctx context.Context, input T) (T, error) {
+	return p.pipe.Execute(ctx, input)
+}
+
+func (p *instrumentedPipe[T]) Wait(ctx context.Context) error {
+	return Wait(ctx, p.pipe)
+}
@@ -0,0 +1,126 @@
+package pipe
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type observerMock struct {
+	m      sync.Mutex
+	infos  []TaskInfo
+	errors []error
+}
+
+func (o *observerMock) ObserveTask(ctx context.Context, info TaskInfo) (context.Context, func(error)) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	o.infos = append(o.infos, info)
+
+	return ctx, func(err error) {
+		o.m.Lock()
+		defer o.m.Unlock()
+		o.errors = append(o.errors, err)
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	t.Run("Should notify the observer about every task", func(t *testing.T) {
+		// Arrange
+		observer := &observerMock{}
+		pipe := Instrument(NewSequentiallyPipe[int](), SEQUENTIAL, observer)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i + 1, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, ONF
+		})
+
+		// Act
+		result, err := pipe.Execute(context.Background(), 1)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result)
+		assert.Equal(t, []TaskInfo{
+			{ExecutionType: SEQUENTIAL, Index: 0},
+			{ExecutionType: SEQUENTIAL, Index: 1},
+		}, observer.infos)
+		assert.Equal(t, []error{nil, ONF}, observer.errors)
+	})
+
+	t.Run("Should instrument pipes created by NewPipe when a default observer is set", func(t *testing.T) {
+		// Arrange
+		observer := &observerMock{}
+		SetDefaultObserver(observer)
+		defer SetDefaultObserver(nil)
+
+		pipe := NewPipe(SEQUENTIAL_WITH_FIRST_RESULT, func(ctx context.Context, i int) (int, error) {
+			return i * 2, nil
+		})
+
+		// Act
+		result, err := pipe.Execute(context.Background(), 2)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result)
+		assert.Equal(t, []TaskInfo{{ExecutionType: SEQUENTIAL_WITH_FIRST_RESULT, Index: 0}}, observer.infos)
+	})
+}
+
+func TestMultiObserver(t *testing.T) {
+	t.Run("Should notify every observer", func(t *testing.T) {
+		// Arrange
+		first, second := &observerMock{}, &observerMock{}
+		pipe := Instrument(NewSequentiallyPipe[int](), SEQUENTIAL, NewMultiObserver(first, second))
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, assert.AnError
+		})
+
+		// Act
+		_, err := pipe.Execute(context.Background(), 1)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, []error{assert.AnError}, first.errors)
+		assert.Equal(t, []error{assert.AnError}, second.errors)
+	})
+}
+
+func TestOrigin(t *testing.T) {
+	t.Run("Should run fire-and-forget tasks detached from the request", func(t *testing.T) {
+		// Arrange
+		type key struct{}
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "request"))
+		ch := make(chan context.Context)
+
+		pipe := NewPipe[int](SEQUENTIAL_BLOCKING_ONLY_FIRST)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			ch <- ctx
+			return i, nil
+		})
+
+		// Act
+		_, err := pipe.Execute(ctx, 1)
+		cancel()
+		taskCtx := <-ch
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, taskCtx.Err())
+		assert.Equal(t, "request", taskCtx.Value(key{}))
+
+		origin, ok := Origin(taskCtx)
+		assert.True(t, ok)
+		assert.Error(t, origin.Err())
+
+		_, ok = Origin(ctx)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,110 @@
+package pipe
+
+import (
+	"context"
+	"log/slog"
+)
+
+// defaultConcurrency is the default number of tasks running at the same time in the concurrent pipes
+const defaultConcurrency = 10
+
+// defaultMaxBackground is the default number of fire-and-forget tasks running at the same time in a pipe
+const defaultMaxBackground = 1000
+
+// options holds the configuration shared by the pipe implementations
+type options struct {
+	logger          *slog.Logger
+	errorSink       ErrorSink
+	backgroundRetry RetryPolicy
+	maxBackground   int
+	concurrency     int
+	cancelOnError   bool
+
+	// reducer is the Reducer of a CONCURRENT_MERGE pipe, it is checked against the type of the pipe by New
+	reducer       any
+	failurePolicy FailurePolicy
+	minSuccess    int
+
+	// observer is the default Observer when the pipe was created, used to report events that are not tied to a task
+	observer Observer
+}
+
+// Option configures a pipe created by New
+type Option func(*options)
+
+// WithLogger sets the logger used to report the errors that are not returned to the caller,
+// like the errors of the fire-and-forget tasks. The default is slog.Default().
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithErrorSink sets the function that receives the errors of the fire-and-forget tasks.
+// The default sink logs the errors.
+func WithErrorSink(sink ErrorSink) Option {
+	return func(o *options) {
+		o.errorSink = sink
+	}
+}
+
+// WithBackgroundRetry retries the fire-and-forget tasks that fail, before reporting them to the ErrorSink
+func WithBackgroundRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.backgroundRetry = policy
+	}
+}
+
+// WithMaxBackground limits the number of fire-and-forget tasks running at the same time.
+// When the limit is reached, Execute does not wait: the task is not started and is reported to the ErrorSink with ErrBackgroundFull.
+func WithMaxBackground(n int) Option {
+	return func(o *options) {
+		o.maxBackground = n
+	}
+}
+
+// WithConcurrency limits the number of tasks running at the same time in the CONCURRENT, CONCURRENT_MERGE and QUORUM pipes.
+// A limit of zero or less runs every task at the same time. The default is 10.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithCancelOnError cancels the context of the other tasks of a CONCURRENT pipe when a task fails.
+// Without it, the other tasks run until the end and the first error is returned.
+func WithCancelOnError() Option {
+	return func(o *options) {
+		o.cancelOnError = true
+	}
+}
+
+// limit returns the concurrency limit in the format of errgroup.SetLimit, where a negative value means no limit
+func (o options) limit() int {
+	if o.concurrency <= 0 {
+		return -1
+	}
+	return o.concurrency
+}
+
+func newOptions(opts ...Option) options {
+	o := options{
+		logger:          slog.Default(),
+		backgroundRetry: RetryPolicy{MaxAttempts: 1},
+		maxBackground:   defaultMaxBackground,
+		concurrency:     defaultConcurrency,
+		observer:        getDefaultObserver(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.errorSink == nil {
+		logger := o.logger
+		o.errorSink = func(ctx context.Context, err error) {
+			// the error can not be returned to the caller, so it is only logged
+			logger.ErrorContext(ctx, "non-blocking task failed", "error", err)
+		}
+	}
+	return o
+}
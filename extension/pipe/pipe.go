@@ -3,13 +3,17 @@ package pipe
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 )
 
 // QNF (QueryNotFound) is a sentinel error used to indicate that a requested object was not found.
 var ONF error = errors.New("ObjectNotFound")
 
-// NewPipe creates a new Pipe based on the execution type
+// ErrInvalidExecutionType is returned by New when the execution type is unknown
+var ErrInvalidExecutionType = errors.New("invalid execution type")
+
+// New creates a new Pipe based on the execution type, configured by the options
 // It uses the Strategy Pattern to select the appropriate Pipe implementation
 // based on the execution type
 // The available execution types are:
@@ -17,21 +21,63 @@ var ONF error = errors.New("ObjectNotFound")
 // - CONCURRENT: executes tasks concurrently, where all tasks receive the same input and their outputs are ignored
 // - SEQUENTIAL_WITH_FIRST_RESULT: executes tasks sequentially, but returns the result of the first successful task
 // - SEQUENTIAL_BLOCKING_ONLY_FIRST: executes tasks sequentially, but only the first task can modify the input for the next tasks
+// - QUORUM: executes tasks concurrently and returns the result given by the majority of the tasks
+// - CONCURRENT_MERGE: executes tasks concurrently and combines their outputs with the reducer set by WithReducer
 //
-// If an invalid execution type is provided, the function will log a fatal error
-// and terminate the program
-func NewPipe[T any](executionType ExecutionType, tasks ...func(context.Context, T) (T, error)) Pipe[T] {
+// When a default Observer is set (see SetDefaultObserver), the returned pipe is instrumented
+// and the observer is notified about every task
+//
+// If an invalid execution type is provided, ErrInvalidExecutionType is returned
+// If a CONCURRENT_MERGE pipe has no reducer of its type, ErrInvalidReducer is returned
+func New[T any](executionType ExecutionType, opts ...Option) (Pipe[T], error) {
+	o := newOptions(opts...)
+
+	var p Pipe[T]
 	switch executionType {
 	case SEQUENTIAL:
-		return NewSequentiallyPipe(tasks...)
+		p = &sequentiallyPipe[T]{opts: o}
 	case CONCURRENT:
-		return NewConcurrentlyPipe(tasks...)
+		p = &concurrentlyPipe[T]{opts: o}
 	case SEQUENTIAL_WITH_FIRST_RESULT:
-		return NewSequentiallyWithFirstResultPipe(tasks...)
+		p = &sequentiallyWithFirstResultPipe[T]{opts: o}
 	case SEQUENTIAL_BLOCKING_ONLY_FIRST:
-		return NewSequentiallyBlockingFirstResultPipe(tasks...)
+		p = newSequentiallyBlockingFirstResultPipe[T](o)
+	case QUORUM:
+		p = &quorumPipe[T]{opts: o}
+	case CONCURRENT_MERGE:
+		reducer, ok := o.reducer.(Reducer[T])
+		if !ok || reducer == nil {
+			return nil, fmt.Errorf("%w: %s needs a reducer of %T", ErrInvalidReducer, executionType, reducer)
+		}
+		p = newConcurrentMergePipe(o, reducer)
 	default:
-		log.Fatalln("Invalid execution type")
+		return nil, fmt.Errorf("%w: %q", ErrInvalidExecutionType, executionType)
+	}
+
+	if obs := getDefaultObserver(); obs != nil {
+		p = Instrument(p, executionType, obs)
+	}
+
+	return p, nil
+}
+
+// NewPipe creates a new Pipe based on the execution type, with the default options
+// See New for the available execution types
+//
+// The tasks are the variadic argument, so the options can not be passed here;
+// a pipe with options is created by New, and its tasks added with Enqueue
+//
+// If an invalid execution type is provided, the function will log a fatal error
+// and terminate the program
+func NewPipe[T any](executionType ExecutionType, tasks ...func(context.Context, T) (T, error)) Pipe[T] {
+	p, err := New[T](executionType)
+	if err != nil {
+		log.Fatalln(err)
 		return nil
 	}
+
+	if len(tasks) > 0 {
+		p.Enqueue(tasks...)
+	}
+	return p
 }
@@ -2,6 +2,7 @@ package pipe
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -180,4 +181,106 @@ func TestPipe(t *testing.T) {
 		assert.Equal(t, 75, v)
 	})
 
+	t.Run("Should return the joined errors when every task fails in Sequential with First Result Execution", func(t *testing.T) {
+		// Arrange
+		otherErr := errors.New("other error")
+		pipe := NewPipe[int](SEQUENTIAL_WITH_FIRST_RESULT)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 0, assert.AnError
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 0, ONF
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 0, otherErr
+		})
+
+		// Act
+		result, err := pipe.Execute(context.Background(), 2)
+
+		// Assert
+		assert.Equal(t, 2, result)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorIs(t, err, otherErr)
+		assert.NotErrorIs(t, err, ONF)
+	})
+
+	t.Run("Should return the majority result in Quorum Execution", func(t *testing.T) {
+		// Arrange
+		pipe := NewPipe[map[string]int](QUORUM)
+		pipe.Enqueue(func(ctx context.Context, m map[string]int) (map[string]int, error) {
+			return map[string]int{"participant1": 10}, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, m map[string]int) (map[string]int, error) {
+			return map[string]int{"participant1": 9}, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, m map[string]int) (map[string]int, error) {
+			return map[string]int{"participant1": 10}, nil
+		})
+
+		// Act
+		result, err := pipe.Execute(context.Background(), nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"participant1": 10}, result)
+	})
+
+	t.Run("Should return ErrNoQuorum when the tasks do not agree in Quorum Execution", func(t *testing.T) {
+		// Arrange
+		pipe := NewPipe[int](QUORUM)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 1, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 2, nil
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 0, assert.AnError
+		})
+
+		// Act
+		result, err := pipe.Execute(context.Background(), 5)
+
+		// Assert
+		assert.Equal(t, 5, result)
+		assert.ErrorIs(t, err, ErrNoQuorum)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("Should report the divergence in Quorum Execution", func(t *testing.T) {
+		// Arrange
+		observer := &divergenceObserverMock{}
+		SetDefaultObserver(observer)
+		defer SetDefaultObserver(nil)
+
+		pipe := NewPipe[int](QUORUM)
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 0, ONF
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 0, ONF
+		})
+		pipe.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return 3, nil
+		})
+
+		// Act
+		result, err := pipe.Execute(context.Background(), 5)
+
+		// Assert
+		// the majority did not find the object, so the input is returned
+		assert.NoError(t, err)
+		assert.Equal(t, 5, result)
+		assert.Equal(t, []Divergence{{Answers: 2, Failures: 0, Tasks: 3}}, observer.divergences)
+	})
+}
+
+type divergenceObserverMock struct {
+	observerMock
+	divergences []Divergence
+}
+
+func (o *divergenceObserverMock) ObserveDivergence(ctx context.Context, executionType ExecutionType, d Divergence) {
+	o.divergences = append(o.divergences, d)
 }
@@ -0,0 +1,130 @@
+package pipe
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrNoQuorum is returned by the QUORUM pipe when no result is given by the majority of the tasks
+var ErrNoQuorum = errors.New("no quorum")
+
+// Divergence describes the answers of a QUORUM pipe when the tasks did not agree
+type Divergence struct {
+	// Answers is the number of different results (ObjectNotFound included)
+	Answers int
+
+	// Failures is the number of tasks that returned an error
+	Failures int
+
+	// Tasks is the total number of tasks
+	Tasks int
+}
+
+// DivergenceObserver is an optional interface of Observer.
+// It is notified when the tasks of a QUORUM pipe do not return the same result.
+type DivergenceObserver interface {
+	ObserveDivergence(ctx context.Context, executionType ExecutionType, d Divergence)
+}
+
+type quorumAnswer[T any] struct {
+	result   T
+	notFound bool
+	votes    int
+}
+
+type quorumPipe[T any] struct {
+	tasks []func(context.Context, T) (T, error)
+	opts  options
+}
+
+// NewQuorumPipe creates a new Pipe that executes tasks concurrently
+// and returns the result given by the majority (more than half) of the tasks
+// The results are compared with reflect.DeepEqual
+// If the majority of the tasks returns an ONF (ObjectNotFound) error, the input is returned without error
+// If there is no majority, ErrNoQuorum joined with the errors of the tasks is returned
+// When the tasks do not agree, the divergence is logged and reported to the default Observer
+func NewQuorumPipe[T any](tasks ...func(context.Context, T) (T, error)) Pipe[T] {
+	pipe := &quorumPipe[T]{opts: newOptions()}
+
+	if len(tasks) == 0 {
+		return pipe
+	}
+
+	pipe.Enqueue(tasks...)
+	return pipe
+}
+
+func (p *quorumPipe[T]) Enqueue(tasks ...func(context.Context, T) (T, error)) {
+	p.tasks = append(p.tasks, tasks...)
+}
+
+func (p *quorumPipe[T]) Execute(ctx context.Context, input T) (T, error) {
+	if len(p.tasks) == 0 {
+		return input, nil
+	}
+
+	var (
+		m       sync.Mutex
+		answers []*quorumAnswer[T]
+		errs    error
+		fails   int
+		eg      errgroup.Group
+	)
+	eg.SetLimit(p.opts.limit())
+
+	for _, task := range p.tasks {
+		eg.Go(func() error {
+			result, err := task(ctx, input)
+
+			m.Lock()
+			defer m.Unlock()
+
+			notFound := errors.Is(err, ONF)
+			if err != nil && !notFound {
+				errs = errors.Join(errs, err)
+				fails++
+				return nil
+			}
+
+			for _, a := range answers {
+				if a.notFound == notFound && (notFound || reflect.DeepEqual(a.result, result)) {
+					a.votes++
+					return nil
+				}
+			}
+			answers = append(answers, &quorumAnswer[T]{result: result, notFound: notFound, votes: 1})
+			return nil
+		})
+	}
+	eg.Wait()
+
+	if len(answers) > 1 || (len(answers) == 1 && fails > 0) {
+		p.reportDivergence(ctx, Divergence{Answers: len(answers), Failures: fails, Tasks: len(p.tasks)})
+	}
+
+	majority := len(p.tasks)/2 + 1
+	for _, a := range answers {
+		if a.votes < majority {
+			continue
+		}
+		if a.notFound {
+			return input, nil
+		}
+		return a.result, nil
+	}
+
+	return input, errors.Join(ErrNoQuorum, errs)
+}
+
+func (p *quorumPipe[T]) reportDivergence(ctx context.Context, d Divergence) {
+	p.opts.logger.WarnContext(ctx, "quorum divergence between tasks",
+		"answers", d.Answers, "failures", d.Failures, "tasks", d.Tasks)
+
+	if o, ok := p.opts.observer.(DivergenceObserver); ok {
+		o.ObserveDivergence(ctx, QUORUM, d)
+	}
+}
@@ -0,0 +1,83 @@
+package pipe
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy configures how many times a task is executed and how long to wait between the attempts.
+// The wait starts at InitialBackoff and doubles on each attempt, limited to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Jitter randomizes the wait by up to this fraction (0 to 1), so the retries of
+	// several clients do not hit the repository at the same time
+	Jitter float64
+
+	// RetryOn selects the errors that are retried. Nil retries every error.
+	// ObjectNotFound is never retried.
+	RetryOn func(error) bool
+}
+
+// RetryOnErrors creates a RetryPolicy.RetryOn function that retries only the given errors (and the errors wrapping them)
+func RetryOnErrors(targets ...error) func(error) bool {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// backoff returns the time to wait before the given attempt (starting at 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			d = p.MaxBackoff
+			break
+		}
+	}
+
+	if p.Jitter > 0 {
+		// spread the wait uniformly between d*(1-jitter) and d*(1+jitter)
+		d = time.Duration(float64(d) * (1 + p.Jitter*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if err == nil || errors.Is(err, ONF) {
+		return false
+	}
+	return p.RetryOn == nil || p.RetryOn(err)
+}
+
+// retry executes fn until it succeeds, the attempts are over or the context is done.
+// ObjectNotFound is not considered a failure, so it is never retried.
+func retry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	attempts := max(policy.MaxAttempts, 1)
+
+	var result T
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		result, err = fn()
+		if !policy.retryable(err) || attempt == attempts {
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, errors.Join(err, ctx.Err())
+		case <-time.After(policy.backoff(attempt)):
+		}
+	}
+	return result, err
+}
@@ -2,19 +2,27 @@ package pipe
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"sync"
 )
 
 type sequentiallyBlockingFirstResultPipe[T any] struct {
 	tasks []func(context.Context, T) (T, error)
+	opts  options
+	slots chan struct{}
+	wg    sync.WaitGroup
 }
 
 // NewSequentiallyBlockingFirstResultPipe creates a new Pipe that executes tasks sequentially
 // but only the first task is blocking, the rest are non-blocking
-// This means that the first task will be executed and the output of this task will be the input of the next tasks
+// This means that the first task will be executed and, when it succeeds, the next tasks receive the same input
 // The next tasks will be executed in separate goroutines and their results will be ignored
+// When the first task fails its error is returned and the next tasks are not started
+// The next tasks are not cancelled when the context of the request is done, use Origin to reach the request context
+// Their errors are retried and reported to the ErrorSink, see WithErrorSink and WithBackgroundRetry
+// Use Wait to wait for the tasks running in background, e.g. before closing the repositories on shutdown
 func NewSequentiallyBlockingFirstResultPipe[T any](tasks ...func(context.Context, T) (T, error)) Pipe[T] {
-	pipe := &sequentiallyBlockingFirstResultPipe[T]{}
+	pipe := newSequentiallyBlockingFirstResultPipe[T](newOptions())
 
 	if len(tasks) == 0 {
 		return pipe
@@ -23,36 +31,91 @@ func NewSequentiallyBlockingFirstResultPipe[T any](tasks ...func(context.Context
 	pipe.Enqueue(tasks...)
 	return pipe
 }
+
+func newSequentiallyBlockingFirstResultPipe[T any](opts options) *sequentiallyBlockingFirstResultPipe[T] {
+	p := &sequentiallyBlockingFirstResultPipe[T]{opts: opts}
+	if opts.maxBackground > 0 {
+		p.slots = make(chan struct{}, opts.maxBackground)
+	}
+	return p
+}
+
 func (p *sequentiallyBlockingFirstResultPipe[T]) Enqueue(tasks ...func(context.Context, T) (T, error)) {
 	p.tasks = append(p.tasks, tasks...)
 }
 
-func (p *sequentiallyBlockingFirstResultPipe[T]) Execute(ctx context.Context, target T) (T, error) {
-	for ind := 0; ind < len(p.tasks); ind++ {
-		if ind != 0 {
-			select {
-			case <-ctx.Done():
-				return target, ctx.Err()
-			default:
-				go func(i int) {
-					_, err := p.tasks[i](context.Background(), target)
-					if err != nil {
-						// log the error, but do not return it
-						// in a real-world scenario, you might want to use a logging library
-						// here we just print it to the console
-						// but in production, i consider creating a channel to collect these errors
-						// and return them at the end of the execution
-						// or use a logging library to log them
-						fmt.Println("Error in non-blocking task:", err)
-					}
-				}(ind)
-			}
+// acquire takes a free slot to run a background task, it does not wait when every slot is taken
+func (p *sequentiallyBlockingFirstResultPipe[T]) acquire() error {
+	if p.slots == nil {
+		return nil
+	}
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	default:
+		return ErrBackgroundFull
+	}
+}
+
+func (p *sequentiallyBlockingFirstResultPipe[T]) release() {
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+func (p *sequentiallyBlockingFirstResultPipe[T]) runInBackground(ctx context.Context, i int, target T) {
+	taskCtx := detach(ctx)
+
+	// the task is counted before it takes a slot, so Wait never misses a task being started
+	p.wg.Add(1)
+	if err := p.acquire(); err != nil {
+		// the request is not held waiting for a slot, the task is reported so it is not lost
+		p.opts.errorSink(taskCtx, &TaskError{Index: i, Input: target, Err: err})
+		p.wg.Done()
+		return
+	}
+
+	go func() {
+		defer p.wg.Done()
+		defer p.release()
+
+		_, err := retry(taskCtx, p.opts.backgroundRetry, func() (T, error) {
+			return p.tasks[i](taskCtx, target)
+		})
+		if err != nil && !errors.Is(err, ONF) {
+			p.opts.errorSink(taskCtx, &TaskError{Index: i, Input: target, Err: err})
 		}
+	}()
+}
+
+func (p *sequentiallyBlockingFirstResultPipe[T]) Execute(ctx context.Context, target T) (T, error) {
+	if len(p.tasks) == 0 {
+		return target, nil
+	}
+
+	result, err := p.tasks[0](ctx, target)
+	if err != nil {
+		return result, err
 	}
 
-	if len(p.tasks) > 0 {
-		return p.tasks[0](ctx, target)
+	for ind := 1; ind < len(p.tasks); ind++ {
+		p.runInBackground(ctx, ind, target)
 	}
+	return result, nil
+}
+
+// Wait blocks until every background task is done, or returns the error of the context when it is done first
+func (p *sequentiallyBlockingFirstResultPipe[T]) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
 
-	return target, nil
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -8,13 +8,14 @@ import (
 // sequentiallyPipe  applies the Strategy Pattern
 type sequentiallyPipe[T any] struct {
 	tasks []func(context.Context, T) (T, error)
+	opts  options
 }
 
 // NewSequentiallyPipe creates a new Pipe that executes tasks sequentially
 // Sequentially means that each task will be executed one after the other
 // and the output of one task will be the input of the next task
 func NewSequentiallyPipe[T any](tasks ...func(context.Context, T) (T, error)) Pipe[T] {
-	pipe := &sequentiallyPipe[T]{}
+	pipe := &sequentiallyPipe[T]{opts: newOptions()}
 
 	if len(tasks) == 0 {
 		return pipe
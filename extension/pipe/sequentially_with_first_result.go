@@ -7,6 +7,7 @@ import (
 
 type sequentiallyWithFirstResultPipe[T any] struct {
 	tasks []func(context.Context, T) (T, error)
+	opts  options
 }
 
 // NewSequentiallyWithFirstResultPipe creates a new Pipe that executes tasks sequentiall
@@ -16,7 +17,7 @@ type sequentiallyWithFirstResultPipe[T any] struct {
 // If a task returns an ONF (ObjectNotFound) error, the execution continues to the next task
 // If all tasks return an error, the errors is joined and returned
 func NewSequentiallyWithFirstResultPipe[T any](tasks ...func(context.Context, T) (T, error)) Pipe[T] {
-	pipe := &sequentiallyWithFirstResultPipe[T]{}
+	pipe := &sequentiallyWithFirstResultPipe[T]{opts: newOptions()}
 
 	if len(tasks) == 0 {
 		return pipe
@@ -42,10 +43,8 @@ func (p *sequentiallyWithFirstResultPipe[T]) Execute(ctx context.Context, target
 			continue
 		}
 
-		// for other errors, log them
-		// the error is stored but the execution continues
-		// this error should be logged in a real-world scenario
-		errors.Join(err, e)
+		// for other errors, the error is stored but the execution continues
+		err = errors.Join(err, e)
 	}
 	return target, err
 }
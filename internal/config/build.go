@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	voteUsecase "github.com/sergiodii/bbb/internal/usecase/vote"
+	"github.com/sergiodii/bbb/internal/usecase/vote/aggregator"
+	"github.com/sergiodii/bbb/pkg/localsql"
+	"github.com/sergiodii/bbb/pkg/memory"
+	"github.com/sergiodii/bbb/pkg/redis"
+)
+
+// NewRepositories creates the repositories of the pipeline, the dead letter and idempotency repositories included.
+// The redis repositories without an addr connect to the Redis of the configuration.
+// The wrap function decorates each repository, for example with metrics, and can be nil.
+// It returns an error when the connection settings of a redis repository are invalid.
+func (p *Pipeline) NewRepositories(redisCfg RedisConfig, logger *slog.Logger, wrap func(name string, repo repository.RoundRepository) repository.RoundRepository) (Repositories, error) {
+	repos := Repositories{byName: map[string]repository.RoundRepository{}}
+
+	for _, r := range p.Repositories {
+		var repo repository.RoundRepository
+		switch r.Type {
+		case RepositoryRedis:
+			opts, err := redisCfg.Options(r.Addr)
+			if err != nil {
+				return repos, fmt.Errorf("repository %q: %w", r.Name, err)
+			}
+			repo = redis.NewRedisRoundRepositoryWithOptions(opts)
+		case RepositorySQL:
+			repo = localsql.NewLocalSqlRoundRepository(logger)
+		case RepositoryMemory:
+			repo = memory.NewMemoryRoundRepository()
+		}
+
+		if wrap != nil {
+			repo = wrap(r.Name, repo)
+		}
+		repos.names = append(repos.names, r.Name)
+		repos.byName[r.Name] = repo
+	}
+
+	deadLetters, err := p.NewDeadLetterRepository(redisCfg)
+	if err != nil {
+		return repos, err
+	}
+	repos.deadLetters = deadLetters
+
+	idempotency, err := p.NewIdempotencyRepository(redisCfg)
+	repos.idempotency = idempotency
+	return repos, err
+}
+
+// NewDeadLetterRepository creates the dead letter repository, it returns nil when no dead letter is configured.
+// The votes are stored in Redis for a redis repository, and in memory otherwise.
+func (p *Pipeline) NewDeadLetterRepository(redisCfg RedisConfig) (repository.DeadLetterRepository, error) {
+	r, ok := p.repository(p.DeadLetter)
+	if !ok {
+		return nil, nil
+	}
+
+	if r.Type == RepositoryRedis {
+		opts, err := redisCfg.Options(r.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("dead letter: %w", err)
+		}
+		return redis.NewRedisDeadLetterRepositoryWithOptions(opts), nil
+	}
+	return localsql.NewLocalSqlDeadLetterRepository(), nil
+}
+
+// NewIdempotencyRepository creates the idempotency repository, it returns nil when no idempotency is configured.
+// The keys are stored in Redis for a redis repository, and in memory otherwise.
+func (p *Pipeline) NewIdempotencyRepository(redisCfg RedisConfig) (repository.IdempotencyRepository, error) {
+	r, ok := p.repository(p.Idempotency)
+	if !ok {
+		return nil, nil
+	}
+
+	if r.Type == RepositoryRedis {
+		opts, err := redisCfg.Options(r.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("idempotency: %w", err)
+		}
+		return redis.NewRedisIdempotencyRepositoryWithOptions(opts), nil
+	}
+	return localsql.NewLocalSqlIdempotencyRepository(), nil
+}
+
+// handlerOptions returns the aggregator options of the handlers and the repositories used by them.
+// A handler without configuration uses every repository, except CreateRound that uses the repositories of CreateVote.
+func (p *Pipeline) handlerOptions(repos Repositories, handlers []voteUsecase.HandlerFuncEnum) ([]aggregator.Option, []string) {
+	var (
+		opts []aggregator.Option
+		used []string
+	)
+
+	for _, handler := range handlers {
+		h, ok := p.Handlers[handler]
+		names := h.Repositories
+		if len(names) == 0 && handler == voteUsecase.HandlerFuncCreateRound {
+			// the rounds are stored where the votes are registered
+			names = p.Handlers[voteUsecase.HandlerFuncCreateVote].Repositories
+		}
+		if len(names) == 0 {
+			names = repos.names
+		}
+
+		for _, name := range names {
+			if !slices.Contains(used, name) {
+				used = append(used, name)
+			}
+		}
+
+		if !ok {
+			continue
+		}
+		if h.ExecutionType != "" {
+			opts = append(opts, aggregator.WithExecutionType(handler, pipe.ParseExecutionType(h.ExecutionType)))
+		}
+		if len(h.Repositories) > 0 {
+			opts = append(opts, aggregator.WithHandlerRepositories(handler, repos.List(h.Repositories...)...))
+		}
+		opts = append(opts, aggregator.WithTaskPolicy(handler, h.Policy()))
+	}
+
+	// the used repositories keep the order they were declared
+	slices.SortStableFunc(used, func(a, b string) int {
+		return slices.Index(repos.names, a) - slices.Index(repos.names, b)
+	})
+	return opts, used
+}
+
+// NewCommandAggregator creates the aggregator that registers the votes, following the pipeline and then the options,
+// such as aggregator.WithLogger. It also returns the names of the repositories used by the aggregator.
+func (p *Pipeline) NewCommandAggregator(repos Repositories, extra ...aggregator.Option) (aggregator.CommandAggregator, []string, error) {
+	opts, used := p.handlerOptions(repos, commandHandlers)
+	if deadLetters := repos.DeadLetters(); deadLetters != nil {
+		opts = append(opts, aggregator.WithDeadLetter(deadLetters))
+	}
+	opts = append(opts, extra...)
+
+	a, err := aggregator.NewCommandAggregatorWithOptions(repos.List(), opts...)
+	return a, used, err
+}
+
+// NewQueryAggregator creates the aggregator that queries the votes, following the pipeline and then the options,
+// such as aggregator.WithQueryCache. It also returns the names of the repositories used by the aggregator.
+func (p *Pipeline) NewQueryAggregator(repos Repositories, extra ...aggregator.Option) (aggregator.QueryAggregator, []string, error) {
+	opts, used := p.handlerOptions(repos, queryHandlers)
+	opts = append(opts, extra...)
+
+	a, err := aggregator.NewQueryAggregatorWithOptions(repos.List(), opts...)
+	return a, used, err
+}
@@ -0,0 +1,473 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sergiodii/bbb/pkg/redis"
+	"github.com/sergiodii/bbb/pkg/tracing"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidConfig is returned when the application configuration is not valid
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// redacted replaces the secrets when the configuration is printed
+const redacted = "********"
+
+// Config is the configuration of the application.
+// It is loaded by Load with the precedence flag > environment variable > file > default,
+// and passed explicitly to the components that need it.
+type Config struct {
+	// Port is the port the API listens on
+	Port string `yaml:"port"`
+
+	// Pipeline is the path of the pipeline configuration file, see LoadPipeline
+	Pipeline string `yaml:"pipeline"`
+
+	// Redis is the connection used by the redis repositories without an addr of their own
+	Redis RedisConfig `yaml:"redis"`
+
+	// BlockedIPRanges are the prefixes of the client IPs answered with 403
+	BlockedIPRanges []string `yaml:"blocked_ip_ranges"`
+
+	// TrustedProxies are the IPs and CIDRs of the proxies and the authentication gateway in front of the API.
+	// Only their requests are trusted to carry the client IP and the X-Voter-ID, none is trusted by default.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	// AdminToken is the bearer token of the administrative operations, the creation of the rounds.
+	// They are disabled when it is empty.
+	AdminToken string `yaml:"admin_token"`
+
+	Query       QueryConfig       `yaml:"query"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Log         LogConfig         `yaml:"log"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
+	Shutdown    ShutdownConfig    `yaml:"shutdown"`
+}
+
+// RedisConfig are the connection settings of the Redis client
+type RedisConfig struct {
+	// URL describes a single node, Sentinel or Cluster connection, with TLS and auth, see redis.Options.WithURL.
+	// When set it replaces the addr.
+	URL string `yaml:"url"`
+
+	Addr     string `yaml:"addr"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// TLSCAFile is the CA that signed the certificate of the server, TLS is enabled when set
+	TLSCAFile string `yaml:"tls_ca_file"`
+
+	DB           int           `yaml:"db"`
+	PoolSize     int           `yaml:"pool_size"`
+	MinIdleConns int           `yaml:"min_idle_conns"`
+	MaxRetries   int           `yaml:"max_retries"`
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+}
+
+// QueryConfig configures the queries of the votes
+type QueryConfig struct {
+	// CacheTTL is the time the results of the queries are cached and the max-age of their responses,
+	// zero disables the cache
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+// IdempotencyConfig configures the idempotency keys of the votes, stored in the idempotency repository of the pipeline
+type IdempotencyConfig struct {
+	// TTL is the time a key is kept, the retries of a vote after it are registered again
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// LogConfig configures the application logger
+type LogConfig struct {
+	// Level is one of debug, info, warn or error
+	Level string `yaml:"level"`
+}
+
+// TracingConfig configures the trace exporter
+type TracingConfig struct {
+	// Exporter is one of otlp, console or none, see tracing.Setup
+	Exporter string `yaml:"exporter"`
+}
+
+// MetricsConfig configures the Prometheus metrics
+type MetricsConfig struct {
+	// Rounds are the rounds whose votes are counted apart, the votes of the other rounds are counted together.
+	// The rounds are not checked when a vote is registered, so they are listed to keep the cardinality bounded.
+	Rounds []string `yaml:"rounds"`
+
+	// Port is the port of the /metrics of the grpc-api, the HTTP APIs expose it on their own port.
+	// The grpc-api does not expose the metrics when it is empty.
+	Port string `yaml:"port"`
+}
+
+// ShutdownConfig controls how the API stops
+type ShutdownConfig struct {
+	// DrainDelay is the time the API keeps answering requests after the readiness probe starts failing,
+	// giving the load balancer time to remove this instance
+	DrainDelay time.Duration `yaml:"drain_delay"`
+
+	// Timeout is the maximum time to wait for the in-flight requests and then for the background tasks
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Default returns the configuration used when nothing is set
+func Default() Config {
+	r := redis.DefaultOptions("localhost:6379")
+	return Config{
+		Port: "8080",
+		Redis: RedisConfig{
+			Addr:         r.Addrs[0],
+			PoolSize:     r.PoolSize,
+			MinIdleConns: r.MinIdleConns,
+			MaxRetries:   r.MaxRetries,
+			DialTimeout:  r.DialTimeout,
+			ReadTimeout:  r.ReadTimeout,
+			WriteTimeout: r.WriteTimeout,
+		},
+		BlockedIPRanges: []string{},
+		TrustedProxies:  []string{},
+		Metrics:         MetricsConfig{Rounds: []string{}},
+		Idempotency:     IdempotencyConfig{TTL: 10 * time.Minute},
+		Log:             LogConfig{Level: "info"},
+		Tracing:         TracingConfig{Exporter: tracing.ExporterNone},
+		Shutdown: ShutdownConfig{
+			DrainDelay: 5 * time.Second,
+			Timeout:    10 * time.Second,
+		},
+	}
+}
+
+// Options returns the options of the Redis client of a repository.
+// The addr of the repository, an address or a URL, replaces the url and the addr of the configuration.
+func (r RedisConfig) Options(addr string) (redis.Options, error) {
+	o := redis.DefaultOptions(r.Addr)
+	o.Username = r.Username
+	o.Password = r.Password
+	o.DB = r.DB
+	o.PoolSize = r.PoolSize
+	o.MinIdleConns = r.MinIdleConns
+	o.MaxRetries = r.MaxRetries
+	o.DialTimeout = r.DialTimeout
+	o.ReadTimeout = r.ReadTimeout
+	o.WriteTimeout = r.WriteTimeout
+
+	url := r.URL
+	switch {
+	case strings.Contains(addr, "://"):
+		url = addr
+	case addr != "":
+		url, o.Addrs = "", []string{addr}
+	}
+
+	var err error
+	if url != "" {
+		if o, err = o.WithURL(url); err != nil {
+			return o, err
+		}
+	}
+	if r.TLSCAFile != "" {
+		return o.WithCAFile(r.TLSCAFile)
+	}
+	return o, nil
+}
+
+// setting binds a field of the configuration to its key in the file, its environment variable and its flag
+type setting struct {
+	key   string
+	env   string
+	flag  string
+	short string
+	usage string
+	field func(*Config) any
+}
+
+// configFileSetting is the path of the configuration file, it is not part of the configuration itself
+var configFileSetting = setting{env: "CONFIG_FILE", flag: "config", usage: "Arquivo YAML de configuração"}
+
+var settings = []setting{
+	{key: "port", env: "PORT", flag: "port", short: "p", usage: "Porta que a API irá escutar", field: func(c *Config) any { return &c.Port }},
+	{key: "pipeline", env: "PIPELINE_CONFIG", flag: "pipeline", usage: "Arquivo YAML com os repositórios e o pipeline de cada handler (padrão: Redis em redis.addr)", field: func(c *Config) any { return &c.Pipeline }},
+	{key: "redis.url", env: "REDIS_URL", flag: "redis-url", usage: "URL do Redis, substitui o endereço (redis://, rediss://, redis+sentinel://, redis+cluster://)", field: func(c *Config) any { return &c.Redis.URL }},
+	{key: "redis.addr", env: "REDIS_ADDR", flag: "redis-addr", usage: "Endereço do Redis", field: func(c *Config) any { return &c.Redis.Addr }},
+	{key: "redis.username", env: "REDIS_USERNAME", flag: "redis-username", usage: "Usuário (ACL) do Redis", field: func(c *Config) any { return &c.Redis.Username }},
+	{key: "redis.password", env: "REDIS_PASSWORD", flag: "redis-password", usage: "Senha do Redis", field: func(c *Config) any { return &c.Redis.Password }},
+	{key: "redis.tls_ca_file", env: "REDIS_TLS_CA_FILE", flag: "redis-tls-ca-file", usage: "CA do certificado do Redis, habilita TLS", field: func(c *Config) any { return &c.Redis.TLSCAFile }},
+	{key: "redis.db", env: "REDIS_DB", flag: "redis-db", usage: "Banco do Redis", field: func(c *Config) any { return &c.Redis.DB }},
+	{key: "redis.pool_size", env: "REDIS_POOL_SIZE", flag: "redis-pool-size", usage: "Número máximo de conexões com o Redis", field: func(c *Config) any { return &c.Redis.PoolSize }},
+	{key: "redis.min_idle_conns", env: "REDIS_MIN_IDLE_CONNS", flag: "redis-min-idle-conns", usage: "Conexões com o Redis mantidas abertas", field: func(c *Config) any { return &c.Redis.MinIdleConns }},
+	{key: "redis.max_retries", env: "REDIS_MAX_RETRIES", flag: "redis-max-retries", usage: "Tentativas de um comando do Redis que falhou na rede (-1 desativa)", field: func(c *Config) any { return &c.Redis.MaxRetries }},
+	{key: "redis.dial_timeout", env: "REDIS_DIAL_TIMEOUT", flag: "redis-dial-timeout", usage: "Timeout para conectar ao Redis", field: func(c *Config) any { return &c.Redis.DialTimeout }},
+	{key: "redis.read_timeout", env: "REDIS_READ_TIMEOUT", flag: "redis-read-timeout", usage: "Timeout de leitura do Redis", field: func(c *Config) any { return &c.Redis.ReadTimeout }},
+	{key: "redis.write_timeout", env: "REDIS_WRITE_TIMEOUT", flag: "redis-write-timeout", usage: "Timeout de escrita do Redis", field: func(c *Config) any { return &c.Redis.WriteTimeout }},
+	{key: "blocked_ip_ranges", env: "BLOCKED_IP_RANGES", flag: "blocked-ip-ranges", usage: "Prefixos de IP bloqueados, separados por vírgula (ex: 192.168.1.,10.0.0.)", field: func(c *Config) any { return &c.BlockedIPRanges }},
+	{key: "trusted_proxies", env: "TRUSTED_PROXIES", flag: "trusted-proxies", usage: "IPs ou CIDRs dos proxies e do gateway de autenticação, separados por vírgula; só deles são aceitos o IP do cliente e o X-Voter-ID", field: func(c *Config) any { return &c.TrustedProxies }},
+	{key: "admin_token", env: "ADMIN_TOKEN", flag: "admin-token", usage: "Token (Authorization: Bearer) exigido para criar rodadas; sem ele a criação de rodadas fica desativada", field: func(c *Config) any { return &c.AdminToken }},
+	{key: "query.cache_ttl", env: "QUERY_CACHE_TTL", flag: "query-cache-ttl", usage: "Tempo em cache dos resultados das consultas, 0 desativa (ex: 500ms)", field: func(c *Config) any { return &c.Query.CacheTTL }},
+	{key: "idempotency.ttl", env: "IDEMPOTENCY_TTL", flag: "idempotency-ttl", usage: "Tempo em que a Idempotency-Key de um voto é guardada, as tentativas do voto nesse tempo são registradas uma única vez", field: func(c *Config) any { return &c.Idempotency.TTL }},
+	{key: "log.level", env: "LOG_LEVEL", flag: "log-level", usage: "Nível do log: debug, info, warn ou error", field: func(c *Config) any { return &c.Log.Level }},
+	{key: "tracing.exporter", env: "OTEL_TRACES_EXPORTER", flag: "tracing-exporter", usage: "Exportador de traces: otlp, console ou none", field: func(c *Config) any { return &c.Tracing.Exporter }},
+	{key: "metrics.rounds", env: "METRICS_ROUNDS", flag: "metrics-rounds", usage: "Rodadas com os votos contados à parte nas métricas, separadas por vírgula; as demais são contadas como other", field: func(c *Config) any { return &c.Metrics.Rounds }},
+	{key: "metrics.port", env: "METRICS_PORT", flag: "metrics-port", usage: "Porta HTTP do /metrics da grpc-api, vazia desativa (as APIs HTTP expõem o /metrics na própria porta)", field: func(c *Config) any { return &c.Metrics.Port }},
+	{key: "shutdown.drain_delay", env: "DRAIN_DELAY", flag: "drain-delay", usage: "Tempo respondendo requisições após a readiness falhar, antes de parar o servidor", field: func(c *Config) any { return &c.Shutdown.DrainDelay }},
+	{key: "shutdown.timeout", env: "SHUTDOWN_TIMEOUT", flag: "shutdown-timeout", usage: "Tempo máximo para concluir as requisições e as tarefas em background no desligamento", field: func(c *Config) any { return &c.Shutdown.Timeout }},
+}
+
+// parseValue sets the field pointed by ptr from its text representation
+func parseValue(ptr any, raw string) error {
+	switch v := ptr.(type) {
+	case *string:
+		*v = raw
+	case *int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return err
+		}
+		*v = n
+	case *time.Duration:
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return err
+		}
+		*v = d
+	case *[]string:
+		*v = splitList(raw)
+	default:
+		return fmt.Errorf("unsupported type %T", ptr)
+	}
+	return nil
+}
+
+// formatValue returns the text representation of the field pointed by ptr, the inverse of parseValue
+func formatValue(ptr any) string {
+	switch v := ptr.(type) {
+	case *string:
+		return *v
+	case *int:
+		return strconv.Itoa(*v)
+	case *time.Duration:
+		return v.String()
+	case *[]string:
+		return strings.Join(*v, ",")
+	default:
+		return fmt.Sprint(ptr)
+	}
+}
+
+// valueType is the type shown in the help of the flags
+func valueType(ptr any) string {
+	switch ptr.(type) {
+	case *int:
+		return "int"
+	case *time.Duration:
+		return "duration"
+	case *[]string:
+		return "strings"
+	default:
+		return "string"
+	}
+}
+
+// splitList splits a comma-separated list, ignoring the empty items
+func splitList(raw string) []string {
+	list := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// flagValue is the value of a flag, parsed into a copy of the defaults.
+// Load reads it through String after the file and the environment, so a flag only wins when it is set.
+type flagValue struct {
+	ptr any
+}
+
+func (f *flagValue) String() string       { return formatValue(f.ptr) }
+func (f *flagValue) Set(raw string) error { return parseValue(f.ptr, raw) }
+func (f *flagValue) Type() string         { return valueType(f.ptr) }
+
+// BindFlags adds a flag for every setting of the configuration, and the --config flag with the path of the file.
+// The defaults are only shown in the help, Load must receive the same defaults.
+func BindFlags(flags *pflag.FlagSet, defaults Config) {
+	flags.String(configFileSetting.flag, "", configFileSetting.usage+" (env "+configFileSetting.env+")")
+
+	values := defaults
+	values.BlockedIPRanges = slices.Clone(defaults.BlockedIPRanges)
+	values.TrustedProxies = slices.Clone(defaults.TrustedProxies)
+	values.Metrics.Rounds = slices.Clone(defaults.Metrics.Rounds)
+	for _, s := range settings {
+		flags.VarP(&flagValue{ptr: s.field(&values)}, s.flag, s.short, s.usage+" (env "+s.env+")")
+	}
+}
+
+// Load reads the configuration with the precedence flag > environment variable > file > defaults.
+// The file is given by the --config flag or the CONFIG_FILE variable. Only the flags set in the command line
+// replace the other sources. The configuration is validated, see Validate.
+func Load(defaults Config, flags *pflag.FlagSet) (Config, error) {
+	return load(defaults, flags, os.LookupEnv)
+}
+
+func load(defaults Config, flags *pflag.FlagSet, lookupEnv func(string) (string, bool)) (Config, error) {
+	cfg := defaults
+	cfg.BlockedIPRanges = slices.Clone(defaults.BlockedIPRanges)
+	cfg.TrustedProxies = slices.Clone(defaults.TrustedProxies)
+	cfg.Metrics.Rounds = slices.Clone(defaults.Metrics.Rounds)
+
+	path, _ := lookupEnv(configFileSetting.env)
+	if f := flags.Lookup(configFileSetting.flag); f != nil && f.Changed {
+		path = f.Value.String()
+	}
+	if path != "" {
+		if err := readFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	var errs []error
+	for _, s := range settings {
+		raw, ok := lookupEnv(s.env)
+		if f := flags.Lookup(s.flag); f != nil && f.Changed {
+			raw, ok = f.Value.String(), true
+		}
+		if !ok {
+			continue
+		}
+		if err := parseValue(s.field(&cfg), raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q: %w", s.key, raw, err))
+		}
+	}
+	if len(errs) > 0 {
+		return cfg, errors.Join(append([]error{ErrInvalidConfig}, errs...)...)
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// readFile reads the configuration file over the configuration, the unknown fields are rejected
+func readFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading the configuration: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return errors.Join(ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// Validate checks the configuration, every problem found is returned joined with ErrInvalidConfig
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		invalid("port: %q is not a valid port", c.Port)
+	}
+	if c.Redis.Addr == "" && c.Redis.URL == "" {
+		invalid("redis.addr or redis.url is required")
+	}
+	if _, err := c.Redis.Options(""); err != nil {
+		invalid("redis: %w", err)
+	}
+	if c.Redis.DB < 0 {
+		invalid("redis.db must not be negative")
+	}
+	if c.Redis.PoolSize < 1 {
+		invalid("redis.pool_size must be at least 1")
+	}
+	if c.Redis.MinIdleConns < 0 || c.Redis.MinIdleConns > c.Redis.PoolSize {
+		invalid("redis.min_idle_conns must be between 0 and redis.pool_size")
+	}
+	if c.Redis.MaxRetries < -1 {
+		invalid("redis.max_retries must be at least -1")
+	}
+	if c.Redis.DialTimeout < 0 || c.Redis.ReadTimeout < 0 || c.Redis.WriteTimeout < 0 {
+		invalid("redis timeouts must not be negative")
+	}
+
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			invalid("trusted_proxies: %q is not an IP or a CIDR", proxy)
+		}
+	}
+
+	if c.Query.CacheTTL < 0 {
+		invalid("query.cache_ttl must not be negative")
+	}
+	if c.Idempotency.TTL <= 0 {
+		invalid("idempotency.ttl must be positive")
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "", "debug", "info", "warn", "warning", "error":
+	default:
+		invalid("log.level: unknown level %q, expected one of debug, info, warn or error", c.Log.Level)
+	}
+	switch c.Tracing.Exporter {
+	case "", tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterConsole:
+	default:
+		invalid("tracing.exporter: unknown exporter %q, expected one of otlp, console or none", c.Tracing.Exporter)
+	}
+
+	if c.Shutdown.DrainDelay < 0 || c.Shutdown.Timeout < 0 {
+		invalid("shutdown durations must not be negative")
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(append([]error{ErrInvalidConfig}, errs...)...)
+	}
+	return nil
+}
+
+// Redacted returns a copy of the configuration with the secrets replaced, safe to be printed or logged
+func (c Config) Redacted() Config {
+	if c.Redis.Password != "" {
+		c.Redis.Password = redacted
+	}
+	if c.AdminToken != "" {
+		c.AdminToken = redacted
+	}
+	// the url keeps the redaction of net/url, the characters of redacted would be escaped
+	if u, err := url.Parse(c.Redis.URL); err == nil {
+		if query := u.Query(); query.Has("sentinel_password") {
+			query.Set("sentinel_password", "xxxxx")
+			u.RawQuery = query.Encode()
+		}
+		c.Redis.URL = u.Redacted()
+	}
+	return c
+}
+
+// YAML returns the configuration in the format of the configuration file, with the secrets redacted
+func (c Config) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), encoder.Close()
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+// env returns a lookup function over the given variables
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+// newFlags creates the flags of the configuration and parses the arguments
+func newFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(flags, Default())
+	assert.NoError(t, flags.Parse(args))
+	return flags
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("Should use the defaults when nothing is set", func(t *testing.T) {
+		// Act
+		cfg, err := load(Default(), newFlags(t), env(nil))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Default(), cfg)
+	})
+
+	t.Run("Should read the sources with the precedence flag, env and file", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		os.WriteFile(path, []byte("port: \"9000\"\nredis:\n  addr: file:6379\n  pool_size: 50\nlog:\n  level: debug\n"), 0o600)
+		flags := newFlags(t, "--config", path, "--port", "9002", "--blocked-ip-ranges", "10.0.0., 192.168.")
+
+		// Act
+		cfg, err := load(Default(), flags, env(map[string]string{
+			"PORT":             "9001",
+			"REDIS_ADDR":       "env:6379",
+			"SHUTDOWN_TIMEOUT": "30s",
+		}))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "9002", cfg.Port)
+		assert.Equal(t, "env:6379", cfg.Redis.Addr)
+		assert.Equal(t, 50, cfg.Redis.PoolSize)
+		assert.Equal(t, "debug", cfg.Log.Level)
+		assert.Equal(t, 30*time.Second, cfg.Shutdown.Timeout)
+		assert.Equal(t, []string{"10.0.0.", "192.168."}, cfg.BlockedIPRanges)
+	})
+
+	t.Run("Should read the file given by CONFIG_FILE", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		os.WriteFile(path, []byte("blocked_ip_ranges: [\"10.0.0.\"]\n"), 0o600)
+
+		// Act
+		cfg, err := load(Default(), newFlags(t), env(map[string]string{"CONFIG_FILE": path}))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"10.0.0."}, cfg.BlockedIPRanges)
+	})
+
+	invalid := map[string]struct {
+		file string
+		env  map[string]string
+	}{
+		"invalid env value":  {env: map[string]string{"REDIS_POOL_SIZE": "many"}},
+		"invalid port":       {env: map[string]string{"PORT": "http"}},
+		"unknown file field": {file: "redis:\n  size: 10\n"},
+		"empty pool":         {file: "redis:\n  pool_size: 0\n"},
+		"unknown log level":  {env: map[string]string{"LOG_LEVEL": "trace"}},
+		"unknown exporter":   {env: map[string]string{"OTEL_TRACES_EXPORTER": "jaeger"}},
+		"negative timeout":   {env: map[string]string{"SHUTDOWN_TIMEOUT": "-1s"}},
+		"negative cache ttl": {env: map[string]string{"QUERY_CACHE_TTL": "-500ms"}},
+		"invalid redis url":  {env: map[string]string{"REDIS_URL": "redis+sentinel://s1:26379"}},
+		"missing redis ca":   {env: map[string]string{"REDIS_TLS_CA_FILE": "/does/not/exist"}},
+		"invalid proxy":      {env: map[string]string{"TRUSTED_PROXIES": "10.0.0.0/33"}},
+	}
+	for name, tc := range invalid {
+		t.Run("Should reject the configuration with "+name, func(t *testing.T) {
+			// Arrange
+			vars := map[string]string{}
+			for k, v := range tc.env {
+				vars[k] = v
+			}
+			if tc.file != "" {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				os.WriteFile(path, []byte(tc.file), 0o600)
+				vars["CONFIG_FILE"] = path
+			}
+
+			// Act
+			_, err := load(Default(), newFlags(t), env(vars))
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+
+	t.Run("Should reject a flag with an invalid value", func(t *testing.T) {
+		// Arrange
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		BindFlags(flags, Default())
+
+		// Act
+		err := flags.Parse([]string{"--redis-dial-timeout", "soon"})
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestRedisConfigOptions(t *testing.T) {
+	t.Run("Should use the url of the configuration over its addr", func(t *testing.T) {
+		// Arrange
+		cfg := Default().Redis
+		cfg.URL = "redis+sentinel://s1:26379,s2:26379/mymaster"
+		cfg.Username, cfg.Password = "voter", "s3cr3t"
+		cfg.PoolSize = 20
+
+		// Act
+		o, err := cfg.Options("")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"s1:26379", "s2:26379"}, o.Addrs)
+		assert.Equal(t, "mymaster", o.MasterName)
+		assert.Equal(t, "voter", o.Username)
+		assert.Equal(t, "s3cr3t", o.Password)
+		assert.Equal(t, 20, o.PoolSize)
+	})
+
+	t.Run("Should use the addr or the url of the repository", func(t *testing.T) {
+		// Arrange
+		cfg := Default().Redis
+		cfg.URL = "redis+cluster://n1:7000"
+
+		// Act
+		byAddr, addrErr := cfg.Options("cache:6379")
+		byURL, urlErr := cfg.Options("redis://other:6379/3")
+
+		// Assert
+		assert.NoError(t, addrErr)
+		assert.NoError(t, urlErr)
+		assert.Equal(t, []string{"cache:6379"}, byAddr.Addrs)
+		assert.False(t, byAddr.Cluster)
+		assert.Equal(t, []string{"other:6379"}, byURL.Addrs)
+		assert.Equal(t, 3, byURL.DB)
+	})
+}
+
+func TestConfigYAML(t *testing.T) {
+	t.Run("Should print the configuration with the secrets redacted", func(t *testing.T) {
+		// Arrange
+		cfg := Default()
+		cfg.Redis.Password = "s3cr3t"
+		cfg.Redis.URL = "rediss+sentinel://voter:s3cr3t@s1:26379/mymaster?sentinel_password=s3cr3t"
+		cfg.AdminToken = "s3cr3t"
+
+		// Act
+		out, err := cfg.YAML()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotContains(t, string(out), "s3cr3t")
+		assert.Contains(t, string(out), "rediss+sentinel://voter:xxxxx@s1:26379/mymaster?sentinel_password=xxxxx")
+		assert.Contains(t, string(out), "password: '"+redacted+"'")
+		assert.Contains(t, string(out), "admin_token: '"+redacted+"'")
+		assert.Contains(t, string(out), "drain_delay: 5s")
+		assert.Equal(t, "s3cr3t", cfg.Redis.Password)
+	})
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	voteUsecase "github.com/sergiodii/bbb/internal/usecase/vote"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The repository types of the pipeline configuration
+const (
+	RepositoryRedis  = "redis"
+	RepositorySQL    = "sql"
+	RepositoryMemory = "memory"
+)
+
+// ErrInvalidPipeline is returned when the pipeline configuration is not valid
+var ErrInvalidPipeline = errors.New("invalid pipeline configuration")
+
+//go:embed default_pipeline.yaml
+var defaultPipeline []byte
+
+// commandHandlers and queryHandlers are the handlers that can be configured in the pipeline
+var (
+	commandHandlers = []voteUsecase.HandlerFuncEnum{
+		voteUsecase.HandlerFuncCreateVote,
+		voteUsecase.HandlerFuncCreateRound,
+	}
+	queryHandlers = []voteUsecase.HandlerFuncEnum{
+		voteUsecase.HandlerFuncGetTotalVotes,
+		voteUsecase.HandlerFuncGetTotalVotesForParticipant,
+		voteUsecase.HandlerFuncGetTotalVotesForHour,
+		voteUsecase.HandlerFuncGetWinner,
+		voteUsecase.HandlerFuncGetRanking,
+		voteUsecase.HandlerFuncGetTimeSeries,
+		voteUsecase.HandlerFuncGetUniqueVoters,
+	}
+)
+
+// Pipeline describes the repositories and, for each handler, how they are executed
+type Pipeline struct {
+	Repositories []RepositoryConfig `yaml:"repositories"`
+
+	// DeadLetter is the name of the repository that stores the votes that failed in the secondary repositories
+	DeadLetter string `yaml:"dead_letter"`
+
+	// Idempotency is the name of the repository that stores the idempotency keys of the votes,
+	// without it the Idempotency-Key of the votes is ignored
+	Idempotency string `yaml:"idempotency"`
+
+	Handlers map[voteUsecase.HandlerFuncEnum]HandlerConfig `yaml:"handlers"`
+}
+
+// RepositoryConfig describes a repository and its connection settings
+type RepositoryConfig struct {
+	Name string `yaml:"name"`
+
+	// Type is one of redis, sql or memory
+	Type string `yaml:"type"`
+
+	// Addr is the address of the Redis server, the default is the redis.addr of the configuration
+	Addr string `yaml:"addr"`
+}
+
+// HandlerConfig describes the pipe of a handler.
+// Without a handler configuration, every repository is used with the default execution type of the aggregator.
+type HandlerConfig struct {
+	// ExecutionType is the execution type of the pipe, see pipe.ParseExecutionType
+	ExecutionType string `yaml:"execution_type"`
+
+	// Repositories are the names of the repositories, in the order of the tasks
+	Repositories []string `yaml:"repositories"`
+
+	Timeout time.Duration  `yaml:"timeout"`
+	Retry   *RetryConfig   `yaml:"retry"`
+	Breaker *BreakerConfig `yaml:"breaker"`
+}
+
+// RetryConfig configures the retries of the transient errors (timeouts and network failures) of a task
+type RetryConfig struct {
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	Jitter         float64       `yaml:"jitter"`
+}
+
+// BreakerConfig configures the circuit breaker of a task
+type BreakerConfig struct {
+	Threshold int           `yaml:"threshold"`
+	Cooldown  time.Duration `yaml:"cooldown"`
+}
+
+// IsTransient returns true for the errors worth retrying: timeouts and network failures
+func IsTransient(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, pipe.ErrTaskTimeout) || errors.As(err, &netErr)
+}
+
+// Policy returns the task policy of the handler
+func (h HandlerConfig) Policy() pipe.TaskPolicy {
+	policy := pipe.TaskPolicy{Timeout: h.Timeout}
+	if h.Retry != nil {
+		policy.Retry = &pipe.RetryPolicy{
+			MaxAttempts:    h.Retry.MaxAttempts,
+			InitialBackoff: h.Retry.InitialBackoff,
+			MaxBackoff:     h.Retry.MaxBackoff,
+			Jitter:         h.Retry.Jitter,
+			RetryOn:        IsTransient,
+		}
+	}
+	if h.Breaker != nil {
+		policy.Breaker = &pipe.BreakerPolicy{
+			Threshold: h.Breaker.Threshold,
+			Cooldown:  h.Breaker.Cooldown,
+		}
+	}
+	return policy
+}
+
+// repository returns the configuration of the repository with the given name
+func (p *Pipeline) repository(name string) (RepositoryConfig, bool) {
+	for _, r := range p.Repositories {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return RepositoryConfig{}, false
+}
+
+// Validate checks the pipeline configuration, every problem found is returned joined with ErrInvalidPipeline
+func (p *Pipeline) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if len(p.Repositories) == 0 {
+		invalid("no repository configured")
+	}
+
+	names := map[string]bool{}
+	for i, r := range p.Repositories {
+		switch {
+		case r.Name == "":
+			invalid("repositories[%d]: name is required", i)
+		case names[r.Name]:
+			invalid("repositories[%d]: duplicated name %q", i, r.Name)
+		}
+		names[r.Name] = true
+
+		switch r.Type {
+		case RepositoryRedis, RepositorySQL, RepositoryMemory:
+		default:
+			invalid("repository %q: unknown type %q, expected one of redis, sql or memory", r.Name, r.Type)
+		}
+	}
+
+	if p.DeadLetter != "" && !names[p.DeadLetter] {
+		invalid("dead_letter: unknown repository %q", p.DeadLetter)
+	}
+	if p.Idempotency != "" && !names[p.Idempotency] {
+		invalid("idempotency: unknown repository %q", p.Idempotency)
+	}
+
+	for handler, h := range p.Handlers {
+		if !slices.Contains(commandHandlers, handler) && !slices.Contains(queryHandlers, handler) {
+			invalid("handler %q: unknown handler", handler)
+			continue
+		}
+
+		if h.ExecutionType != "" && pipe.ParseExecutionType(h.ExecutionType) == "" {
+			invalid("handler %q: unknown execution type %q", handler, h.ExecutionType)
+		}
+
+		for _, name := range h.Repositories {
+			if !names[name] {
+				invalid("handler %q: unknown repository %q", handler, name)
+			}
+		}
+
+		if h.Retry != nil && h.Retry.MaxAttempts < 1 {
+			invalid("handler %q: retry.max_attempts must be at least 1", handler)
+		}
+		if h.Breaker != nil && h.Breaker.Threshold < 1 {
+			invalid("handler %q: breaker.threshold must be at least 1", handler)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(append([]error{ErrInvalidPipeline}, errs...)...)
+	}
+	return nil
+}
+
+// ParsePipeline reads a pipeline configuration in YAML.
+// The environment variables (${VAR}) are expanded and the unknown fields are rejected.
+func ParsePipeline(data []byte) (*Pipeline, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(os.ExpandEnv(string(data))))
+	decoder.KnownFields(true)
+
+	p := &Pipeline{}
+	if err := decoder.Decode(p); err != nil {
+		return nil, errors.Join(ErrInvalidPipeline, err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LoadPipeline reads the pipeline configuration file.
+// Without a path, the default pipeline is used: a single Redis repository at the redis.addr of the configuration.
+func LoadPipeline(path string, logger *slog.Logger) (*Pipeline, error) {
+	if path == "" {
+		logger.Debug("using the default pipeline configuration")
+		return ParsePipeline(defaultPipeline)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading the pipeline configuration: %w", err)
+	}
+
+	logger.Debug("using the pipeline configuration file", "path", path)
+	return ParsePipeline(data)
+}
+
+// Repositories are the repositories built from the pipeline configuration
+type Repositories struct {
+	names       []string
+	byName      map[string]repository.RoundRepository
+	deadLetters repository.DeadLetterRepository
+	idempotency repository.IdempotencyRepository
+}
+
+// DeadLetters returns the dead letter repository, nil when no dead letter is configured
+func (r Repositories) DeadLetters() repository.DeadLetterRepository {
+	return r.deadLetters
+}
+
+// Idempotency returns the idempotency repository, nil when no idempotency is configured
+func (r Repositories) Idempotency() repository.IdempotencyRepository {
+	return r.idempotency
+}
+
+// Names returns the names of the repositories in the order they were declared
+func (r Repositories) Names() []string {
+	return slices.Clone(r.names)
+}
+
+// Get returns the repository with the given name
+func (r Repositories) Get(name string) repository.RoundRepository {
+	return r.byName[name]
+}
+
+// List returns the repositories with the given names, or every repository, in the order they were declared, without names
+func (r Repositories) List(names ...string) []repository.RoundRepository {
+	if len(names) == 0 {
+		names = r.names
+	}
+
+	repos := make([]repository.RoundRepository, 0, len(names))
+	for _, name := range names {
+		repos = append(repos, r.byName[name])
+	}
+	return repos
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	voteUsecase "github.com/sergiodii/bbb/internal/usecase/vote"
+	"github.com/sergiodii/bbb/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePipeline(t *testing.T) {
+	t.Run("Should load the default pipeline", func(t *testing.T) {
+		// Act
+		p, err := LoadPipeline("", logger.Discard())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []RepositoryConfig{{Name: "redis", Type: RepositoryRedis}}, p.Repositories)
+		assert.Equal(t, "redis", p.DeadLetter)
+		assert.Equal(t, "redis", p.Idempotency)
+		assert.Equal(t, "SEQUENTIAL_BLOCKING_ONLY_FIRST", p.Handlers[voteUsecase.HandlerFuncCreateVote].ExecutionType)
+
+		policy := p.Handlers[voteUsecase.HandlerFuncGetTotalVotesForHour].Policy()
+		assert.Equal(t, 500*time.Millisecond, policy.Timeout)
+		assert.Equal(t, 2, policy.Retry.MaxAttempts)
+		assert.True(t, policy.Retry.RetryOn(pipe.ErrTaskTimeout))
+		assert.False(t, policy.Retry.RetryOn(assert.AnError))
+		assert.Equal(t, &pipe.BreakerPolicy{Threshold: 5, Cooldown: 10 * time.Second}, policy.Breaker)
+
+		for _, handler := range queryHandlers {
+			assert.Equal(t, p.Handlers[voteUsecase.HandlerFuncGetTotalVotes], p.Handlers[handler], "handler %s", handler)
+		}
+	})
+
+	t.Run("Should read the pipeline from a file", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "pipeline.yaml")
+		os.WriteFile(path, []byte("repositories:\n  - name: cache\n    type: memory\n"), 0o600)
+
+		// Act
+		p, err := LoadPipeline(path, logger.Discard())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []RepositoryConfig{{Name: "cache", Type: RepositoryMemory}}, p.Repositories)
+		assert.Empty(t, p.Handlers)
+	})
+
+	invalid := map[string]string{
+		"no repository":          "repositories: []\n",
+		"unknown field":          "repositories:\n  - name: cache\n    type: memory\n    size: 10\n",
+		"unknown repository":     "repositories:\n  - name: cache\n    type: mongo\n",
+		"duplicated repository":  "repositories:\n  - name: cache\n    type: memory\n  - name: cache\n    type: sql\n",
+		"unknown dead letter":    "repositories:\n  - name: cache\n    type: memory\ndead_letter: redis\n",
+		"unknown idempotency":    "repositories:\n  - name: cache\n    type: memory\nidempotency: redis\n",
+		"unknown handler":        "repositories:\n  - name: cache\n    type: memory\nhandlers:\n  DeleteVote: {}\n",
+		"unknown execution type": "repositories:\n  - name: cache\n    type: memory\nhandlers:\n  CreateVote:\n    execution_type: PARALLEL\n",
+		"unknown task":           "repositories:\n  - name: cache\n    type: memory\nhandlers:\n  CreateVote:\n    repositories: [redis]\n",
+		"invalid retry":          "repositories:\n  - name: cache\n    type: memory\nhandlers:\n  GetTotalVotes:\n    retry:\n      max_attempts: 0\n",
+	}
+	for name, data := range invalid {
+		t.Run("Should return a validation error for "+name, func(t *testing.T) {
+			// Act
+			_, err := ParsePipeline([]byte(data))
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidPipeline)
+		})
+	}
+}
+
+func TestPipelineBuild(t *testing.T) {
+	t.Run("Should build the aggregators following the handlers of the pipeline", func(t *testing.T) {
+		// Arrange
+		p, err := ParsePipeline([]byte(`
+repositories:
+  - name: shard-a
+    type: memory
+  - name: shard-b
+    type: memory
+  - name: unused
+    type: memory
+handlers:
+  CreateVote:
+    execution_type: SEQUENTIAL
+    repositories: [shard-a, shard-b]
+  GetTotalVotes:
+    execution_type: CONCURRENT_MERGE
+    repositories: [shard-a, shard-b]
+  GetTotalVotesForParticipant:
+    repositories: [shard-b]
+  GetTotalVotesForHour:
+    repositories: [shard-a]
+  GetWinner:
+    execution_type: CONCURRENT_MERGE
+    repositories: [shard-a, shard-b]
+  GetRanking:
+    repositories: [shard-a]
+  GetTimeSeries:
+    repositories: [shard-b]
+  GetUniqueVoters:
+    repositories: [shard-a]
+`))
+		assert.NoError(t, err)
+
+		var wrapped []string
+		repos, err := p.NewRepositories(Default().Redis, slog.Default(), func(name string, repo repository.RoundRepository) repository.RoundRepository {
+			wrapped = append(wrapped, name)
+			return repo
+		})
+		assert.NoError(t, err)
+
+		// Act
+		command, commandRepos, commandErr := p.NewCommandAggregator(repos)
+		query, queryRepos, queryErr := p.NewQueryAggregator(repos)
+
+		// Assert
+		assert.NoError(t, commandErr)
+		assert.NoError(t, queryErr)
+		assert.Equal(t, []string{"shard-a", "shard-b", "unused"}, wrapped)
+		assert.Equal(t, []string{"shard-a", "shard-b"}, commandRepos)
+		assert.Equal(t, []string{"shard-a", "shard-b"}, queryRepos)
+
+		err = command.GetAggregatedUseCase().CreateVote(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "alice"})
+		assert.NoError(t, err)
+
+		total, err := query.GetAggregatedUseCase().GetTotalVotes(context.Background(), "round1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+
+		participants, err := query.GetAggregatedUseCase().GetTotalVotesForParticipant(context.Background(), "round1")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"alice": 1}, participants)
+
+		unused, _ := repos.Get("unused").GetTotalVotes(context.Background(), "round1")
+		assert.Equal(t, 0, unused)
+	})
+}
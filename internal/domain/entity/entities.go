@@ -1,5 +1,7 @@
 package entity
 
+import "github.com/sergiodii/bbb/internal/domain/errs"
+
 type Round struct {
 	ID           string
 	Nome         string
@@ -12,9 +14,69 @@ type Participant struct {
 	Nome string
 }
 
+// Validate checks the round, the round and its participants need an ID
+func (r Round) Validate() error {
+	if r.ID == "" {
+		return errs.New(errs.ErrValidation, "round_id is required")
+	}
+	for _, p := range r.Participants {
+		if p.ID == "" {
+			return errs.New(errs.ErrValidation, "the id of every participant is required")
+		}
+	}
+	return nil
+}
+
 type Vote struct {
 	RoundID       string
 	ParticipantID string
 	Timestamp     int64
 	IP            string
+
+	// VoterID identifies the authenticated voter, empty when the voter is anonymous
+	VoterID string
+}
+
+// Voter identifies who voted, to count the unique voters: the authenticated voter or else the IP.
+// It is empty when neither is known, and the vote is then not counted as a voter.
+func (v Vote) Voter() string {
+	if v.VoterID != "" {
+		return "id:" + v.VoterID
+	}
+	if v.IP != "" {
+		return "ip:" + v.IP
+	}
+	return ""
+}
+
+// Validate checks the vote, the round and the participant are required
+func (v Vote) Validate() error {
+	switch {
+	case v.RoundID == "":
+		return errs.New(errs.ErrValidation, "round_id is required")
+	case v.ParticipantID == "":
+		return errs.New(errs.ErrValidation, "participant_id is required")
+	}
+	return nil
+}
+
+// UniqueVoters is the approximate number of distinct voters of a round, overall and of each participant
+type UniqueVoters struct {
+	Total        int
+	Participants map[string]int
+}
+
+// DeadLetter is a vote that could not be registered in one of the repositories,
+// kept to be replayed later
+type DeadLetter struct {
+	Vote Vote
+
+	// RepositoryIndex is the position of the repository in the command aggregator
+	RepositoryIndex int
+
+	// Repository is the name of the repository, used to check the index on replay
+	Repository string
+
+	Error    string
+	FailedAt int64
 }
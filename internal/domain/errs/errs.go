@@ -0,0 +1,73 @@
+// Package errs defines the errors of the domain. The repositories and the use cases wrap their errors
+// in one of the kinds, so the APIs answer with the right status without exposing the internal errors.
+package errs
+
+import "errors"
+
+// The kinds of the domain errors, matched with errors.Is
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrValidation  = errors.New("validation failed")
+	ErrConflict    = errors.New("conflict")
+	ErrRateLimited = errors.New("rate limited")
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// kinds are the kinds checked by KindOf, in order
+var kinds = []error{ErrValidation, ErrNotFound, ErrConflict, ErrRateLimited, ErrUnavailable}
+
+// Error is an error of the domain.
+// Message is safe to show to the clients, Err is the internal cause, which is only logged.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the kind and the cause, so errors.Is matches both
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// New creates an error of the kind with the message shown to the clients
+func New(kind error, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap creates an error of the kind caused by err, the message is shown to the clients instead of err
+func Wrap(kind error, err error, message string) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// KindOf returns the kind of the error, nil when it is not an error of the domain
+func KindOf(err error) error {
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// Message returns the message of the domain error shown to the clients.
+// Without a message, the kind is the message; it is empty when err is not an error of the domain.
+func Message(err error) string {
+	var e *Error
+	if errors.As(err, &e) && e.Message != "" {
+		return e.Message
+	}
+	if kind := KindOf(err); kind != nil {
+		return kind.Error()
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	t.Run("Should match the kind and the cause", func(t *testing.T) {
+		// Arrange
+		cause := errors.New("dial tcp: connection refused")
+
+		// Act
+		err := fmt.Errorf("query: %w", Wrap(ErrUnavailable, cause, "the vote store is unavailable"))
+
+		// Assert
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, ErrUnavailable, KindOf(err))
+		assert.Equal(t, "the vote store is unavailable", Message(err))
+		assert.Equal(t, "query: the vote store is unavailable: dial tcp: connection refused", err.Error())
+	})
+
+	t.Run("Should find the kind in joined errors", func(t *testing.T) {
+		// Act
+		err := errors.Join(errors.New("timeout"), New(ErrValidation, "participant_id is required"))
+
+		// Assert
+		assert.Equal(t, ErrValidation, KindOf(err))
+		assert.Equal(t, "participant_id is required", Message(err))
+	})
+
+	t.Run("Should use the kind as the message of a kind without message", func(t *testing.T) {
+		// Act
+		err := fmt.Errorf("round1: %w", ErrNotFound)
+
+		// Assert
+		assert.Equal(t, ErrNotFound, KindOf(err))
+		assert.Equal(t, "not found", Message(err))
+	})
+
+	t.Run("Should not expose the errors outside the domain", func(t *testing.T) {
+		// Act
+		err := errors.New("redis: nil")
+
+		// Assert
+		assert.Nil(t, KindOf(err))
+		assert.Empty(t, Message(err))
+	})
+}
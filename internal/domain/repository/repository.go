@@ -2,13 +2,93 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/errs"
 )
 
+// ErrRoundNotFound is returned by the queries of a RoundRepository for a round it does not know.
+// A round is known once it is created (see RoundRegistry) or gets its first vote;
+// a known round without votes is not an error, its total is zero and its counters are empty.
+var ErrRoundNotFound = errs.New(errs.ErrNotFound, "round not found")
+
+// RoundRepository stores the counters of the votes of the rounds.
+// The queries return ErrRoundNotFound for the rounds the repository does not know.
 type RoundRepository interface {
 	VoteRegister(ctx context.Context, vote entity.Vote) error
 	GetTotalVotes(ctx context.Context, roundID string) (int, error)
 	GetTotalForParticipant(ctx context.Context, roundID string) (map[string]int, error)
 	GetTotalForHour(ctx context.Context, roundID string) (map[string]int, error)
 }
+
+// RoundRegistry is an optional interface a RoundRepository can implement to store the metadata of the rounds,
+// so a round is known before its first vote. Creating a round that already exists updates its metadata.
+type RoundRegistry interface {
+	CreateRound(ctx context.Context, round entity.Round) error
+}
+
+// VoterCounter is an optional interface a RoundRepository can implement to count the unique voters of the rounds,
+// see entity.Vote.Voter. The counts can be approximate. It returns ErrRoundNotFound for an unknown round.
+// A decorator whose decorated repository does not count the voters returns errors.ErrUnsupported.
+type VoterCounter interface {
+	GetUniqueVoters(ctx context.Context, roundID string) (entity.UniqueVoters, error)
+}
+
+// HealthChecker is an optional interface a RoundRepository can implement
+// to report whether its backing store is reachable.
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+// Unwrapper is implemented by repositories that decorate another repository,
+// for example to add metrics or tracing
+type Unwrapper interface {
+	Unwrap() RoundRepository
+}
+
+// Unwrap returns the innermost repository of a chain of decorators
+func Unwrap(repo RoundRepository) RoundRepository {
+	for {
+		u, ok := repo.(Unwrapper)
+		if !ok {
+			return repo
+		}
+		repo = u.Unwrap()
+	}
+}
+
+// DeadLetterRepository stores the votes that failed to be registered in a repository
+type DeadLetterRepository interface {
+	Push(ctx context.Context, letter entity.DeadLetter) error
+
+	// Pop removes and returns the oldest dead letter, the boolean is false when the store is empty
+	Pop(ctx context.Context) (entity.DeadLetter, bool, error)
+}
+
+// IdempotencyState is the state of an idempotency key, see IdempotencyRepository
+type IdempotencyState int
+
+const (
+	// IdempotencyReserved is the state of a key reserved by the call, its request must be processed
+	IdempotencyReserved IdempotencyState = iota
+
+	// IdempotencyPending is the state of a key whose request is being processed by another call
+	IdempotencyPending
+
+	// IdempotencyDone is the state of a key whose request was processed, it must not be processed again
+	IdempotencyDone
+)
+
+// IdempotencyRepository stores the idempotency keys of the requests, so the retries of a request
+// that succeeded are not processed again. The keys expire after the TTL.
+type IdempotencyRepository interface {
+	// Reserve reserves the key when it is unknown, returning IdempotencyReserved, or returns its state
+	Reserve(ctx context.Context, key string, ttl time.Duration) (IdempotencyState, error)
+
+	// Complete marks the request of the reserved key as processed
+	Complete(ctx context.Context, key string, ttl time.Duration) error
+
+	// Release removes the reserved key of a request that failed, so it can be retried
+	Release(ctx context.Context, key string) error
+}
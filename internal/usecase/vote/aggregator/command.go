@@ -2,52 +2,241 @@ package aggregator
 
 import (
 	"context"
-	"sync"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/sergiodii/bbb/extension/pipe"
 	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/errs"
 	"github.com/sergiodii/bbb/internal/domain/repository"
 	voteUsecase "github.com/sergiodii/bbb/internal/usecase/vote"
 	commandVoteUsecase "github.com/sergiodii/bbb/internal/usecase/vote/command"
 )
 
-var commandAggregated *commandAggregator
+// ErrDeadLetterRepository is returned by ReplayDeadLetters when a dead letter points to a repository
+// that is not configured in the aggregator anymore
+var ErrDeadLetterRepository = errors.New("dead letter repository not found")
 
-var commandAggregatedOnce sync.Once
+// ErrNoRepository is returned when a handler has no repository to run
+var ErrNoRepository = errors.New("handler without repositories")
+
+// backgroundRetryPolicy is used to retry the votes registered in the secondary repositories
+var backgroundRetryPolicy = pipe.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     time.Second,
+}
 
 type commandAggregator struct {
 	repositories []repository.RoundRepository
+	config
+	useCase      commandVoteUsecase.CommandVoteUseCase
+	registerVote pipe.Pipe[entity.Vote]
+}
+
+// voteRepositories returns the repositories that register the votes, in the order of the tasks
+func (a *commandAggregator) voteRepositories() []repository.RoundRepository {
+	return a.handlerRepositories(voteUsecase.HandlerFuncCreateVote, a.repositories)
+}
+
+func repositoryName(repo repository.RoundRepository) string {
+	return fmt.Sprintf("%T", repository.Unwrap(repo))
+}
+
+// deadLetterSink stores the votes that the secondary repositories failed to register, so they can be replayed
+func (a *commandAggregator) deadLetterSink(ctx context.Context, err error) {
+	var taskErr *pipe.TaskError
+	vote, ok := entity.Vote{}, false
+	if errors.As(err, &taskErr) {
+		vote, ok = taskErr.Input.(entity.Vote)
+	}
+	repos := a.voteRepositories()
+	if !ok || taskErr.Index >= len(repos) {
+		a.logger.ErrorContext(ctx, "non-blocking task failed", "error", err)
+		return
+	}
+
+	letter := entity.DeadLetter{
+		Vote:            vote,
+		RepositoryIndex: taskErr.Index,
+		Repository:      repositoryName(repos[taskErr.Index]),
+		Error:           taskErr.Err.Error(),
+		FailedAt:        time.Now().Unix(),
+	}
+
+	a.logger.WarnContext(ctx, "vote sent to the dead letter store", "repository", letter.Repository, "round_id", vote.RoundID, "error", taskErr.Err)
+	if err := a.deadLetters.Push(ctx, letter); err != nil {
+		a.logger.ErrorContext(ctx, "vote lost, dead letter store failed", "repository", letter.Repository, "round_id", vote.RoundID, "error", err)
+	}
+}
+
+// keepVote is the reducer used when the votes are registered with CONCURRENT_MERGE, the vote is not changed by the repositories
+func keepVote(input entity.Vote, outputs []entity.Vote) (entity.Vote, error) {
+	return input, nil
 }
 
-func (a *commandAggregator) aggregateVoteRegisterHandler() pipe.Pipe[entity.Vote] {
-	p := pipe.NewPipe[entity.Vote](pipe.SEQUENTIAL_BLOCKING_ONLY_FIRST)
-	for _, exec := range a.repositories {
-		p.Enqueue(func(ctx context.Context, dto entity.Vote) (entity.Vote, error) {
+func (a *commandAggregator) aggregateVoteRegisterHandler() (pipe.Pipe[entity.Vote], error) {
+	handler := voteUsecase.HandlerFuncCreateVote
+	opts := []pipe.Option{pipe.WithLogger(a.logger), pipe.WithBackgroundRetry(backgroundRetryPolicy), pipe.WithReducer(keepVote)}
+	if a.deadLetters != nil {
+		opts = append(opts, pipe.WithErrorSink(a.deadLetterSink))
+	}
+
+	p, err := pipe.New[entity.Vote](a.executionType(handler, pipe.SEQUENTIAL_BLOCKING_ONLY_FIRST), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", handler, err)
+	}
+
+	repos := a.voteRepositories()
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("%s: %w", handler, ErrNoRepository)
+	}
+
+	policy := a.policy(handler)
+	for _, exec := range repos {
+		p.Enqueue(applyPolicy(func(ctx context.Context, dto entity.Vote) (entity.Vote, error) {
 			err := exec.VoteRegister(ctx, dto)
 			if err != nil {
 				return dto, err
 			}
 			return dto, nil
-		})
+		}, policy))
 	}
-	return p
+	return p, nil
 }
 
-func (a *commandAggregator) GetAggregatedUseCase() commandVoteUsecase.CommandVoteUseCase {
+// aggregateCreateRoundHandler creates the pipe that stores the rounds in every repository that supports them.
+// By default the rounds are stored SEQUENTIAL in the repositories that register the votes,
+// so a round is known by every one of them once it is created.
+func (a *commandAggregator) aggregateCreateRoundHandler() (pipe.Pipe[entity.Round], error) {
+	handler := voteUsecase.HandlerFuncCreateRound
+	p, err := pipe.New[entity.Round](a.executionType(handler, pipe.SEQUENTIAL), pipe.WithLogger(a.logger), pipe.WithReducer(keepRound))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", handler, err)
+	}
 
-	executionMap := map[voteUsecase.HandlerFuncEnum]voteUsecase.Pipe[entity.Vote]{
-		voteUsecase.HandlerFuncCreateVote: a.aggregateVoteRegisterHandler(),
+	repos := a.handlerRepositories(handler, a.voteRepositories())
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("%s: %w", handler, ErrNoRepository)
+	}
+
+	policy := a.policy(handler)
+	for _, exec := range repos {
+		p.Enqueue(applyPolicy(func(ctx context.Context, round entity.Round) (entity.Round, error) {
+			registry, ok := exec.(repository.RoundRegistry)
+			if !ok {
+				// the repository does not store rounds, it only knows the rounds with votes
+				return round, nil
+			}
+			return round, registry.CreateRound(ctx, round)
+		}, policy))
+	}
+	return p, nil
+}
+
+// applyPolicy decorates the task with the policy. The repository skipped by its open circuit or that did not
+// answer in time is unavailable, not failing: the errors are wrapped as errs.ErrUnavailable, so the clients
+// receive a 503 they can retry, and still match pipe.ErrCircuitOpen and pipe.ErrTaskTimeout.
+// They do not match pipe.ONF, otherwise the command pipes would skip the repository and answer with a success.
+func applyPolicy[T any](task func(context.Context, T) (T, error), policy pipe.TaskPolicy) func(context.Context, T) (T, error) {
+	task = pipe.ApplyPolicy(task, policy)
+	return func(ctx context.Context, input T) (T, error) {
+		output, err := task(ctx, input)
+		switch {
+		case errors.Is(err, pipe.ErrCircuitOpen):
+			err = errs.Wrap(errs.ErrUnavailable, notSkipped{err}, "the vote store is unavailable")
+		case errors.Is(err, pipe.ErrTaskTimeout):
+			err = errs.Wrap(errs.ErrUnavailable, notSkipped{err}, "the vote store did not answer in time")
+		}
+		return output, err
 	}
-	return commandVoteUsecase.NewCommandVote(executionMap)
 }
 
-func NewCommandAggregator(repos ...repository.RoundRepository) CommandAggregator {
+// notSkipped is an error of a repository that must not be skipped by the pipes:
+// it matches the same errors as err, except pipe.ONF
+type notSkipped struct {
+	err error
+}
+
+func (e notSkipped) Error() string {
+	return e.err.Error()
+}
+
+func (e notSkipped) Is(target error) bool {
+	return target != pipe.ONF && errors.Is(e.err, target)
+}
+
+// keepRound is the reducer used when the rounds are stored with CONCURRENT_MERGE
+func keepRound(input entity.Round, outputs []entity.Round) (entity.Round, error) {
+	return input, nil
+}
+
+func (a *commandAggregator) GetAggregatedUseCase() commandVoteUsecase.CommandVoteUseCase {
+	return a.useCase
+}
+
+// Wait blocks until the votes being registered in the secondary repositories are done.
+// It must be called after the requests are finished, e.g. on shutdown before closing the repositories.
+func (a *commandAggregator) Wait(ctx context.Context) error {
+	return pipe.Wait(ctx, a.registerVote)
+}
+
+// ReplayDeadLetters registers again the votes of the dead letter store in the repository that failed.
+// It stops on the first failure, putting the dead letter back in the store, and returns the number of votes replayed.
+func (a *commandAggregator) ReplayDeadLetters(ctx context.Context) (int, error) {
+	if a.deadLetters == nil {
+		return 0, nil
+	}
 
-	commandAggregatedOnce.Do(func() {
-		commandAggregated = &commandAggregator{
-			repositories: repos,
+	replayed := 0
+	for {
+		letter, ok, err := a.deadLetters.Pop(ctx)
+		if err != nil || !ok {
+			return replayed, err
 		}
-	})
 
-	return commandAggregated
+		err = ErrDeadLetterRepository
+		repos := a.voteRepositories()
+		if letter.RepositoryIndex < len(repos) && repositoryName(repos[letter.RepositoryIndex]) == letter.Repository {
+			err = repos[letter.RepositoryIndex].VoteRegister(ctx, letter.Vote)
+		}
+
+		if err != nil {
+			return replayed, errors.Join(err, a.deadLetters.Push(ctx, letter))
+		}
+		replayed++
+	}
+}
+
+// NewCommandAggregator creates the command aggregator with the default options
+func NewCommandAggregator(repos ...repository.RoundRepository) (CommandAggregator, error) {
+	return NewCommandAggregatorWithOptions(repos)
+}
+
+// NewCommandAggregatorWithOptions creates the command aggregator configured by the options,
+// see WithDeadLetter, WithTaskPolicy, WithExecutionType and WithHandlerRepositories.
+// It returns an error when a handler can not be built from the options.
+func NewCommandAggregatorWithOptions(repos []repository.RoundRepository, opts ...Option) (CommandAggregator, error) {
+	a := &commandAggregator{
+		repositories: repos,
+		config:       newConfig(opts...),
+	}
+
+	registerVote, err := a.aggregateVoteRegisterHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	createRound, err := a.aggregateCreateRoundHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	executionMap := map[voteUsecase.HandlerFuncEnum]voteUsecase.Pipe[entity.Vote]{
+		voteUsecase.HandlerFuncCreateVote: registerVote,
+	}
+	a.registerVote = registerVote
+	a.useCase = commandVoteUsecase.NewCommandVoteWithRounds(executionMap, createRound)
+	return a, nil
 }
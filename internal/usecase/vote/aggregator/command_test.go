@@ -0,0 +1,214 @@
+package aggregator
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/errs"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	voteUsecase "github.com/sergiodii/bbb/internal/usecase/vote"
+	"github.com/sergiodii/bbb/pkg/localsql"
+	"github.com/sergiodii/bbb/pkg/logger"
+	"github.com/sergiodii/bbb/pkg/problem"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingRepository struct {
+	m     sync.Mutex
+	err   error
+	votes []entity.Vote
+	calls int
+}
+
+func (r *failingRepository) setErr(err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.err = err
+}
+
+func (r *failingRepository) VoteRegister(ctx context.Context, vote entity.Vote) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.votes = append(r.votes, vote)
+	return nil
+}
+
+func (r *failingRepository) GetTotalVotes(ctx context.Context, roundID string) (int, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.calls++
+	return len(r.votes), r.err
+}
+
+func (r *failingRepository) GetTotalForParticipant(ctx context.Context, roundID string) (map[string]int, error) {
+	return nil, r.err
+}
+
+func (r *failingRepository) GetTotalForHour(ctx context.Context, roundID string) (map[string]int, error) {
+	return nil, r.err
+}
+
+func TestCommandAggregatorDeadLetter(t *testing.T) {
+	t.Run("Should store the votes that failed in a secondary repository and replay them", func(t *testing.T) {
+		// Arrange
+		primary := &failingRepository{}
+		secondary := &failingRepository{err: assert.AnError}
+		deadLetters := localsql.NewLocalSqlDeadLetterRepository()
+
+		a, err := NewCommandAggregatorWithOptions([]repository.RoundRepository{primary, secondary}, WithDeadLetter(deadLetters))
+		assert.NoError(t, err)
+		vote := entity.Vote{RoundID: "round1", ParticipantID: "participant1", Timestamp: 1625079600}
+
+		// Act
+		err = a.GetAggregatedUseCase().CreateVote(context.Background(), vote)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			letter, ok, _ := deadLetters.Pop(context.Background())
+			if ok {
+				assert.Equal(t, vote, letter.Vote)
+				assert.Equal(t, 1, letter.RepositoryIndex)
+				deadLetters.Push(context.Background(), letter)
+			}
+			return ok
+		}, 2*time.Second, 10*time.Millisecond)
+
+		// Act
+		// while the secondary repository is still failing, the vote goes back to the store
+		replayed, err := a.ReplayDeadLetters(context.Background())
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 0, replayed)
+
+		secondary.setErr(nil)
+		replayed, err = a.ReplayDeadLetters(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, replayed)
+		assert.Equal(t, []entity.Vote{vote}, secondary.votes)
+		assert.Equal(t, []entity.Vote{vote}, primary.votes)
+	})
+}
+
+func TestCommandAggregatorWait(t *testing.T) {
+	t.Run("Should wait for the votes registered in the secondary repositories", func(t *testing.T) {
+		// Arrange
+		primary := &failingRepository{}
+		secondary := &failingRepository{}
+		a, err := NewCommandAggregator(primary, secondary)
+		assert.NoError(t, err)
+
+		for range 10 {
+			a.GetAggregatedUseCase().CreateVote(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "participant1"})
+		}
+
+		// Act
+		err = a.Wait(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+		total, _ := secondary.GetTotalVotes(context.Background(), "round1")
+		assert.Equal(t, 10, total)
+	})
+}
+
+// failingRegistry also stores the rounds, failing like the votes
+type failingRegistry struct {
+	failingRepository
+}
+
+func (r *failingRegistry) CreateRound(ctx context.Context, round entity.Round) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.err
+}
+
+// slowRepository registers the votes after the delay, or gives up when the context is done
+type slowRepository struct {
+	failingRepository
+	delay time.Duration
+}
+
+func (r *slowRepository) VoteRegister(ctx context.Context, vote entity.Vote) error {
+	select {
+	case <-time.After(r.delay):
+		return r.failingRepository.VoteRegister(ctx, vote)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestCommandAggregatorUnavailable(t *testing.T) {
+	t.Run("Should answer unavailable when the primary repository is skipped or does not answer in time", func(t *testing.T) {
+		// Arrange
+		broken := &failingRepository{err: assert.AnError}
+		slow := &slowRepository{delay: 50 * time.Millisecond}
+		withBreaker, err := NewCommandAggregatorWithOptions([]repository.RoundRepository{broken},
+			WithTaskPolicy(voteUsecase.HandlerFuncCreateVote, pipe.TaskPolicy{
+				Breaker: &pipe.BreakerPolicy{Threshold: 1, Cooldown: time.Hour},
+			}),
+		)
+		assert.NoError(t, err)
+		withTimeout, err := NewCommandAggregatorWithOptions([]repository.RoundRepository{slow},
+			WithTaskPolicy(voteUsecase.HandlerFuncCreateVote, pipe.TaskPolicy{Timeout: time.Millisecond}),
+		)
+		assert.NoError(t, err)
+		vote := entity.Vote{RoundID: "round1", ParticipantID: "participant1"}
+		failedErr := withBreaker.GetAggregatedUseCase().CreateVote(context.Background(), vote)
+
+		// Act
+		openErr := withBreaker.GetAggregatedUseCase().CreateVote(context.Background(), vote)
+		timeoutErr := withTimeout.GetAggregatedUseCase().CreateVote(context.Background(), vote)
+
+		// Assert
+		assert.ErrorIs(t, failedErr, assert.AnError)
+		assert.NotErrorIs(t, failedErr, errs.ErrUnavailable)
+		assert.ErrorIs(t, openErr, errs.ErrUnavailable)
+		assert.ErrorIs(t, openErr, pipe.ErrCircuitOpen)
+		assert.ErrorIs(t, timeoutErr, errs.ErrUnavailable)
+		assert.ErrorIs(t, timeoutErr, pipe.ErrTaskTimeout)
+	})
+	executionTypes := []pipe.ExecutionType{
+		pipe.SEQUENTIAL_BLOCKING_ONLY_FIRST, pipe.SEQUENTIAL, pipe.CONCURRENT, pipe.CONCURRENT_MERGE, pipe.QUORUM,
+	}
+	for _, executionType := range executionTypes {
+		t.Run("Should not skip the repository with the circuit open with "+executionType.String(), func(t *testing.T) {
+			// Arrange
+			broken := &failingRegistry{failingRepository{err: assert.AnError}}
+			healthy := localsql.NewLocalSqlRoundRepository(logger.Discard())
+			policy := pipe.TaskPolicy{Breaker: &pipe.BreakerPolicy{Threshold: 1, Cooldown: time.Hour}}
+			a, err := NewCommandAggregatorWithOptions([]repository.RoundRepository{broken, healthy},
+				WithExecutionType(voteUsecase.HandlerFuncCreateVote, executionType),
+				WithExecutionType(voteUsecase.HandlerFuncCreateRound, executionType),
+				WithTaskPolicy(voteUsecase.HandlerFuncCreateVote, policy),
+				WithTaskPolicy(voteUsecase.HandlerFuncCreateRound, policy),
+			)
+			assert.NoError(t, err)
+			useCase := a.GetAggregatedUseCase()
+			vote := entity.Vote{RoundID: "round1", ParticipantID: "participant1"}
+			round := entity.Round{ID: "round1"}
+			useCase.CreateVote(context.Background(), vote)
+			useCase.CreateRound(context.Background(), round)
+
+			// Act
+			voteErr := useCase.CreateVote(context.Background(), vote)
+			roundErr := useCase.CreateRound(context.Background(), round)
+
+			// Assert
+			assert.ErrorIs(t, voteErr, pipe.ErrCircuitOpen)
+			assert.Equal(t, http.StatusServiceUnavailable, problem.FromError(voteErr).Status)
+			assert.ErrorIs(t, roundErr, pipe.ErrCircuitOpen)
+			assert.Equal(t, http.StatusServiceUnavailable, problem.FromError(roundErr).Status)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package aggregator
 
 import (
+	"context"
+
 	commandVoteUsecase "github.com/sergiodii/bbb/internal/usecase/vote/command"
 	queryVoteUsecase "github.com/sergiodii/bbb/internal/usecase/vote/query"
 )
@@ -11,4 +13,10 @@ type QueryAggregator interface {
 
 type CommandAggregator interface {
 	GetAggregatedUseCase() commandVoteUsecase.CommandVoteUseCase
+
+	// ReplayDeadLetters registers again the votes that failed in the secondary repositories
+	ReplayDeadLetters(ctx context.Context) (int, error)
+
+	// Wait blocks until the votes being registered in background are done or the context is done
+	Wait(ctx context.Context) error
 }
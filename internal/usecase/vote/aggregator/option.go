@@ -0,0 +1,104 @@
+package aggregator
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	voteUsecase "github.com/sergiodii/bbb/internal/usecase/vote"
+)
+
+// Option configures an aggregator
+type Option func(*config)
+
+type config struct {
+	deadLetters    repository.DeadLetterRepository
+	policies       map[voteUsecase.HandlerFuncEnum]pipe.TaskPolicy
+	executionTypes map[voteUsecase.HandlerFuncEnum]pipe.ExecutionType
+	repositories   map[voteUsecase.HandlerFuncEnum][]repository.RoundRepository
+	cacheTTL       time.Duration
+	logger         *slog.Logger
+}
+
+// policy returns the task policy of the handler, the zero policy does not change the tasks
+func (c config) policy(handler voteUsecase.HandlerFuncEnum) pipe.TaskPolicy {
+	return c.policies[handler]
+}
+
+// executionType returns the execution type of the handler pipe, or the default one when it is not set
+func (c config) executionType(handler voteUsecase.HandlerFuncEnum, def pipe.ExecutionType) pipe.ExecutionType {
+	if t, ok := c.executionTypes[handler]; ok {
+		return t
+	}
+	return def
+}
+
+// handlerRepositories returns the repositories of the handler, or the default ones when they are not set
+func (c config) handlerRepositories(handler voteUsecase.HandlerFuncEnum, def []repository.RoundRepository) []repository.RoundRepository {
+	if repos, ok := c.repositories[handler]; ok {
+		return repos
+	}
+	return def
+}
+
+func newConfig(opts ...Option) config {
+	c := config{
+		policies:       map[voteUsecase.HandlerFuncEnum]pipe.TaskPolicy{},
+		executionTypes: map[voteUsecase.HandlerFuncEnum]pipe.ExecutionType{},
+		repositories:   map[voteUsecase.HandlerFuncEnum][]repository.RoundRepository{},
+		logger:         slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithLogger sets the logger of the aggregator and of its pipes, used to report the errors of the background tasks.
+// The default is slog.Default().
+func WithLogger(l *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = l
+	}
+}
+
+// WithDeadLetter stores the votes that the secondary repositories failed to register in the dead letter repository.
+// It is only used by the command aggregator.
+func WithDeadLetter(deadLetters repository.DeadLetterRepository) Option {
+	return func(c *config) {
+		c.deadLetters = deadLetters
+	}
+}
+
+// WithTaskPolicy applies the policy (timeout, retry and circuit breaker) to each repository task of the handler.
+// Each repository gets its own circuit breaker.
+func WithTaskPolicy(handler voteUsecase.HandlerFuncEnum, policy pipe.TaskPolicy) Option {
+	return func(c *config) {
+		c.policies[handler] = policy
+	}
+}
+
+// WithExecutionType sets the execution type of the handler pipe.
+// By default the votes are registered with SEQUENTIAL_BLOCKING_ONLY_FIRST and queried with SEQUENTIAL_WITH_FIRST_RESULT.
+func WithExecutionType(handler voteUsecase.HandlerFuncEnum, executionType pipe.ExecutionType) Option {
+	return func(c *config) {
+		c.executionTypes[handler] = executionType
+	}
+}
+
+// WithHandlerRepositories sets the repositories of the handler, in the order of its tasks.
+// By default a handler uses every repository of the aggregator.
+func WithHandlerRepositories(handler voteUsecase.HandlerFuncEnum, repos ...repository.RoundRepository) Option {
+	return func(c *config) {
+		c.repositories[handler] = repos
+	}
+}
+
+// WithQueryCache caches the results of the queries for the TTL, and shares one query between the identical
+// queries made at the same time, see query.NewCachedQueryVote. It is only used by the query aggregator.
+func WithQueryCache(ttl time.Duration) Option {
+	return func(c *config) {
+		c.cacheTTL = ttl
+	}
+}
@@ -2,104 +2,271 @@ package aggregator
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
 
 	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/errs"
 	"github.com/sergiodii/bbb/internal/domain/repository"
 	voteUsecase "github.com/sergiodii/bbb/internal/usecase/vote"
 	queryVoteUsecase "github.com/sergiodii/bbb/internal/usecase/vote/query"
-
-	"sync"
 )
 
-var queryAggregated *queryAggregator
-
-var queryAggregatedOnce sync.Once
-
 type queryAggregator struct {
 	repositories []repository.RoundRepository
+	config
+	useCase queryVoteUsecase.QueryVoteUseCase
 }
 
-func (a *queryAggregator) aggregateTotalVotesHandler() pipe.Pipe[queryVoteUsecase.QueryDTO] {
-	p := pipe.NewPipe[queryVoteUsecase.QueryDTO](pipe.SEQUENTIAL_WITH_FIRST_RESULT)
-	for _, exec := range a.repositories {
-		p.Enqueue(func(ctx context.Context, dto queryVoteUsecase.QueryDTO) (queryVoteUsecase.QueryDTO, error) {
-			total, err := exec.GetTotalVotes(ctx, dto.RoundID)
-			if err != nil {
-				return dto, err
-			}
+// sumTotals is the reducer used when the total is queried with CONCURRENT_MERGE, the totals of the repositories are summed
+func sumTotals(input queryVoteUsecase.QueryRequest[int], outputs []queryVoteUsecase.QueryRequest[int]) (queryVoteUsecase.QueryRequest[int], error) {
+	input.Result = 0
+	for _, output := range outputs {
+		input.Result += output.Result
+	}
+	return input, nil
+}
 
-			if total == 0 {
-				// If no votes found, return ObjectNotFound error to let the pipe continue
-				return dto, pipe.ONF
-			}
+// sumMaps is the reducer used when the maps are queried with CONCURRENT_MERGE, the counters of the repositories are summed by key
+func sumMaps(input queryVoteUsecase.QueryRequest[map[string]int], outputs []queryVoteUsecase.QueryRequest[map[string]int]) (queryVoteUsecase.QueryRequest[map[string]int], error) {
+	input.Result = map[string]int{}
+	for _, output := range outputs {
+		for k, v := range output.Result {
+			input.Result[k] += v
+		}
+	}
+	return input, nil
+}
 
-			dto.Result = total
-			return dto, nil
-		})
+// largestVoters is the reducer used when the unique voters are queried with CONCURRENT_MERGE.
+// The voters of the repositories may overlap and their counts can not be summed, so the largest counts are kept.
+func largestVoters(input queryVoteUsecase.QueryRequest[entity.UniqueVoters], outputs []queryVoteUsecase.QueryRequest[entity.UniqueVoters]) (queryVoteUsecase.QueryRequest[entity.UniqueVoters], error) {
+	input.Result = entity.UniqueVoters{Participants: map[string]int{}}
+	for _, output := range outputs {
+		input.Result.Total = max(input.Result.Total, output.Result.Total)
+		for participant, voters := range output.Result.Participants {
+			input.Result.Participants[participant] = max(input.Result.Participants[participant], voters)
+		}
 	}
-	return p
+	return input, nil
 }
 
-func (a *queryAggregator) aggregateTotalVotesForParticipantHandler() pipe.Pipe[queryVoteUsecase.QueryDTO] {
-	p := pipe.NewPipe[queryVoteUsecase.QueryDTO](pipe.SEQUENTIAL_WITH_FIRST_RESULT)
-	for _, exec := range a.repositories {
-		p.Enqueue(func(ctx context.Context, dto queryVoteUsecase.QueryDTO) (queryVoteUsecase.QueryDTO, error) {
-			totalMap, err := exec.GetTotalForParticipant(ctx, dto.RoundID)
+// aggregate creates the pipe of the handler, with a task for each of its repositories.
+// The result type R of the task is the result type of the handler.
+func aggregate[R any](
+	a *queryAggregator,
+	handler voteUsecase.HandlerFuncEnum,
+	reducer pipe.Reducer[queryVoteUsecase.QueryRequest[R]],
+	task func(repository.RoundRepository) func(context.Context, queryVoteUsecase.QueryRequest[R]) (queryVoteUsecase.QueryRequest[R], error),
+) (pipe.Pipe[queryVoteUsecase.QueryRequest[R]], error) {
+	p, err := pipe.New[queryVoteUsecase.QueryRequest[R]](a.executionType(handler, pipe.SEQUENTIAL_WITH_FIRST_RESULT), pipe.WithLogger(a.logger), pipe.WithReducer(reducer))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", handler, err)
+	}
 
-			if len(totalMap) == 0 {
+	repos := a.handlerRepositories(handler, a.repositories)
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("%s: %w", handler, ErrNoRepository)
+	}
 
-				// If no votes found, return ObjectNotFound error to let the pipe continue
-				return dto, pipe.ONF
-			}
-			if err != nil {
-				return dto, err
-			}
-			dto.Result = totalMap
-			return dto, nil
-		})
+	policy := a.policy(handler)
+	for _, exec := range repos {
+		p.Enqueue(skipped(pipe.ApplyPolicy(task(exec), policy)))
 	}
-	return p
+	return &roundPipe[queryVoteUsecase.QueryRequest[R]]{Pipe: p}, nil
 }
 
-func (a *queryAggregator) aggregateTotalVotesForHourHandler() pipe.Pipe[queryVoteUsecase.QueryDTO] {
-	p := pipe.NewPipe[queryVoteUsecase.QueryDTO](pipe.SEQUENTIAL_WITH_FIRST_RESULT)
-	for _, exec := range a.repositories {
-		p.Enqueue(func(ctx context.Context, dto queryVoteUsecase.QueryDTO) (queryVoteUsecase.QueryDTO, error) {
-			totalMap, err := exec.GetTotalForHour(ctx, dto.RoundID)
+// lookup is the state of a query shared by its tasks through the context.
+// The tasks of the repositories without votes return ONF, so the pipe tries the next repositories,
+// and the result alone can not tell a round without votes from an unknown round.
+type lookup struct {
+	// found is set when a repository knows the round
+	found atomic.Bool
 
-			if len(totalMap) == 0 {
-				// If no votes found, return ObjectNotFound error to let the pipe continue
-				return dto, pipe.ONF
-			}
+	// open is set when a repository was skipped because its circuit is open
+	open atomic.Bool
+}
 
-			if err != nil {
-				return dto, err
-			}
+type lookupKey struct{}
 
-			dto.Result = totalMap
-			return dto, nil
-		})
+func lookupFrom(ctx context.Context) *lookup {
+	l, _ := ctx.Value(lookupKey{}).(*lookup)
+	if l == nil {
+		return &lookup{}
 	}
-	return p
+	return l
 }
 
-func (a *queryAggregator) GetAggregatedUseCase() queryVoteUsecase.QueryVoteUseCase {
+// known converts the error of a repository: an unknown round is ONF, so the next repository is tried,
+// and the round is marked as found when the repository answered
+func known(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrRoundNotFound):
+		return pipe.ONF
+	case err == nil:
+		lookupFrom(ctx).found.Store(true)
+	}
+	return err
+}
+
+// skipped marks the query when the task is skipped by its circuit breaker
+func skipped[T any](task func(context.Context, T) (T, error)) func(context.Context, T) (T, error) {
+	return func(ctx context.Context, dto T) (T, error) {
+		result, err := task(ctx, dto)
+		if errors.Is(err, pipe.ErrCircuitOpen) {
+			lookupFrom(ctx).open.Store(true)
+		}
+		return result, err
+	}
+}
+
+// roundPipe is the pipe of a query handler, it returns repository.ErrRoundNotFound when no repository knows the round
+type roundPipe[T any] struct {
+	pipe.Pipe[T]
+}
+
+func (p *roundPipe[T]) Execute(ctx context.Context, dto T) (T, error) {
+	l := &lookup{}
+	result, err := p.Pipe.Execute(context.WithValue(ctx, lookupKey{}, l), dto)
+	if err != nil || l.found.Load() {
+		return result, err
+	}
+
+	// the repositories that could know the round were not called
+	if l.open.Load() {
+		return result, errs.Wrap(errs.ErrUnavailable, pipe.ErrCircuitOpen, "the vote store is unavailable")
+	}
+	return result, repository.ErrRoundNotFound
+}
+
+func totalVotesTask(exec repository.RoundRepository) func(context.Context, queryVoteUsecase.QueryRequest[int]) (queryVoteUsecase.QueryRequest[int], error) {
+	return func(ctx context.Context, dto queryVoteUsecase.QueryRequest[int]) (queryVoteUsecase.QueryRequest[int], error) {
+		total, err := exec.GetTotalVotes(ctx, dto.RoundID)
+		if err = known(ctx, err); err != nil {
+			return dto, err
+		}
+
+		if total == 0 {
+			// If no votes found, return ObjectNotFound error to let the pipe continue
+			return dto, pipe.ONF
+		}
+
+		dto.Result = total
+		return dto, nil
+	}
+}
 
-	executionMap := map[voteUsecase.HandlerFuncEnum]voteUsecase.Pipe[queryVoteUsecase.QueryDTO]{
-		voteUsecase.HandlerFuncGetTotalVotes:               a.aggregateTotalVotesHandler(),
-		voteUsecase.HandlerFuncGetTotalVotesForParticipant: a.aggregateTotalVotesForParticipantHandler(),
-		voteUsecase.HandlerFuncGetTotalVotesForHour:        a.aggregateTotalVotesForHourHandler(),
+func totalVotesForParticipantTask(exec repository.RoundRepository) func(context.Context, queryVoteUsecase.QueryRequest[map[string]int]) (queryVoteUsecase.QueryRequest[map[string]int], error) {
+	return func(ctx context.Context, dto queryVoteUsecase.QueryRequest[map[string]int]) (queryVoteUsecase.QueryRequest[map[string]int], error) {
+		totalMap, err := exec.GetTotalForParticipant(ctx, dto.RoundID)
+		if err = known(ctx, err); err != nil {
+			return dto, err
+		}
+
+		if len(totalMap) == 0 {
+			// If no votes found, return ObjectNotFound error to let the pipe continue
+			return dto, pipe.ONF
+		}
+		dto.Result = totalMap
+		return dto, nil
 	}
-	return queryVoteUsecase.NewQueryVote(executionMap)
 }
 
-func NewQueryAggregator(repos ...repository.RoundRepository) QueryAggregator {
+func totalVotesForHourTask(exec repository.RoundRepository) func(context.Context, queryVoteUsecase.QueryRequest[map[string]int]) (queryVoteUsecase.QueryRequest[map[string]int], error) {
+	return func(ctx context.Context, dto queryVoteUsecase.QueryRequest[map[string]int]) (queryVoteUsecase.QueryRequest[map[string]int], error) {
+		totalMap, err := exec.GetTotalForHour(ctx, dto.RoundID)
+		if err = known(ctx, err); err != nil {
+			return dto, err
+		}
+
+		if len(totalMap) == 0 {
+			// If no votes found, return ObjectNotFound error to let the pipe continue
+			return dto, pipe.ONF
+		}
+
+		dto.Result = totalMap
+		return dto, nil
+	}
+}
 
-	queryAggregatedOnce.Do(func() {
-		queryAggregated = &queryAggregator{
-			repositories: repos,
+func uniqueVotersTask(exec repository.RoundRepository) func(context.Context, queryVoteUsecase.QueryRequest[entity.UniqueVoters]) (queryVoteUsecase.QueryRequest[entity.UniqueVoters], error) {
+	return func(ctx context.Context, dto queryVoteUsecase.QueryRequest[entity.UniqueVoters]) (queryVoteUsecase.QueryRequest[entity.UniqueVoters], error) {
+		counter, ok := exec.(repository.VoterCounter)
+		if !ok {
+			// the repository does not count the voters, the next one is tried
+			return dto, pipe.ONF
 		}
-	})
 
-	return queryAggregated
+		voters, err := counter.GetUniqueVoters(ctx, dto.RoundID)
+		if errors.Is(err, errors.ErrUnsupported) {
+			return dto, pipe.ONF
+		}
+		if err = known(ctx, err); err != nil {
+			return dto, err
+		}
+
+		if voters.Total == 0 {
+			// If no voters found, return ObjectNotFound error to let the pipe continue
+			return dto, pipe.ONF
+		}
+
+		dto.Result = voters
+		return dto, nil
+	}
+}
+
+func (a *queryAggregator) GetAggregatedUseCase() queryVoteUsecase.QueryVoteUseCase {
+	return a.useCase
+}
+
+// NewQueryAggregator creates the query aggregator with the default options
+func NewQueryAggregator(repos ...repository.RoundRepository) (QueryAggregator, error) {
+	return NewQueryAggregatorWithOptions(repos)
+}
+
+// NewQueryAggregatorWithOptions creates the query aggregator configured by the options,
+// see WithTaskPolicy, WithExecutionType, WithHandlerRepositories and WithQueryCache.
+// It returns an error when a handler can not be built from the options.
+func NewQueryAggregatorWithOptions(repos []repository.RoundRepository, opts ...Option) (QueryAggregator, error) {
+	a := &queryAggregator{
+		repositories: repos,
+		config:       newConfig(opts...),
+	}
+
+	var (
+		pipes queryVoteUsecase.Pipes
+		err   error
+	)
+
+	if pipes.TotalVotes, err = aggregate(a, voteUsecase.HandlerFuncGetTotalVotes, sumTotals, totalVotesTask); err != nil {
+		return nil, err
+	}
+
+	// the handlers computed from the votes of each participant or of each hour have their own pipe,
+	// so they can be configured with a different strategy
+	counters := []struct {
+		handler voteUsecase.HandlerFuncEnum
+		pipe    *voteUsecase.Pipe[queryVoteUsecase.QueryRequest[map[string]int]]
+		task    func(repository.RoundRepository) func(context.Context, queryVoteUsecase.QueryRequest[map[string]int]) (queryVoteUsecase.QueryRequest[map[string]int], error)
+	}{
+		{voteUsecase.HandlerFuncGetTotalVotesForParticipant, &pipes.TotalVotesForParticipant, totalVotesForParticipantTask},
+		{voteUsecase.HandlerFuncGetTotalVotesForHour, &pipes.TotalVotesForHour, totalVotesForHourTask},
+		{voteUsecase.HandlerFuncGetWinner, &pipes.Winner, totalVotesForParticipantTask},
+		{voteUsecase.HandlerFuncGetRanking, &pipes.Ranking, totalVotesForParticipantTask},
+		{voteUsecase.HandlerFuncGetTimeSeries, &pipes.TimeSeries, totalVotesForHourTask},
+	}
+	for _, c := range counters {
+		if *c.pipe, err = aggregate(a, c.handler, sumMaps, c.task); err != nil {
+			return nil, err
+		}
+	}
+
+	if pipes.UniqueVoters, err = aggregate(a, voteUsecase.HandlerFuncGetUniqueVoters, largestVoters, uniqueVotersTask); err != nil {
+		return nil, err
+	}
+
+	a.useCase = queryVoteUsecase.NewCachedQueryVote(queryVoteUsecase.NewQueryVote(pipes), a.cacheTTL)
+	return a, nil
 }
@@ -0,0 +1,224 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/errs"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	voteUsecase "github.com/sergiodii/bbb/internal/usecase/vote"
+	queryVoteUsecase "github.com/sergiodii/bbb/internal/usecase/vote/query"
+	"github.com/sergiodii/bbb/pkg/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryAggregator(t *testing.T) {
+	t.Run("Should return the errors when every repository fails", func(t *testing.T) {
+		// Arrange
+		otherErr := errors.New("other error")
+		a, err := NewQueryAggregatorWithOptions([]repository.RoundRepository{
+			&failingRepository{err: assert.AnError},
+			&failingRepository{err: otherErr},
+		})
+		assert.NoError(t, err)
+		uc := a.GetAggregatedUseCase()
+
+		// Act
+		_, totalErr := uc.GetTotalVotes(context.Background(), "round1")
+		_, participantErr := uc.GetTotalVotesForParticipant(context.Background(), "round1")
+		_, hourErr := uc.GetTotalVotesForHour(context.Background(), "round1")
+		_, winnerErr := uc.GetWinner(context.Background(), "round1")
+		_, rankingErr := uc.GetRanking(context.Background(), "round1")
+		_, seriesErr := uc.GetTimeSeries(context.Background(), "round1")
+
+		// Assert
+		for _, err := range []error{totalErr, participantErr, hourErr, winnerErr, rankingErr, seriesErr} {
+			assert.ErrorIs(t, err, assert.AnError)
+			assert.ErrorIs(t, err, otherErr)
+		}
+	})
+
+	t.Run("Should compute the winner with the strategy of its own handler", func(t *testing.T) {
+		// Arrange
+		first, second := memory.NewMemoryRoundRepository(), memory.NewMemoryRoundRepository()
+		for _, participant := range []string{"alice", "alice"} {
+			first.VoteRegister(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: participant})
+		}
+		for _, participant := range []string{"alice", "bob", "bob", "bob"} {
+			second.VoteRegister(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: participant})
+		}
+
+		a, err := NewQueryAggregatorWithOptions([]repository.RoundRepository{first, second},
+			WithExecutionType(voteUsecase.HandlerFuncGetWinner, pipe.CONCURRENT_MERGE),
+		)
+		assert.NoError(t, err)
+		uc := a.GetAggregatedUseCase()
+
+		// Act
+		participants, participantsErr := uc.GetTotalVotesForParticipant(context.Background(), "round1")
+		winner, winnerErr := uc.GetWinner(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, participantsErr)
+		assert.NoError(t, winnerErr)
+		assert.Equal(t, map[string]int{"alice": 2}, participants)
+		assert.Equal(t, queryVoteUsecase.Winner{ParticipantID: "alice", Votes: 3, Percentage: 50, Tie: true}, winner)
+	})
+
+	t.Run("Should stop calling a failing repository once its circuit is open", func(t *testing.T) {
+		// Arrange
+		broken := &failingRepository{err: assert.AnError}
+		healthy := &failingRepository{votes: []entity.Vote{{RoundID: "round1"}}}
+		a, err := NewQueryAggregatorWithOptions([]repository.RoundRepository{broken, healthy},
+			WithTaskPolicy(voteUsecase.HandlerFuncGetTotalVotes, pipe.TaskPolicy{
+				Breaker: &pipe.BreakerPolicy{Threshold: 2, Cooldown: time.Hour},
+			}),
+		)
+		assert.NoError(t, err)
+		uc := a.GetAggregatedUseCase()
+
+		// Act
+		for range 5 {
+			total, err := uc.GetTotalVotes(context.Background(), "round1")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, total)
+		}
+
+		// Assert
+		assert.Equal(t, 2, broken.calls)
+		assert.Equal(t, 5, healthy.calls)
+	})
+	t.Run("Should merge the totals of the repositories with CONCURRENT_MERGE", func(t *testing.T) {
+		// Arrange
+		a, err := NewQueryAggregatorWithOptions([]repository.RoundRepository{
+			&failingRepository{votes: []entity.Vote{{RoundID: "round1"}}},
+			&failingRepository{votes: []entity.Vote{{RoundID: "round1"}, {RoundID: "round1"}}},
+		}, WithExecutionType(voteUsecase.HandlerFuncGetTotalVotes, pipe.CONCURRENT_MERGE))
+		assert.NoError(t, err)
+
+		// Act
+		total, err := a.GetAggregatedUseCase().GetTotalVotes(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+	})
+
+	t.Run("Should tell an unknown round from a round without votes", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		cache, store := memory.NewMemoryRoundRepository(), memory.NewMemoryRoundRepository()
+		store.(repository.RoundRegistry).CreateRound(ctx, entity.Round{ID: "empty"})
+		store.VoteRegister(ctx, entity.Vote{RoundID: "voted", ParticipantID: "alice"})
+
+		a, err := NewQueryAggregator(cache, store)
+		assert.NoError(t, err)
+		uc := a.GetAggregatedUseCase()
+
+		// Act
+		_, unknownErr := uc.GetTotalVotes(ctx, "unknown")
+		_, unknownRankingErr := uc.GetRanking(ctx, "unknown")
+		empty, emptyErr := uc.GetTotalVotes(ctx, "empty")
+		emptyRanking, emptyRankingErr := uc.GetRanking(ctx, "empty")
+		voted, votedErr := uc.GetTotalVotes(ctx, "voted")
+
+		// Assert
+		assert.ErrorIs(t, unknownErr, repository.ErrRoundNotFound)
+		assert.ErrorIs(t, unknownRankingErr, repository.ErrRoundNotFound)
+		assert.NoError(t, emptyErr)
+		assert.NoError(t, emptyRankingErr)
+		assert.NoError(t, votedErr)
+		assert.Equal(t, 0, empty)
+		assert.Empty(t, emptyRanking)
+		assert.Equal(t, 1, voted)
+	})
+
+	t.Run("Should not report an unknown round when the repositories were skipped", func(t *testing.T) {
+		// Arrange
+		broken := &failingRepository{err: assert.AnError}
+		a, err := NewQueryAggregatorWithOptions([]repository.RoundRepository{broken},
+			WithTaskPolicy(voteUsecase.HandlerFuncGetTotalVotes, pipe.TaskPolicy{
+				Breaker: &pipe.BreakerPolicy{Threshold: 1, Cooldown: time.Hour},
+			}),
+		)
+		assert.NoError(t, err)
+		uc := a.GetAggregatedUseCase()
+		uc.GetTotalVotes(context.Background(), "round1")
+
+		// Act
+		_, err = uc.GetTotalVotes(context.Background(), "round1")
+
+		// Assert
+		assert.ErrorIs(t, err, errs.ErrUnavailable)
+		assert.NotErrorIs(t, err, repository.ErrRoundNotFound)
+	})
+
+	t.Run("Should query the unique voters from the repositories that count them", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		withoutVoters := &failingRepository{votes: []entity.Vote{{RoundID: "round1", ParticipantID: "alice"}}}
+		store := memory.NewMemoryRoundRepository()
+		store.VoteRegister(ctx, entity.Vote{RoundID: "round1", ParticipantID: "alice", VoterID: "v1"})
+		store.VoteRegister(ctx, entity.Vote{RoundID: "round1", ParticipantID: "bob", VoterID: "v1"})
+		store.(repository.RoundRegistry).CreateRound(ctx, entity.Round{ID: "empty"})
+		a, err := NewQueryAggregator(withoutVoters, store)
+		assert.NoError(t, err)
+		uc := a.GetAggregatedUseCase()
+
+		// Act
+		voters, votersErr := uc.GetUniqueVoters(ctx, "round1")
+		empty, emptyErr := uc.GetUniqueVoters(ctx, "empty")
+		_, unknownErr := uc.GetUniqueVoters(ctx, "unknown")
+
+		// Assert
+		assert.NoError(t, votersErr)
+		assert.Equal(t, queryVoteUsecase.UniqueVoters{Total: 1, Participants: map[string]int{"alice": 1, "bob": 1}}, voters)
+		assert.NoError(t, emptyErr)
+		assert.Equal(t, queryVoteUsecase.UniqueVoters{Participants: map[string]int{}}, empty)
+		assert.ErrorIs(t, unknownErr, repository.ErrRoundNotFound)
+	})
+
+	t.Run("Should cache the results of the queries with WithQueryCache", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		store := memory.NewMemoryRoundRepository()
+		store.VoteRegister(ctx, entity.Vote{RoundID: "round1", ParticipantID: "alice"})
+		a, err := NewQueryAggregatorWithOptions([]repository.RoundRepository{store}, WithQueryCache(time.Minute))
+		assert.NoError(t, err)
+		uc := a.GetAggregatedUseCase()
+
+		// Act
+		first, firstErr := uc.GetTotalVotes(ctx, "round1")
+		store.VoteRegister(ctx, entity.Vote{RoundID: "round1", ParticipantID: "bob"})
+		cached, cachedErr := uc.GetTotalVotes(ctx, "round1")
+		participants, participantsErr := uc.GetTotalVotesForParticipant(ctx, "round1")
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, cachedErr)
+		assert.NoError(t, participantsErr)
+		assert.Equal(t, 1, first)
+		assert.Equal(t, 1, cached)
+		assert.Equal(t, map[string]int{"alice": 1, "bob": 1}, participants)
+	})
+
+	t.Run("Should return an error when a handler can not be built", func(t *testing.T) {
+		// Arrange
+		repos := []repository.RoundRepository{&failingRepository{}}
+
+		// Act
+		_, typeErr := NewQueryAggregatorWithOptions(repos, WithExecutionType(voteUsecase.HandlerFuncGetTotalVotes, "PARALLEL"))
+		_, reposErr := NewQueryAggregatorWithOptions(repos, WithHandlerRepositories(voteUsecase.HandlerFuncGetTotalVotesForHour))
+		_, commandErr := NewCommandAggregatorWithOptions(nil)
+
+		// Assert
+		assert.ErrorIs(t, typeErr, pipe.ErrInvalidExecutionType)
+		assert.ErrorIs(t, reposErr, ErrNoRepository)
+		assert.ErrorIs(t, commandErr, ErrNoRepository)
+	})
+}
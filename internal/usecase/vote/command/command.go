@@ -2,24 +2,54 @@ package command
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/sergiodii/bbb/internal/domain/entity"
 	usecaseVote "github.com/sergiodii/bbb/internal/usecase/vote"
 )
 
+// ErrHandlerNotConfigured is returned when the pipe of a handler was not given to the use case
+var ErrHandlerNotConfigured = errors.New("command handler not configured")
+
 type commandVote struct {
 	pipeMap map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[entity.Vote]
+	rounds  usecaseVote.Pipe[entity.Round]
 }
 
 // commandVote defines the interface for vote queries.
 func (q *commandVote) CreateVote(ctx context.Context, vote entity.Vote) error {
+	if err := vote.Validate(); err != nil {
+		return err
+	}
+
 	_, err := q.pipeMap[usecaseVote.HandlerFuncCreateVote].Execute(ctx, vote)
 	return err
 }
 
+// CreateRound validates the round and stores its metadata in the repositories of the rounds pipe.
+func (q *commandVote) CreateRound(ctx context.Context, round entity.Round) error {
+	if err := round.Validate(); err != nil {
+		return err
+	}
+	if q.rounds == nil {
+		return fmt.Errorf("%w: %s", ErrHandlerNotConfigured, usecaseVote.HandlerFuncCreateRound)
+	}
+
+	_, err := q.rounds.Execute(ctx, round)
+	return err
+}
+
 // NewCommandVote creates a new instance of commandVote with the provided execution pipes.
 func NewCommandVote(pipeMap map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[entity.Vote]) CommandVoteUseCase {
+	return NewCommandVoteWithRounds(pipeMap, nil)
+}
+
+// NewCommandVoteWithRounds creates a new instance of commandVote with the provided execution pipes
+// and the pipe that stores the rounds.
+func NewCommandVoteWithRounds(pipeMap map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[entity.Vote], rounds usecaseVote.Pipe[entity.Round]) CommandVoteUseCase {
 	return &commandVote{
 		pipeMap: pipeMap,
+		rounds:  rounds,
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/errs"
 	usecaseVote "github.com/sergiodii/bbb/internal/usecase/vote"
 	"github.com/sergiodii/bbb/internal/usecase/vote/query/mock"
 
@@ -69,4 +70,24 @@ func TestNewCommandVote(t *testing.T) {
 		assert.Equal(t, "participant1", "participant1")
 		assert.Equal(t, 1234567890, 1234567890)
 	})
+
+	t.Run("Should reject an invalid vote without executing the pipe", func(t *testing.T) {
+
+		// Arrange
+		pipe := mock.NewPipeMock[entity.Vote]()
+
+		pipeMap := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[entity.Vote]{
+			usecaseVote.HandlerFuncCreateVote: pipe,
+		}
+
+		commandVote := NewCommandVote(pipeMap)
+
+		// Act
+		err := commandVote.CreateVote(context.Background(), entity.Vote{RoundID: "round1"})
+
+		// Assert
+		assert.ErrorIs(t, err, errs.ErrValidation)
+		assert.Equal(t, "participant_id is required", errs.Message(err))
+		pipe.AssertNotCalled(t, "Execute")
+	})
 }
@@ -8,4 +8,7 @@ import (
 
 type CommandVoteUseCase interface {
 	CreateVote(ctx context.Context, vote entity.Vote) error
+
+	// CreateRound stores the metadata of the round, so it is known before its first vote
+	CreateRound(ctx context.Context, round entity.Round) error
 }
@@ -4,11 +4,15 @@ type HandlerFuncEnum string
 
 const (
 	HandlerFuncCreateVote                  HandlerFuncEnum = "CreateVote"
+	HandlerFuncCreateRound                 HandlerFuncEnum = "CreateRound"
 	HandlerFuncGetTotalVotes               HandlerFuncEnum = "GetTotalVotes"
 	HandlerFuncGetTotalVotesForParticipant HandlerFuncEnum = "GetTotalVotesForParticipant"
 	HandlerFuncGetTotalVotesForHour        HandlerFuncEnum = "GetTotalVotesForHour"
 	HandlerFuncGetWinner                   HandlerFuncEnum = "GetWinner"
 	HandlerFuncGetVotesFromParticipant     HandlerFuncEnum = "GetVotesFromParticipant"
+	HandlerFuncGetRanking                  HandlerFuncEnum = "GetRanking"
+	HandlerFuncGetTimeSeries               HandlerFuncEnum = "GetTimeSeries"
+	HandlerFuncGetUniqueVoters             HandlerFuncEnum = "GetUniqueVoters"
 )
 
 func (h HandlerFuncEnum) String() string {
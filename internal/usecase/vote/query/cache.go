@@ -0,0 +1,128 @@
+package query
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// cachedQueryVote is a read-through cache of a QueryVoteUseCase.
+// The results are kept for the TTL, and the identical queries made at the same time share one call to the use case.
+// Only the results are cached, the errors are returned to the callers waiting for them and the next query tries again.
+type cachedQueryVote struct {
+	uc  QueryVoteUseCase
+	ttl time.Duration
+	now func() time.Time
+
+	group singleflight.Group
+
+	m       sync.Mutex
+	entries map[string]cacheEntry
+	sweep   time.Time
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// NewCachedQueryVote decorates the use case with a cache of its results, kept for the TTL.
+// The results are shared by the callers and must not be modified. A TTL of zero or less returns the use case itself.
+func NewCachedQueryVote(uc QueryVoteUseCase, ttl time.Duration) QueryVoteUseCase {
+	if ttl <= 0 {
+		return uc
+	}
+	return &cachedQueryVote{
+		uc:      uc,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+func (c *cachedQueryVote) GetTotalVotes(ctx context.Context, roundID string) (int, error) {
+	return cached(ctx, c, "total", roundID, c.uc.GetTotalVotes)
+}
+
+func (c *cachedQueryVote) GetTotalVotesForParticipant(ctx context.Context, roundID string) (map[string]int, error) {
+	return cached(ctx, c, "participant", roundID, c.uc.GetTotalVotesForParticipant)
+}
+
+func (c *cachedQueryVote) GetTotalVotesForHour(ctx context.Context, roundID string) (map[string]int, error) {
+	return cached(ctx, c, "hour", roundID, c.uc.GetTotalVotesForHour)
+}
+
+func (c *cachedQueryVote) GetWinner(ctx context.Context, roundID string) (Winner, error) {
+	return cached(ctx, c, "winner", roundID, c.uc.GetWinner)
+}
+
+func (c *cachedQueryVote) GetRanking(ctx context.Context, roundID string) ([]RankingEntry, error) {
+	return cached(ctx, c, "ranking", roundID, c.uc.GetRanking)
+}
+
+func (c *cachedQueryVote) GetTimeSeries(ctx context.Context, roundID string) ([]TimeSeriesPoint, error) {
+	return cached(ctx, c, "timeseries", roundID, c.uc.GetTimeSeries)
+}
+
+func (c *cachedQueryVote) GetUniqueVoters(ctx context.Context, roundID string) (UniqueVoters, error) {
+	return cached(ctx, c, "voters", roundID, c.uc.GetUniqueVoters)
+}
+
+// cached returns the result of the query from the cache, or calls the use case once for the callers waiting for it
+func cached[T any](ctx context.Context, c *cachedQueryVote, query, roundID string, fn func(context.Context, string) (T, error)) (T, error) {
+	// the query has no colon, so the key of each query and round is unique
+	key := query + ":" + roundID
+	if value, ok := c.get(key); ok {
+		return value.(T), nil
+	}
+
+	ch := c.group.DoChan(key, func() (any, error) {
+		// the call is shared, a caller that gives up must not cancel it for the others
+		value, err := fn(context.WithoutCancel(ctx), roundID)
+		if err == nil {
+			c.set(key, value)
+		}
+		return value, err
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
+
+func (c *cachedQueryVote) get(key string) (any, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// set stores the result, and removes the expired ones at most once per TTL, so the rounds no longer queried are dropped
+func (c *cachedQueryVote) set(key string, value any) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := c.now()
+	if !now.Before(c.sweep) {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.sweep = now.Add(c.ttl)
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+}
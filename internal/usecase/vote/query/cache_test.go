@@ -0,0 +1,138 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingQueryVote answers the totals of the rounds and counts the calls, a call blocks while release is not closed
+type countingQueryVote struct {
+	QueryVoteUseCase
+	calls   atomic.Int32
+	release chan struct{}
+	total   int
+	err     error
+}
+
+func (u *countingQueryVote) GetTotalVotes(ctx context.Context, roundID string) (int, error) {
+	u.calls.Add(1)
+	if u.release != nil {
+		<-u.release
+	}
+	return u.total, u.err
+}
+
+func TestCachedQueryVote(t *testing.T) {
+	t.Run("Should return the cached result until the TTL expires", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		uc := &countingQueryVote{total: 10}
+		cache := NewCachedQueryVote(uc, time.Second).(*cachedQueryVote)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		// Act
+		first, firstErr := cache.GetTotalVotes(ctx, "round1")
+		uc.total = 20
+		cached, cachedErr := cache.GetTotalVotes(ctx, "round1")
+		other, otherErr := cache.GetTotalVotes(ctx, "round2")
+		now = now.Add(time.Second)
+		expired, expiredErr := cache.GetTotalVotes(ctx, "round1")
+
+		// Assert
+		require.NoError(t, errors.Join(firstErr, cachedErr, otherErr, expiredErr))
+		assert.Equal(t, 10, first)
+		assert.Equal(t, 10, cached)
+		assert.Equal(t, 20, other)
+		assert.Equal(t, 20, expired)
+		assert.EqualValues(t, 3, uc.calls.Load())
+	})
+
+	t.Run("Should share one call between the identical queries made at the same time", func(t *testing.T) {
+		// Arrange
+		uc := &countingQueryVote{total: 10, release: make(chan struct{})}
+		cache := NewCachedQueryVote(uc, time.Minute)
+		results := make([]int, 10)
+
+		// Act
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = cache.GetTotalVotes(context.Background(), "round1")
+			}()
+		}
+		assert.Eventually(t, func() bool { return uc.calls.Load() == 1 }, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		close(uc.release)
+		wg.Wait()
+
+		// Assert
+		assert.EqualValues(t, 1, uc.calls.Load())
+		for _, total := range results {
+			assert.Equal(t, 10, total)
+		}
+	})
+
+	t.Run("Should not cache the errors", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		uc := &countingQueryVote{err: errors.New("store down")}
+		cache := NewCachedQueryVote(uc, time.Minute)
+
+		// Act
+		_, failedErr := cache.GetTotalVotes(ctx, "round1")
+		uc.err, uc.total = nil, 10
+		total, err := cache.GetTotalVotes(ctx, "round1")
+
+		// Assert
+		assert.EqualError(t, failedErr, "store down")
+		assert.NoError(t, err)
+		assert.Equal(t, 10, total)
+		assert.EqualValues(t, 2, uc.calls.Load())
+	})
+
+	t.Run("Should not cancel the shared call when a caller gives up", func(t *testing.T) {
+		// Arrange
+		uc := &countingQueryVote{total: 10, release: make(chan struct{})}
+		cache := NewCachedQueryVote(uc, time.Minute)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// Act
+		canceled := make(chan error)
+		go func() {
+			_, err := cache.GetTotalVotes(ctx, "round1")
+			canceled <- err
+		}()
+		assert.Eventually(t, func() bool { return uc.calls.Load() == 1 }, time.Second, time.Millisecond)
+		cancel()
+		canceledErr := <-canceled
+		close(uc.release)
+		total, err := cache.GetTotalVotes(context.Background(), "round1")
+
+		// Assert
+		assert.ErrorIs(t, canceledErr, context.Canceled)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, total)
+		assert.EqualValues(t, 1, uc.calls.Load())
+	})
+
+	t.Run("Should return the use case itself without a TTL", func(t *testing.T) {
+		// Arrange
+		uc := &countingQueryVote{}
+
+		// Act
+		cache := NewCachedQueryVote(uc, 0)
+
+		// Assert
+		assert.Same(t, uc, cache)
+	})
+}
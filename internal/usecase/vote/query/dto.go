@@ -1,15 +1,59 @@
 package query
 
 import (
+	"github.com/sergiodii/bbb/internal/domain/entity"
 	usecaseVote "github.com/sergiodii/bbb/internal/usecase/vote"
 )
 
-type QueryDTO struct {
+// QueryRequest is the input and the output of the query pipes, R is the type of the result of the handler
+type QueryRequest[R any] struct {
 	RoundID       string
 	ParticipantID string
-	Result        interface{}
+	Result        R
 }
 
-type OrderedExecutionPipeDTO struct {
-	Pipe usecaseVote.Pipe[QueryDTO]
+// Pipes are the pipes of the query handlers, each one typed by the result its repositories return.
+// The winner and the ranking are computed from the votes of each participant, and the time series from the votes of each hour,
+// so they can be executed with a different strategy than GetTotalVotesForParticipant and GetTotalVotesForHour.
+type Pipes struct {
+	TotalVotes               usecaseVote.Pipe[QueryRequest[int]]
+	TotalVotesForParticipant usecaseVote.Pipe[QueryRequest[map[string]int]]
+	TotalVotesForHour        usecaseVote.Pipe[QueryRequest[map[string]int]]
+	Winner                   usecaseVote.Pipe[QueryRequest[map[string]int]]
+	Ranking                  usecaseVote.Pipe[QueryRequest[map[string]int]]
+	TimeSeries               usecaseVote.Pipe[QueryRequest[map[string]int]]
+	UniqueVoters             usecaseVote.Pipe[QueryRequest[entity.UniqueVoters]]
+}
+
+// Winner is the participant with the most votes of a round
+type Winner struct {
+	ParticipantID string  `json:"participant_id"`
+	Votes         int     `json:"votes"`
+	Percentage    float64 `json:"percentage"`
+
+	// Tie is true when other participants have the same number of votes,
+	// the winner is then the first one in alphabetical order
+	Tie bool `json:"tie"`
+}
+
+// RankingEntry is the position of a participant in the ranking of a round
+type RankingEntry struct {
+	Position      int     `json:"position"`
+	ParticipantID string  `json:"participant_id"`
+	Votes         int     `json:"votes"`
+	Percentage    float64 `json:"percentage"`
+}
+
+// TimeSeriesPoint is the number of votes of an hour of a round
+type TimeSeriesPoint struct {
+	// Timestamp is the Unix timestamp of the beginning of the hour
+	Timestamp  int64 `json:"timestamp"`
+	Votes      int   `json:"votes"`
+	Cumulative int   `json:"cumulative"`
+}
+
+// UniqueVoters is the approximate number of distinct voters of a round, overall and of each participant
+type UniqueVoters struct {
+	Total        int            `json:"total"`
+	Participants map[string]int `json:"participants"`
 }
@@ -12,4 +12,16 @@ type QueryVoteUseCase interface {
 
 	// Returns a map with the total number of votes per hour for a given round.
 	GetTotalVotesForHour(ctx context.Context, roundID string) (map[string]int, error)
+
+	// Returns the participant with the most votes in a given round, the zero Winner when there is no vote.
+	GetWinner(ctx context.Context, roundID string) (Winner, error)
+
+	// Returns the participants of a given round ordered by the number of votes.
+	GetRanking(ctx context.Context, roundID string) ([]RankingEntry, error)
+
+	// Returns the number of votes per hour of a given round, ordered by time.
+	GetTimeSeries(ctx context.Context, roundID string) ([]TimeSeriesPoint, error)
+
+	// Returns the approximate number of unique voters of a given round, overall and for each participant.
+	GetUniqueVoters(ctx context.Context, roundID string) (UniqueVoters, error)
 }
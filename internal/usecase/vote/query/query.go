@@ -1,45 +1,165 @@
 package query
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 
 	usecaseVote "github.com/sergiodii/bbb/internal/usecase/vote"
 )
 
+// ErrHandlerNotConfigured is returned when the pipe of a handler was not given to NewQueryVote
+var ErrHandlerNotConfigured = errors.New("query handler not configured")
+
 type queryVote struct {
-	pipeMap map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[QueryDTO]
+	pipes Pipes
+}
+
+// execute runs the pipe of the handler and returns its typed result
+func execute[R any](ctx context.Context, handler usecaseVote.HandlerFuncEnum, p usecaseVote.Pipe[QueryRequest[R]], roundID string) (R, error) {
+	var zero R
+	if p == nil {
+		return zero, fmt.Errorf("%w: %s", ErrHandlerNotConfigured, handler)
+	}
+
+	result, err := p.Execute(ctx, QueryRequest[R]{RoundID: roundID})
+	if err != nil {
+		return zero, err
+	}
+	return result.Result, nil
+}
+
+// executeMap runs the pipe of the handler, returning an empty map when the round has no vote
+func executeMap(ctx context.Context, handler usecaseVote.HandlerFuncEnum, p usecaseVote.Pipe[QueryRequest[map[string]int]], roundID string) (map[string]int, error) {
+	result, err := execute(ctx, handler, p, roundID)
+	if err != nil || result == nil {
+		return map[string]int{}, err
+	}
+	return result, nil
 }
 
 // QueryVote defines the interface for vote queries.
 func (q *queryVote) GetTotalVotes(ctx context.Context, roundID string) (int, error) {
-	result, err := q.pipeMap[usecaseVote.HandlerFuncGetTotalVotes].Execute(ctx, QueryDTO{RoundID: roundID})
-	if err != nil || result.Result == nil {
-		return 0, err
-	}
-	return result.Result.(int), nil
+	return execute(ctx, usecaseVote.HandlerFuncGetTotalVotes, q.pipes.TotalVotes, roundID)
 }
 
 // GetTotalVotesForParticipant returns a map with the total number of votes for each participant in a given round.
 func (q *queryVote) GetTotalVotesForParticipant(ctx context.Context, roundID string) (map[string]int, error) {
-	result, err := q.pipeMap[usecaseVote.HandlerFuncGetTotalVotesForParticipant].Execute(ctx, QueryDTO{RoundID: roundID})
-	if err != nil || result.Result == nil {
-		return map[string]int{}, err
-	}
-	return result.Result.(map[string]int), nil
+	return executeMap(ctx, usecaseVote.HandlerFuncGetTotalVotesForParticipant, q.pipes.TotalVotesForParticipant, roundID)
 }
 
 // GetTotalVotesForHour returns a map with the total number of votes per hour for a given round.
 func (q *queryVote) GetTotalVotesForHour(ctx context.Context, roundID string) (map[string]int, error) {
-	result, err := q.pipeMap[usecaseVote.HandlerFuncGetTotalVotesForHour].Execute(ctx, QueryDTO{RoundID: roundID})
-	if err != nil || result.Result == nil {
-		return map[string]int{}, err
+	return executeMap(ctx, usecaseVote.HandlerFuncGetTotalVotesForHour, q.pipes.TotalVotesForHour, roundID)
+}
+
+// GetWinner returns the participant with the most votes in a given round.
+func (q *queryVote) GetWinner(ctx context.Context, roundID string) (Winner, error) {
+	totals, err := executeMap(ctx, usecaseVote.HandlerFuncGetWinner, q.pipes.Winner, roundID)
+	if err != nil {
+		return Winner{}, err
+	}
+
+	ranking := NewRanking(totals)
+	if len(ranking) == 0 {
+		return Winner{}, nil
+	}
+
+	first := ranking[0]
+	return Winner{
+		ParticipantID: first.ParticipantID,
+		Votes:         first.Votes,
+		Percentage:    first.Percentage,
+		Tie:           len(ranking) > 1 && ranking[1].Votes == first.Votes,
+	}, nil
+}
+
+// GetRanking returns the participants of a given round ordered by the number of votes.
+func (q *queryVote) GetRanking(ctx context.Context, roundID string) ([]RankingEntry, error) {
+	totals, err := executeMap(ctx, usecaseVote.HandlerFuncGetRanking, q.pipes.Ranking, roundID)
+	if err != nil {
+		return []RankingEntry{}, err
+	}
+	return NewRanking(totals), nil
+}
+
+// GetTimeSeries returns the number of votes per hour of a given round, ordered by time.
+func (q *queryVote) GetTimeSeries(ctx context.Context, roundID string) ([]TimeSeriesPoint, error) {
+	totals, err := executeMap(ctx, usecaseVote.HandlerFuncGetTimeSeries, q.pipes.TimeSeries, roundID)
+	if err != nil {
+		return []TimeSeriesPoint{}, err
+	}
+	return NewTimeSeries(totals)
+}
+
+// GetUniqueVoters returns the approximate number of unique voters of a given round, overall and for each participant.
+func (q *queryVote) GetUniqueVoters(ctx context.Context, roundID string) (UniqueVoters, error) {
+	voters, err := execute(ctx, usecaseVote.HandlerFuncGetUniqueVoters, q.pipes.UniqueVoters, roundID)
+	if err != nil {
+		return UniqueVoters{Participants: map[string]int{}}, err
+	}
+	if voters.Participants == nil {
+		voters.Participants = map[string]int{}
+	}
+	return UniqueVoters{Total: voters.Total, Participants: voters.Participants}, nil
+}
+
+// NewRanking orders the participants by the number of votes, the ties are ordered alphabetically.
+// The participants with the same number of votes share the same position.
+func NewRanking(totals map[string]int) []RankingEntry {
+	total := 0
+	ranking := make([]RankingEntry, 0, len(totals))
+	for participantID, votes := range totals {
+		total += votes
+		ranking = append(ranking, RankingEntry{ParticipantID: participantID, Votes: votes})
+	}
+
+	slices.SortFunc(ranking, func(a, b RankingEntry) int {
+		return cmp.Or(cmp.Compare(b.Votes, a.Votes), cmp.Compare(a.ParticipantID, b.ParticipantID))
+	})
+
+	for i := range ranking {
+		ranking[i].Position = i + 1
+		if i > 0 && ranking[i].Votes == ranking[i-1].Votes {
+			ranking[i].Position = ranking[i-1].Position
+		}
+		if total > 0 {
+			ranking[i].Percentage = float64(ranking[i].Votes) * 100 / float64(total)
+		}
+	}
+	return ranking
+}
+
+// NewTimeSeries orders the votes per hour by time.
+// The keys of the map are the number of hours since the Unix epoch, the format used by the repositories.
+func NewTimeSeries(totals map[string]int) ([]TimeSeriesPoint, error) {
+	series := make([]TimeSeriesPoint, 0, len(totals))
+	for hour, votes := range totals {
+		h, err := strconv.ParseInt(hour, 10, 64)
+		if err != nil {
+			return []TimeSeriesPoint{}, fmt.Errorf("invalid hour %q: %w", hour, err)
+		}
+		series = append(series, TimeSeriesPoint{Timestamp: h * 3600, Votes: votes})
+	}
+
+	slices.SortFunc(series, func(a, b TimeSeriesPoint) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+
+	cumulative := 0
+	for i := range series {
+		cumulative += series[i].Votes
+		series[i].Cumulative = cumulative
 	}
-	return result.Result.(map[string]int), nil
+	return series, nil
 }
 
 // NewQueryVote creates a new instance of QueryVote with the provided execution pipes.
-func NewQueryVote(pipeMap map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[QueryDTO]) QueryVoteUseCase {
+func NewQueryVote(pipes Pipes) QueryVoteUseCase {
 	return &queryVote{
-		pipeMap: pipeMap,
+		pipes: pipes,
 	}
 }
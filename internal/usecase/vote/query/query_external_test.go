@@ -7,19 +7,17 @@ import (
 	"github.com/sergiodii/bbb/internal/usecase/vote/query"
 
 	"github.com/stretchr/testify/assert"
-
-	usecaseVote "github.com/sergiodii/bbb/internal/usecase/vote"
 )
 
-type pipeMock struct {
-	ExecutedFuncs []func(context.Context, query.QueryDTO) (query.QueryDTO, error)
+type pipeMock[T any] struct {
+	ExecutedFuncs []func(context.Context, T) (T, error)
 }
 
-func (p *pipeMock) Enqueue(funcs ...func(context.Context, query.QueryDTO) (query.QueryDTO, error)) {
+func (p *pipeMock[T]) Enqueue(funcs ...func(context.Context, T) (T, error)) {
 	p.ExecutedFuncs = append(p.ExecutedFuncs, funcs...)
 }
 
-func (p *pipeMock) Execute(ctx context.Context, dto query.QueryDTO) (query.QueryDTO, error) {
+func (p *pipeMock[T]) Execute(ctx context.Context, dto T) (T, error) {
 	var err error
 	for _, fn := range p.ExecutedFuncs {
 		dto, err = fn(ctx, dto)
@@ -33,58 +31,44 @@ func (p *pipeMock) Execute(ctx context.Context, dto query.QueryDTO) (query.Query
 func TestGetVotesFromParticipant(t *testing.T) {
 
 	t.Run("Should return total votes", func(t *testing.T) {
-		pm := &pipeMock{}
-		pm.Enqueue(func(ctx context.Context, dto query.QueryDTO) (query.QueryDTO, error) {
+		pm := &pipeMock[query.QueryRequest[int]]{}
+		pm.Enqueue(func(ctx context.Context, dto query.QueryRequest[int]) (query.QueryRequest[int], error) {
 			dto.Result = 1 * 5
 			return dto, nil
 		})
 
-		execution := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[query.QueryDTO]{
-			usecaseVote.HandlerFuncGetTotalVotes: pm,
-		}
-
-		q := query.NewQueryVote(execution)
+		q := query.NewQueryVote(query.Pipes{TotalVotes: pm})
 		result, err := q.GetTotalVotes(context.Background(), "1")
 		assert.NoError(t, err)
 		assert.Equal(t, 5, result)
 	})
 
 	t.Run("Should return total votes for participant", func(t *testing.T) {
-		pm := &pipeMock{}
-		pm.Enqueue(func(ctx context.Context, dto query.QueryDTO) (query.QueryDTO, error) {
+		pm := &pipeMock[query.QueryRequest[map[string]int]]{}
+		pm.Enqueue(func(ctx context.Context, dto query.QueryRequest[map[string]int]) (query.QueryRequest[map[string]int], error) {
 			dto.Result = map[string]int{"participant1": 10, "participant2": 5}
 			return dto, nil
 		})
 
-		execution := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[query.QueryDTO]{
-			usecaseVote.HandlerFuncGetTotalVotesForParticipant: pm,
-		}
-
-		q := query.NewQueryVote(execution)
+		q := query.NewQueryVote(query.Pipes{TotalVotesForParticipant: pm})
 		result, err := q.GetTotalVotesForParticipant(context.Background(), "1")
 		assert.NoError(t, err)
 		assert.Equal(t, map[string]int{"participant1": 10, "participant2": 5}, result)
 	})
 
 	t.Run("Should return total votes for participant summed", func(t *testing.T) {
-		pm := &pipeMock{}
-		pm.Enqueue(func(ctx context.Context, dto query.QueryDTO) (query.QueryDTO, error) {
+		pm := &pipeMock[query.QueryRequest[map[string]int]]{}
+		pm.Enqueue(func(ctx context.Context, dto query.QueryRequest[map[string]int]) (query.QueryRequest[map[string]int], error) {
 			dto.Result = map[string]int{"participant1": 10, "participant2": 5}
 			return dto, nil
 		})
-		pm.Enqueue(func(ctx context.Context, dto query.QueryDTO) (query.QueryDTO, error) {
-			currentResult := dto.Result.(map[string]int)
-			currentResult["participant1"] += 15
-			currentResult["participant3"] = 7
-			dto.Result = currentResult
+		pm.Enqueue(func(ctx context.Context, dto query.QueryRequest[map[string]int]) (query.QueryRequest[map[string]int], error) {
+			dto.Result["participant1"] += 15
+			dto.Result["participant3"] = 7
 			return dto, nil
 		})
 
-		execution := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[query.QueryDTO]{
-			usecaseVote.HandlerFuncGetTotalVotesForParticipant: pm,
-		}
-
-		q := query.NewQueryVote(execution)
+		q := query.NewQueryVote(query.Pipes{TotalVotesForParticipant: pm})
 		result, err := q.GetTotalVotesForParticipant(context.Background(), "1")
 		assert.NoError(t, err)
 		assert.Equal(t, map[string]int{"participant1": 25, "participant2": 5, "participant3": 7}, result)
@@ -92,16 +76,12 @@ func TestGetVotesFromParticipant(t *testing.T) {
 
 	t.Run("Should handle pipe execution error", func(t *testing.T) {
 
-		pm := &pipeMock{}
-		pm.Enqueue(func(ctx context.Context, dto query.QueryDTO) (query.QueryDTO, error) {
+		pm := &pipeMock[query.QueryRequest[int]]{}
+		pm.Enqueue(func(ctx context.Context, dto query.QueryRequest[int]) (query.QueryRequest[int], error) {
 			return dto, assert.AnError
 		})
 
-		execution := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[query.QueryDTO]{
-			usecaseVote.HandlerFuncGetTotalVotes: pm,
-		}
-
-		q := query.NewQueryVote(execution)
+		q := query.NewQueryVote(query.Pipes{TotalVotes: pm})
 		_, err := q.GetTotalVotes(context.Background(), "1")
 		assert.Error(t, err)
 	})
@@ -6,7 +6,7 @@ import (
 
 	"github.com/sergiodii/bbb/internal/usecase/vote/query/mock"
 
-	usecaseVote "github.com/sergiodii/bbb/internal/usecase/vote"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewQueryVote(t *testing.T) {
@@ -14,14 +14,10 @@ func TestNewQueryVote(t *testing.T) {
 	t.Run("Should create a new QueryVote instance", func(t *testing.T) {
 
 		// Arrange
-		pipe := mock.NewPipeMock[QueryDTO]()
-
-		pipeMap := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[QueryDTO]{
-			usecaseVote.HandlerFuncGetTotalVotes: pipe,
-		}
+		pipe := mock.NewPipeMock[QueryRequest[int]]()
 
 		// Act
-		queryVote := NewQueryVote(pipeMap)
+		queryVote := NewQueryVote(Pipes{TotalVotes: pipe})
 
 		// Assert
 		if queryVote == nil {
@@ -32,10 +28,8 @@ func TestNewQueryVote(t *testing.T) {
 	t.Run("Should handle empty orderedExecutionPipes", func(t *testing.T) {
 
 		// Arrange
-		orderedExecutionPipes := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[QueryDTO]{}
-
 		// Act
-		queryVote := NewQueryVote(orderedExecutionPipes)
+		queryVote := NewQueryVote(Pipes{})
 
 		// Assert
 		if queryVote == nil {
@@ -46,15 +40,11 @@ func TestNewQueryVote(t *testing.T) {
 	t.Run("Should execute GetTotalVotes without error", func(t *testing.T) {
 
 		// Arrange
-		pipe := mock.NewPipeMock[QueryDTO]()
+		pipe := mock.NewPipeMock[QueryRequest[int]]()
 
-		pipe.On("Execute", context.Background(), QueryDTO{RoundID: "round1"}).Return(QueryDTO{Result: 42}, nil)
+		pipe.On("Execute", context.Background(), QueryRequest[int]{RoundID: "round1"}).Return(QueryRequest[int]{Result: 42}, nil)
 
-		orderedExecutionPipes := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[QueryDTO]{
-			usecaseVote.HandlerFuncGetTotalVotes: pipe,
-		}
-
-		queryVote := NewQueryVote(orderedExecutionPipes)
+		queryVote := NewQueryVote(Pipes{TotalVotes: pipe})
 
 		// Act
 		totalVotes, err := queryVote.GetTotalVotes(context.Background(), "round1")
@@ -71,16 +61,12 @@ func TestNewQueryVote(t *testing.T) {
 	t.Run("Should execute GetTotalVotesForParticipant without error", func(t *testing.T) {
 
 		// Arrange
-		pipe := mock.NewPipeMock[QueryDTO]()
+		pipe := mock.NewPipeMock[QueryRequest[map[string]int]]()
 
 		expectedResult := map[string]int{"participant1": 10, "participant2": 20}
-		pipe.On("Execute", context.Background(), QueryDTO{RoundID: "round1"}).Return(QueryDTO{Result: expectedResult}, nil)
-
-		orderedExecutionPipes := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[QueryDTO]{
-			usecaseVote.HandlerFuncGetTotalVotesForParticipant: pipe,
-		}
+		pipe.On("Execute", context.Background(), QueryRequest[map[string]int]{RoundID: "round1"}).Return(QueryRequest[map[string]int]{Result: expectedResult}, nil)
 
-		queryVote := NewQueryVote(orderedExecutionPipes)
+		queryVote := NewQueryVote(Pipes{TotalVotesForParticipant: pipe})
 
 		// Act
 		result, err := queryVote.GetTotalVotesForParticipant(context.Background(), "round1")
@@ -101,16 +87,12 @@ func TestNewQueryVote(t *testing.T) {
 
 	t.Run("Should execute GetTotalVotesForHour without error", func(t *testing.T) {
 		// Arrange
-		pipe := mock.NewPipeMock[QueryDTO]()
+		pipe := mock.NewPipeMock[QueryRequest[map[string]int]]()
 
 		expectedResult := map[string]int{"10:00": 5, "11:00": 15}
-		pipe.On("Execute", context.Background(), QueryDTO{RoundID: "round1"}).Return(QueryDTO{Result: expectedResult}, nil)
-
-		orderedExecutionPipes := map[usecaseVote.HandlerFuncEnum]usecaseVote.Pipe[QueryDTO]{
-			usecaseVote.HandlerFuncGetTotalVotesForHour: pipe,
-		}
+		pipe.On("Execute", context.Background(), QueryRequest[map[string]int]{RoundID: "round1"}).Return(QueryRequest[map[string]int]{Result: expectedResult}, nil)
 
-		queryVote := NewQueryVote(orderedExecutionPipes)
+		queryVote := NewQueryVote(Pipes{TotalVotesForHour: pipe})
 
 		// Act
 		result, err := queryVote.GetTotalVotesForHour(context.Background(), "round1")
@@ -128,4 +110,85 @@ func TestNewQueryVote(t *testing.T) {
 			}
 		}
 	})
+	t.Run("Should return ErrHandlerNotConfigured when the pipe is missing", func(t *testing.T) {
+		// Arrange
+		queryVote := NewQueryVote(Pipes{})
+
+		// Act
+		_, err := queryVote.GetWinner(context.Background(), "round1")
+
+		// Assert
+		assert.ErrorIs(t, err, ErrHandlerNotConfigured)
+	})
+
+	t.Run("Should return the winner from the votes of each participant", func(t *testing.T) {
+		// Arrange
+		pipe := mock.NewPipeMock[QueryRequest[map[string]int]]()
+		pipe.On("Execute", context.Background(), QueryRequest[map[string]int]{RoundID: "round1"}).
+			Return(QueryRequest[map[string]int]{Result: map[string]int{"alice": 30, "bob": 10}}, nil)
+
+		queryVote := NewQueryVote(Pipes{Winner: pipe})
+
+		// Act
+		winner, err := queryVote.GetWinner(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Winner{ParticipantID: "alice", Votes: 30, Percentage: 75}, winner)
+	})
+
+	t.Run("Should return the zero winner when the round has no vote", func(t *testing.T) {
+		// Arrange
+		pipe := mock.NewPipeMock[QueryRequest[map[string]int]]()
+		pipe.On("Execute", context.Background(), QueryRequest[map[string]int]{RoundID: "round1"}).
+			Return(QueryRequest[map[string]int]{}, nil)
+
+		queryVote := NewQueryVote(Pipes{Winner: pipe})
+
+		// Act
+		winner, err := queryVote.GetWinner(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Winner{}, winner)
+	})
+
+	t.Run("Should return the ranking with the ties sharing the position", func(t *testing.T) {
+		// Arrange
+		pipe := mock.NewPipeMock[QueryRequest[map[string]int]]()
+		pipe.On("Execute", context.Background(), QueryRequest[map[string]int]{RoundID: "round1"}).
+			Return(QueryRequest[map[string]int]{Result: map[string]int{"carol": 5, "alice": 10, "bob": 5}}, nil)
+
+		queryVote := NewQueryVote(Pipes{Ranking: pipe})
+
+		// Act
+		ranking, err := queryVote.GetRanking(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []RankingEntry{
+			{Position: 1, ParticipantID: "alice", Votes: 10, Percentage: 50},
+			{Position: 2, ParticipantID: "bob", Votes: 5, Percentage: 25},
+			{Position: 2, ParticipantID: "carol", Votes: 5, Percentage: 25},
+		}, ranking)
+	})
+
+	t.Run("Should return the time series ordered by time", func(t *testing.T) {
+		// Arrange
+		pipe := mock.NewPipeMock[QueryRequest[map[string]int]]()
+		pipe.On("Execute", context.Background(), QueryRequest[map[string]int]{RoundID: "round1"}).
+			Return(QueryRequest[map[string]int]{Result: map[string]int{"470856": 4, "470855": 2}}, nil)
+
+		queryVote := NewQueryVote(Pipes{TimeSeries: pipe})
+
+		// Act
+		series, err := queryVote.GetTimeSeries(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []TimeSeriesPoint{
+			{Timestamp: 470855 * 3600, Votes: 2, Cumulative: 2},
+			{Timestamp: 470856 * 3600, Votes: 4, Cumulative: 6},
+		}, series)
+	})
 }
@@ -0,0 +1,204 @@
+// Package apitest runs the whole voting API in the process, for end to end tests without Docker.
+//
+// Start builds the unified engine of the api command (the query routes under /query and the command
+// routes under /command) against the memory repository or an embedded Redis (miniredis) and serves it
+// on a random port:
+//
+//	srv := apitest.Start(t, apitest.WithBackend(apitest.BackendMiniredis))
+//	err := srv.Client.Vote(ctx, "round1", "alice")
+//	total, err := srv.Client.Total(ctx, "round1")
+package apitest
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/cmd/api"
+	"github.com/sergiodii/bbb/internal/config"
+	"github.com/sergiodii/bbb/pkg/client"
+	"github.com/sergiodii/bbb/pkg/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// Backend is where the votes are stored
+type Backend string
+
+const (
+	// BackendMemory stores the votes in the memory repository
+	BackendMemory Backend = "memory"
+
+	// BackendMiniredis stores the votes in an embedded Redis with the default pipeline,
+	// the same used in production
+	BackendMiniredis Backend = "miniredis"
+)
+
+// memoryPipeline is the pipeline of the memory backend
+const memoryPipeline = `repositories:
+  - name: memory
+    type: memory
+idempotency: memory
+`
+
+// AdminToken is the admin token of the servers, unless replaced by WithAdminToken
+const AdminToken = "apitest-admin-token"
+
+// shutdownTimeout is how long the cleanup waits for the votes being registered in the background
+const shutdownTimeout = 5 * time.Second
+
+type options struct {
+	backend         Backend
+	pipeline        string
+	blockedIPRanges []string
+	trustedProxies  []string
+	queryCacheTTL   time.Duration
+	adminToken      string
+}
+
+// Option configures the server
+type Option func(*options)
+
+// WithBackend sets where the votes are stored, the memory repository by default
+func WithBackend(backend Backend) Option {
+	return func(o *options) {
+		o.backend = backend
+	}
+}
+
+// WithPipeline replaces the pipeline of the backend by the given YAML configuration.
+// With the miniredis backend, the redis repositories without an addr use the embedded Redis.
+func WithPipeline(yaml string) Option {
+	return func(o *options) {
+		o.pipeline = yaml
+	}
+}
+
+// WithBlockedIPRanges blocks the requests from the IP prefixes, the clients of the test come from 127.0.0.1
+func WithBlockedIPRanges(ranges ...string) Option {
+	return func(o *options) {
+		o.blockedIPRanges = ranges
+	}
+}
+
+// WithTrustedProxies trusts the headers of the requests from the proxies, the client IP and the X-Voter-ID,
+// as the trusted_proxies configuration. The clients of the test come from 127.0.0.1.
+func WithTrustedProxies(proxies ...string) Option {
+	return func(o *options) {
+		o.trustedProxies = proxies
+	}
+}
+
+// WithQueryCache caches the results of the queries for the TTL, as the query.cache_ttl configuration
+func WithQueryCache(ttl time.Duration) Option {
+	return func(o *options) {
+		o.queryCacheTTL = ttl
+	}
+}
+
+// WithAdminToken replaces the admin token required to create the rounds, an empty token disables their creation
+func WithAdminToken(token string) Option {
+	return func(o *options) {
+		o.adminToken = token
+	}
+}
+
+// Server is the voting API running in the process
+type Server struct {
+	// URL is the base URL of the API, e.g. http://127.0.0.1:41234
+	URL string
+
+	// Client is a client of the API with the admin token, without retries so the tests see every error
+	Client *client.Client
+
+	// Redis is the embedded Redis of the miniredis backend, nil with the memory backend
+	Redis *miniredis.Miniredis
+
+	engine *api.Engine
+}
+
+// Start builds the API and serves it on a random port until the end of the test
+func Start(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+
+	o := options{backend: BackendMemory, adminToken: AdminToken}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &Server{}
+	cfg := config.Default()
+	cfg.BlockedIPRanges = o.blockedIPRanges
+	cfg.TrustedProxies = o.trustedProxies
+	cfg.Query.CacheTTL = o.queryCacheTTL
+	cfg.AdminToken = o.adminToken
+
+	switch o.backend {
+	case BackendMemory:
+		if o.pipeline == "" {
+			o.pipeline = memoryPipeline
+		}
+	case BackendMiniredis:
+		s.Redis = miniredis.RunT(t)
+		cfg.Redis.Addr = s.Redis.Addr()
+	default:
+		t.Fatalf("apitest: unknown backend %q", o.backend)
+	}
+
+	if o.pipeline != "" {
+		cfg.Pipeline = filepath.Join(t.TempDir(), "pipeline.yaml")
+		if err := os.WriteFile(cfg.Pipeline, []byte(o.pipeline), 0o600); err != nil {
+			t.Fatalf("apitest: writing the pipeline: %v", err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	engine, err := api.NewEngine(cfg, logger.Discard())
+	if err != nil {
+		t.Fatalf("apitest: building the API: %v", err)
+	}
+	s.engine = engine
+
+	server := httptest.NewServer(engine.Handler())
+	s.URL = server.URL
+	s.Client = client.New(server.URL,
+		client.WithHTTPClient(server.Client()),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+		client.WithAdminToken(o.adminToken),
+	)
+
+	t.Cleanup(func() {
+		server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := engine.Wait(ctx); err != nil {
+			t.Errorf("apitest: waiting for the background votes: %v", err)
+		}
+		if err := engine.Close(); err != nil {
+			t.Errorf("apitest: closing the API: %v", err)
+		}
+	})
+
+	return s
+}
+
+// CommandURL returns the base URL of the command routes, e.g. for loadtest --url
+func (s *Server) CommandURL() string {
+	return s.URL + "/command"
+}
+
+// QueryURL returns the base URL of the query routes, e.g. for loadtest --query-url
+func (s *Server) QueryURL() string {
+	return s.URL + "/query"
+}
+
+// Wait blocks until the votes being registered in the background are done,
+// so the secondary repositories can be checked
+func (s *Server) Wait(ctx context.Context) error {
+	return s.engine.Wait(ctx)
+}
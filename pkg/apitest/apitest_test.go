@@ -0,0 +1,212 @@
+package apitest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/pkg/apitest"
+	"github.com/sergiodii/bbb/pkg/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	for _, backend := range []apitest.Backend{apitest.BackendMemory, apitest.BackendMiniredis} {
+		t.Run("Should register and query the votes with the "+string(backend)+" backend", func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			srv := apitest.Start(t, apitest.WithBackend(backend))
+
+			// Act
+			for _, participant := range []string{"alice", "bob", "alice"} {
+				require.NoError(t, srv.Client.Vote(ctx, "round1", participant))
+			}
+			require.NoError(t, srv.Wait(ctx))
+
+			total, totalErr := srv.Client.Total(ctx, "round1")
+			participants, participantsErr := srv.Client.Participants(ctx, "round1")
+			hours, hoursErr := srv.Client.Hours(ctx, "round1")
+			winner, winnerErr := srv.Client.Winner(ctx, "round1")
+			ranking, rankingErr := srv.Client.Ranking(ctx, "round1")
+			series, seriesErr := srv.Client.TimeSeries(ctx, "round1")
+
+			// Assert
+			assert.NoError(t, errors.Join(totalErr, participantsErr, hoursErr, winnerErr, rankingErr, seriesErr))
+			assert.Equal(t, 3, total)
+			assert.Equal(t, map[string]int{"alice": 2, "bob": 1}, participants)
+			assert.Len(t, hours, 1)
+			assert.Equal(t, "alice", winner.ParticipantID)
+			assert.Equal(t, 2, winner.Votes)
+			assert.Len(t, ranking, 2)
+			assert.Len(t, series, 1)
+			assert.Equal(t, 3, series[0].Cumulative)
+		})
+	}
+
+	t.Run("Should store the votes in the embedded Redis", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		srv := apitest.Start(t, apitest.WithBackend(apitest.BackendMiniredis))
+
+		// Act
+		err := srv.Client.Vote(ctx, "round1", "alice")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1", srv.Redis.HGet("round:{round1}:participants", "alice"))
+	})
+
+	t.Run("Should return the status of a rejected request", func(t *testing.T) {
+		// Arrange
+		srv := apitest.Start(t)
+		req, _ := http.NewRequest(http.MethodPost, srv.CommandURL()+"/round1", nil)
+
+		// Act
+		resp, err := http.DefaultClient.Do(req)
+
+		// Assert
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Should return not found for an unknown round and zero votes for a created round", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		srv := apitest.Start(t, apitest.WithBackend(apitest.BackendMiniredis))
+
+		// Act
+		_, unknownErr := srv.Client.Total(ctx, "round1")
+		createErr := srv.Client.CreateRound(ctx, "round1", client.Round{
+			Name:         "Paredão 1",
+			Participants: []client.Participant{{ID: "alice", Name: "Alice"}, {ID: "bob", Name: "Bob"}},
+		})
+		total, totalErr := srv.Client.Total(ctx, "round1")
+		participants, participantsErr := srv.Client.Participants(ctx, "round1")
+
+		// Assert
+		assert.ErrorIs(t, unknownErr, client.ErrNotFound)
+		require.NoError(t, errors.Join(createErr, totalErr, participantsErr))
+		assert.Equal(t, 0, total)
+		assert.Empty(t, participants)
+	})
+
+	t.Run("Should count the unique voters by voter ID or by IP", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		srv := apitest.Start(t, apitest.WithBackend(apitest.BackendMiniredis), apitest.WithTrustedProxies("127.0.0.1"))
+		vote := func(participant, voterID, ip string) {
+			req, _ := http.NewRequest(http.MethodPost, srv.CommandURL()+"/round1", strings.NewReader(`{"participant_id":"`+participant+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Voter-ID", voterID)
+			req.Header.Set("X-Real-IP", ip)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+
+		// Act
+		vote("alice", "v1", "10.0.0.1")
+		vote("alice", "v1", "10.0.0.2")
+		vote("bob", "", "10.0.0.1")
+		vote("bob", "", "10.0.0.1")
+		voters, err := srv.Client.Voters(ctx, "round1")
+		_, unknownErr := srv.Client.Voters(ctx, "unknown")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, client.UniqueVoters{Total: 2, Participants: map[string]int{"alice": 1, "bob": 1}}, voters)
+		assert.ErrorIs(t, unknownErr, client.ErrNotFound)
+	})
+
+	t.Run("Should ignore the voter ID and the IP headers of an untrusted client", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		srv := apitest.Start(t)
+		for i, voterID := range []string{"v1", "v2", "v3"} {
+			req, _ := http.NewRequest(http.MethodPost, srv.CommandURL()+"/round1", strings.NewReader(`{"participant_id":"alice"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Voter-ID", voterID)
+			req.Header.Set("X-Forwarded-For", "10.0.0."+strconv.Itoa(i))
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+
+		// Act
+		voters, err := srv.Client.Voters(ctx, "round1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, voters.Total)
+	})
+
+	t.Run("Should cache the queries and answer them with an ETag", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		srv := apitest.Start(t, apitest.WithQueryCache(time.Minute))
+		require.NoError(t, srv.Client.Vote(ctx, "round1", "alice"))
+		get := func(etag string) *http.Response {
+			req, _ := http.NewRequest(http.MethodGet, srv.QueryURL()+"/round1/participant", nil)
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp
+		}
+
+		// Act
+		first := get("")
+		require.NoError(t, srv.Client.Vote(ctx, "round1", "bob"))
+		revalidated := get(first.Header.Get("ETag"))
+		participants, err := srv.Client.Participants(ctx, "round1")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, first.StatusCode)
+		assert.NotEmpty(t, first.Header.Get("ETag"))
+		assert.Equal(t, "public, max-age=60", first.Header.Get("Cache-Control"))
+		assert.Equal(t, http.StatusNotModified, revalidated.StatusCode)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"alice": 1}, participants)
+	})
+
+	t.Run("Should answer the errors with problem details", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		srv := apitest.Start(t, apitest.WithBackend(apitest.BackendMiniredis))
+
+		// Act
+		voteErr := srv.Client.Vote(ctx, "round1", "")
+		resp, err := http.Get(srv.URL + "/unknown")
+		require.NoError(t, err)
+		resp.Body.Close()
+		srv.Redis.Close()
+		_, queryErr := srv.Client.Total(ctx, "round1")
+
+		// Assert
+		var voteAPIErr, queryAPIErr *client.APIError
+		require.ErrorAs(t, voteErr, &voteAPIErr)
+		assert.Equal(t, http.StatusBadRequest, voteAPIErr.StatusCode)
+		assert.Equal(t, "validation_error", voteAPIErr.Code)
+		assert.Equal(t, "participant_id is required", voteAPIErr.Message)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+
+		// the error of the Redis client is not exposed
+		require.ErrorAs(t, queryErr, &queryAPIErr)
+		assert.Equal(t, http.StatusServiceUnavailable, queryAPIErr.StatusCode)
+		assert.Equal(t, "unavailable", queryAPIErr.Code)
+		assert.Equal(t, "the vote store is unavailable", queryAPIErr.Message)
+	})
+}
@@ -0,0 +1,327 @@
+// Package client is the Go SDK of the voting API.
+//
+// It registers the votes, one by one or in batches, and calls every query of a round, with typed
+// errors, retries and connection pooling:
+//
+//	c := client.New("http://localhost:8080")
+//	err := c.Vote(ctx, "round1", "alice")
+//	if errors.Is(err, client.ErrRateLimited) { ... }
+//	ranking, err := c.Ranking(ctx, "round1")
+package client
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header with the key of a vote, the same in every attempt of the vote,
+// so the API does not register again a vote it already registered
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// The defaults of a new client
+const (
+	DefaultTimeout          = 10 * time.Second
+	DefaultBatchConcurrency = 16
+)
+
+// Client is a client of the voting API, it is safe for concurrent use
+type Client struct {
+	commandURL       string
+	queryURL         string
+	http             *http.Client
+	retry            RetryPolicy
+	batchConcurrency int
+	adminToken       string
+}
+
+// Option configures the client
+type Option func(*Client)
+
+// WithHTTPClient replaces the HTTP client, e.g. to use a custom transport
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.http = c
+	}
+}
+
+// WithCommandURL sets the base URL of the command routes, e.g. the command-api at http://localhost:8082
+func WithCommandURL(u string) Option {
+	return func(c *Client) {
+		c.commandURL = strings.TrimSuffix(u, "/")
+	}
+}
+
+// WithQueryURL sets the base URL of the query routes, e.g. the query-api at http://localhost:8081
+func WithQueryURL(u string) Option {
+	return func(c *Client) {
+		c.queryURL = strings.TrimSuffix(u, "/")
+	}
+}
+
+// WithRetryPolicy replaces the retry policy, a MaxAttempts of 1 disables the retries
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithBatchConcurrency sets how many votes of a batch are sent at the same time
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) {
+		c.batchConcurrency = max(n, 1)
+	}
+}
+
+// WithAdminToken sets the admin token of the API, sent by CreateRound
+func WithAdminToken(token string) Option {
+	return func(c *Client) {
+		c.adminToken = token
+	}
+}
+
+// New creates a client of the unified API (the api command) at the base URL, whose command
+// routes are under /command and query routes under /query. The standalone APIs are
+// configured with WithCommandURL and WithQueryURL.
+func New(baseURL string, opts ...Option) *Client {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	c := &Client{
+		commandURL:       baseURL + "/command",
+		queryURL:         baseURL + "/query",
+		retry:            DefaultRetryPolicy,
+		batchConcurrency: DefaultBatchConcurrency,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.http == nil {
+		c.http = &http.Client{Timeout: DefaultTimeout, Transport: newTransport()}
+	}
+	return c
+}
+
+// newTransport keeps the connections to the API open between the requests,
+// enough for the votes of a batch to reuse them
+func newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = 100
+	t.MaxIdleConnsPerHost = 100
+	t.IdleConnTimeout = 90 * time.Second
+	return t
+}
+
+// Vote registers a vote for the participant in the round.
+// Each call is a new vote, with a new idempotency key sent in every attempt of the vote.
+func (c *Client) Vote(ctx context.Context, roundID, participantID string) error {
+	body, err := json.Marshal(voteRequest{ParticipantID: participantID})
+	if err != nil {
+		return err
+	}
+
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return err
+	}
+	header := http.Header{IdempotencyKeyHeader: {key}}
+	return c.do(ctx, http.MethodPost, c.commandURL+"/"+url.PathEscape(roundID), body, header, http.StatusCreated, nil)
+}
+
+// CreateRound creates the round with its metadata, the queries of a created round without votes return zero.
+// The round is replaced when it already exists. It is an administrative operation, see WithAdminToken.
+func (c *Client) CreateRound(ctx context.Context, roundID string, round Round) error {
+	body, err := json.Marshal(round)
+	if err != nil {
+		return err
+	}
+	var header http.Header
+	if c.adminToken != "" {
+		header = http.Header{"Authorization": {"Bearer " + c.adminToken}}
+	}
+	return c.do(ctx, http.MethodPut, c.commandURL+"/"+url.PathEscape(roundID), body, header, http.StatusCreated, nil)
+}
+
+// VoteBatch registers the votes concurrently, up to the batch concurrency at the same time.
+// Every vote is sent even when some fail; the failures are returned in a *BatchError.
+func (c *Client) VoteBatch(ctx context.Context, votes []Vote) error {
+	errs := make([]error, len(votes))
+	slots := make(chan struct{}, c.batchConcurrency)
+	var wg sync.WaitGroup
+	failed := false
+
+	for i, vote := range votes {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			errs[i], failed = ctx.Err(), true
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			errs[i] = c.Vote(ctx, vote.RoundID, vote.ParticipantID)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		failed = failed || err != nil
+	}
+	if failed {
+		return &BatchError{Errors: errs}
+	}
+	return nil
+}
+
+// Total returns the total number of votes of the round
+func (c *Client) Total(ctx context.Context, roundID string) (int, error) {
+	var res totalResponse
+	err := c.query(ctx, roundID, "", &res)
+	return res.Total, err
+}
+
+// Participants returns the number of votes of each participant of the round
+func (c *Client) Participants(ctx context.Context, roundID string) (map[string]int, error) {
+	var res map[string]int
+	err := c.query(ctx, roundID, "/participant", &res)
+	return res, err
+}
+
+// Hours returns the number of votes per hour of the round, the hour is the number of hours since the epoch
+func (c *Client) Hours(ctx context.Context, roundID string) (map[string]int, error) {
+	var res map[string]int
+	err := c.query(ctx, roundID, "/hour", &res)
+	return res, err
+}
+
+// Winner returns the participant with the most votes in the round
+func (c *Client) Winner(ctx context.Context, roundID string) (Winner, error) {
+	var res Winner
+	err := c.query(ctx, roundID, "/winner", &res)
+	return res, err
+}
+
+// Ranking returns the participants of the round ordered by the number of votes
+func (c *Client) Ranking(ctx context.Context, roundID string) ([]RankingEntry, error) {
+	var res []RankingEntry
+	err := c.query(ctx, roundID, "/ranking", &res)
+	return res, err
+}
+
+// TimeSeries returns the number of votes per hour of the round, ordered by time
+func (c *Client) TimeSeries(ctx context.Context, roundID string) ([]TimeSeriesPoint, error) {
+	var res []TimeSeriesPoint
+	err := c.query(ctx, roundID, "/timeseries", &res)
+	return res, err
+}
+
+// Voters returns the approximate number of unique voters of the round, overall and of each participant
+func (c *Client) Voters(ctx context.Context, roundID string) (UniqueVoters, error) {
+	var res UniqueVoters
+	err := c.query(ctx, roundID, "/voters", &res)
+	return res, err
+}
+
+func (c *Client) query(ctx context.Context, roundID, path string, v any) error {
+	return c.do(ctx, http.MethodGet, c.queryURL+"/"+url.PathEscape(roundID)+path, nil, nil, http.StatusOK, v)
+}
+
+// do sends the request, retrying it with the retry policy, and decodes the response into v when v is not nil.
+// The header, e.g. with the idempotency key, is sent in every attempt.
+func (c *Client) do(ctx context.Context, method, u string, body []byte, header http.Header, status int, v any) error {
+	attempts := max(c.retry.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var code int
+		code, err = c.send(ctx, method, u, body, header, status, v)
+		if err == nil || ctx.Err() != nil || attempt == attempts || !retryable(method, code) {
+			return err
+		}
+
+		wait := c.retry.backoff(attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			wait = max(wait, apiErr.RetryAfter)
+		}
+
+		// a wait beyond the deadline would only end with the deadline exceeded, the API error is more useful
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// send sends the request once, it returns the status of the response, zero when there is none
+func (c *Client) send(ctx context.Context, method, u string, body []byte, header http.Header, status int, v any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		return resp.StatusCode, newAPIError(resp)
+	}
+	if v == nil {
+		// drain the body, so the connection goes back to the pool
+		io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(v)
+}
+
+func newAPIError(resp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body problem
+	if json.Unmarshal(raw, &body) == nil && (body.Code != "" || body.Title != "") {
+		e.Code, e.Message = body.Code, cmp.Or(body.Detail, body.Title)
+	} else {
+		e.Message = string(bytes.TrimSpace(raw))
+	}
+	return e
+}
+
+// newIdempotencyKey returns a random key, so the API can recognize the attempts of a vote
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,354 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/pkg/apitest"
+	"github.com/sergiodii/bbb/pkg/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetry retries without waiting, so the tests only wait for the Retry-After of the API
+var fastRetry = client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+// roundTripFunc is an http.RoundTripper made of a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestClientWithTheAPI(t *testing.T) {
+	t.Run("Should register the votes and call every query", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		srv := apitest.Start(t)
+		c := client.New(srv.URL)
+
+		// Act
+		voteErr := c.Vote(ctx, "round1", "alice")
+		batchErr := c.VoteBatch(ctx, []client.Vote{
+			{RoundID: "round1", ParticipantID: "bob"},
+			{RoundID: "round1", ParticipantID: "alice"},
+			{RoundID: "round1", ParticipantID: "alice"},
+		})
+		total, totalErr := c.Total(ctx, "round1")
+		participants, participantsErr := c.Participants(ctx, "round1")
+		hours, hoursErr := c.Hours(ctx, "round1")
+		winner, winnerErr := c.Winner(ctx, "round1")
+		ranking, rankingErr := c.Ranking(ctx, "round1")
+		series, seriesErr := c.TimeSeries(ctx, "round1")
+		voters, votersErr := c.Voters(ctx, "round1")
+
+		// Assert
+		require.NoError(t, errors.Join(voteErr, batchErr, totalErr, participantsErr, hoursErr, winnerErr, rankingErr, seriesErr, votersErr))
+		assert.Equal(t, 4, total)
+		assert.Equal(t, map[string]int{"alice": 3, "bob": 1}, participants)
+		assert.Len(t, hours, 1)
+		assert.Equal(t, client.Winner{ParticipantID: "alice", Votes: 3, Percentage: 75}, winner)
+		assert.Equal(t, []client.RankingEntry{
+			{Position: 1, ParticipantID: "alice", Votes: 3, Percentage: 75},
+			{Position: 2, ParticipantID: "bob", Votes: 1, Percentage: 25},
+		}, ranking)
+		require.Len(t, series, 1)
+		assert.Equal(t, 4, series[0].Cumulative)
+
+		// every vote comes from the IP of the test
+		assert.Equal(t, client.UniqueVoters{Total: 1, Participants: map[string]int{"alice": 1, "bob": 1}}, voters)
+	})
+
+	t.Run("Should create a round only with the admin token", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		srv := apitest.Start(t)
+		disabled := apitest.Start(t, apitest.WithAdminToken(""))
+		round := client.Round{Name: "Paredão 1"}
+
+		// Act
+		adminErr := client.New(srv.URL, client.WithAdminToken(apitest.AdminToken)).CreateRound(ctx, "round1", round)
+		missingErr := client.New(srv.URL).CreateRound(ctx, "round2", round)
+		wrongErr := client.New(srv.URL, client.WithAdminToken("wrong")).CreateRound(ctx, "round3", round)
+		disabledErr := client.New(disabled.URL, client.WithAdminToken(apitest.AdminToken)).CreateRound(ctx, "round1", round)
+		_, createdErr := srv.Client.Total(ctx, "round1")
+		_, refusedErr := srv.Client.Total(ctx, "round2")
+
+		// Assert
+		assert.NoError(t, adminErr)
+		assert.ErrorIs(t, missingErr, client.ErrUnauthorized)
+		assert.ErrorIs(t, wrongErr, client.ErrUnauthorized)
+		assert.ErrorIs(t, disabledErr, client.ErrForbidden)
+		assert.NoError(t, createdErr)
+		assert.ErrorIs(t, refusedErr, client.ErrNotFound)
+	})
+
+	t.Run("Should return ErrForbidden when the IP range is blocked", func(t *testing.T) {
+		// Arrange
+		srv := apitest.Start(t, apitest.WithBlockedIPRanges("127.0.0."))
+
+		// Act
+		err := client.New(srv.URL).Vote(context.Background(), "round1", "alice")
+
+		// Assert
+		assert.ErrorIs(t, err, client.ErrForbidden)
+		var apiErr *client.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "Access from your IP range is blocked", apiErr.Message)
+	})
+
+	for _, backend := range []apitest.Backend{apitest.BackendMemory, apitest.BackendMiniredis} {
+		t.Run("Should not count twice a vote retried after the API registered it with "+string(backend), func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			srv := apitest.Start(t, apitest.WithBackend(backend))
+			var attempts atomic.Int32
+			c := client.New(srv.URL, fastRetry, client.WithHTTPClient(&http.Client{
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					resp, err := http.DefaultTransport.RoundTrip(r)
+					if r.Method == http.MethodPost && attempts.Add(1) == 1 && err == nil {
+						// the API registered the vote, but the response is lost
+						resp.Body.Close()
+						return nil, errors.New("connection reset by peer")
+					}
+					return resp, err
+				}),
+			}))
+
+			// Act
+			retriedErr := c.Vote(ctx, "round1", "alice")
+			nextErr := c.Vote(ctx, "round1", "alice")
+			total, totalErr := c.Total(ctx, "round1")
+
+			// Assert
+			require.NoError(t, errors.Join(retriedErr, nextErr, totalErr))
+			assert.EqualValues(t, 3, attempts.Load())
+			assert.Equal(t, 2, total)
+		})
+	}
+
+	t.Run("Should return ErrServer when the repository fails", func(t *testing.T) {
+		// Arrange
+		srv := apitest.Start(t, apitest.WithBackend(apitest.BackendMiniredis))
+		srv.Redis.SetError("LOADING Redis is loading the dataset in memory")
+
+		// Act
+		_, err := client.New(srv.URL, fastRetry).Total(context.Background(), "round1")
+
+		// Assert
+		assert.ErrorIs(t, err, client.ErrServer)
+	})
+}
+
+func TestClient(t *testing.T) {
+	t.Run("Should retry a rate limited vote after the Retry-After", func(t *testing.T) {
+		// Arrange
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+		start := time.Now()
+
+		// Act
+		err := client.New(server.URL, fastRetry).Vote(context.Background(), "round1", "alice")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("Should not wait for a Retry-After beyond the deadline", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"type": "about:blank", "title": "Too Many Requests", "status": 429, "detail": "Maximum 60 requests per 1 minute allowed", "code": "rate_limited"}`))
+		}))
+		defer server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// Act
+		err := client.New(server.URL, fastRetry).Vote(ctx, "round1", "alice")
+
+		// Assert
+		assert.ErrorIs(t, err, client.ErrRateLimited)
+		var apiErr *client.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, time.Minute, apiErr.RetryAfter)
+		assert.Equal(t, "rate_limited", apiErr.Code)
+		assert.Equal(t, "Maximum 60 requests per 1 minute allowed", apiErr.Message)
+	})
+
+	t.Run("Should retry a vote with the same idempotency key after a lost response or a 500", func(t *testing.T) {
+		// Arrange
+		var calls atomic.Int32
+		var m sync.Mutex
+		var keys []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.Lock()
+			keys = append(keys, r.Header.Get(client.IdempotencyKeyHeader))
+			m.Unlock()
+
+			switch calls.Add(1) {
+			case 1:
+				// the vote reaches the API, but the connection is closed before the response
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+			case 2:
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				w.WriteHeader(http.StatusCreated)
+			}
+		}))
+		defer server.Close()
+		c := client.New(server.URL, fastRetry)
+
+		// Act
+		retriedErr := c.Vote(context.Background(), "round1", "alice")
+		nextErr := c.Vote(context.Background(), "round1", "alice")
+
+		// Assert
+		assert.NoError(t, retriedErr)
+		assert.NoError(t, nextErr)
+		require.Len(t, keys, 4)
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, []string{keys[0], keys[0], keys[0]}, keys[:3])
+		assert.NotEqual(t, keys[0], keys[3])
+	})
+
+	t.Run("Should retry a vote while the API registers it with the same key, and a query that failed", func(t *testing.T) {
+		// Arrange
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1)%2 == 1 {
+				status := http.StatusConflict
+				if r.Method == http.MethodGet {
+					status = http.StatusInternalServerError
+				}
+				w.WriteHeader(status)
+				return
+			}
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`{"total": 1}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+		c := client.New(server.URL, fastRetry)
+
+		// Act
+		voteErr := c.Vote(context.Background(), "round1", "alice")
+		total, queryErr := c.Total(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, voteErr)
+		assert.NoError(t, queryErr)
+		assert.Equal(t, 1, total)
+		assert.EqualValues(t, 4, calls.Load())
+	})
+
+	t.Run("Should retry a vote that could not reach the API", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+		var attempts atomic.Int32
+		c := client.New(server.URL, fastRetry, client.WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				attempts.Add(1)
+				return http.DefaultTransport.RoundTrip(r)
+			}),
+		}))
+
+		// Act
+		err := c.Vote(context.Background(), "round1", "alice")
+
+		// Assert
+		assert.Error(t, err)
+		assert.EqualValues(t, 3, attempts.Load())
+	})
+
+	t.Run("Should return ErrBadRequest with the problem of the API", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"type": "about:blank", "title": "Bad Request", "status": 400, "detail": "participant_id is required", "code": "validation_error"}`))
+		}))
+		defer server.Close()
+
+		// Act
+		err := client.New(server.URL, fastRetry).Vote(context.Background(), "round1", "alice")
+
+		// Assert
+		assert.ErrorIs(t, err, client.ErrBadRequest)
+		assert.EqualError(t, err, "bbb api: status 400 (validation_error): participant_id is required")
+	})
+
+	t.Run("Should return the errors of the failed votes of a batch in order", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/command/blocked" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		// Act
+		err := client.New(server.URL, client.WithBatchConcurrency(2)).VoteBatch(context.Background(), []client.Vote{
+			{RoundID: "round1", ParticipantID: "alice"},
+			{RoundID: "blocked", ParticipantID: "bob"},
+			{RoundID: "round1", ParticipantID: "carol"},
+		})
+
+		// Assert
+		var batchErr *client.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.ErrorIs(t, err, client.ErrForbidden)
+		assert.NoError(t, batchErr.Errors[0])
+		assert.ErrorIs(t, batchErr.Errors[1], client.ErrForbidden)
+		assert.NoError(t, batchErr.Errors[2])
+	})
+
+	t.Run("Should use the URLs of the standalone APIs", func(t *testing.T) {
+		// Arrange
+		var paths []string
+		var m sync.Mutex
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.Lock()
+			paths = append(paths, r.URL.Path)
+			m.Unlock()
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			w.Write([]byte(`{"total": 1}`))
+		}))
+		defer server.Close()
+		c := client.New("", client.WithCommandURL(server.URL+"/"), client.WithQueryURL(server.URL))
+
+		// Act
+		voteErr := c.Vote(context.Background(), "round 1", "alice")
+		total, totalErr := c.Total(context.Background(), "round 1")
+
+		// Assert
+		assert.NoError(t, errors.Join(voteErr, totalErr))
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []string{"/round 1", "/round 1"}, paths)
+	})
+}
@@ -0,0 +1,60 @@
+package client
+
+// Vote is a vote of a participant in a round, used by VoteBatch
+type Vote struct {
+	RoundID       string
+	ParticipantID string
+}
+
+// Round is the metadata of a round, used by CreateRound
+type Round struct {
+	Name         string        `json:"name"`
+	Participants []Participant `json:"participants"`
+}
+
+// Participant is a participant of a round
+type Participant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Winner is the participant with the most votes in a round
+type Winner struct {
+	ParticipantID string  `json:"participant_id"`
+	Votes         int     `json:"votes"`
+	Percentage    float64 `json:"percentage"`
+
+	// Tie is true when other participants have the same number of votes,
+	// the winner is then the first one in alphabetical order
+	Tie bool `json:"tie"`
+}
+
+// RankingEntry is the position of a participant in the ranking of a round
+type RankingEntry struct {
+	Position      int     `json:"position"`
+	ParticipantID string  `json:"participant_id"`
+	Votes         int     `json:"votes"`
+	Percentage    float64 `json:"percentage"`
+}
+
+// UniqueVoters is the approximate number of distinct voters of a round, overall and of each participant
+type UniqueVoters struct {
+	Total        int            `json:"total"`
+	Participants map[string]int `json:"participants"`
+}
+
+// TimeSeriesPoint is the number of votes of an hour of a round
+type TimeSeriesPoint struct {
+	// Timestamp is the Unix timestamp of the beginning of the hour
+	Timestamp  int64 `json:"timestamp"`
+	Votes      int   `json:"votes"`
+	Cumulative int   `json:"cumulative"`
+}
+
+type voteRequest struct {
+	ParticipantID string `json:"participant_id"`
+}
+
+type totalResponse struct {
+	Total int `json:"total"`
+}
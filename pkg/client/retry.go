@@ -0,0 +1,62 @@
+package client
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures how many times a request is sent and how long to wait between the attempts.
+// The wait starts at InitialBackoff and doubles on each attempt, limited to MaxBackoff; a longer
+// Retry-After of the API is respected.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Jitter randomizes the wait by up to this fraction (0 to 1), so the retries of
+	// several clients do not hit the API at the same time
+	Jitter float64
+}
+
+// DefaultRetryPolicy is the retry policy of a new client
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Jitter:         0.2,
+}
+
+// backoff returns the time to wait before the given attempt (starting at 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			d = p.MaxBackoff
+			break
+		}
+	}
+
+	if p.Jitter > 0 {
+		// spread the wait uniformly between d*(1-jitter) and d*(1+jitter)
+		d = time.Duration(float64(d) * (1 + p.Jitter*(2*rand.Float64()-1)))
+	}
+	return d
+}
+
+// retryable returns true when the request can be sent again: on a failure without a response (status zero),
+// a 429 and on the 5xx of the API and of the proxies. The queries and the rounds, that are created again with
+// the same metadata, are idempotent. A vote is sent with the same idempotency key in every attempt, so the API
+// answers an attempt of a vote it already registered without registering it again, and answers 409 while
+// the vote is still being registered by another attempt.
+func retryable(method string, status int) bool {
+	switch status {
+	case 0, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return method == http.MethodPost
+	}
+	return false
+}
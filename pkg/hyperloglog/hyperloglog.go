@@ -0,0 +1,62 @@
+// Package hyperloglog estimates the number of distinct items of a set with a fixed memory,
+// the same sketch used by the PFADD and PFCOUNT commands of Redis.
+package hyperloglog
+
+import (
+	"hash/maphash"
+	"math"
+	"math/bits"
+)
+
+// precision is the number of bits of the hash that choose the register, the sketch has 2^precision registers.
+// As in Redis, the 16384 registers give a standard error of 0.81%.
+const precision = 14
+
+const registers = 1 << precision
+
+// seed hashes the items, the sketches are not persisted so a seed per process is enough
+var seed = maphash.MakeSeed()
+
+// Sketch estimates the number of distinct items added to it.
+// It uses one byte per register, 16 KB, and is not safe for concurrent use.
+type Sketch struct {
+	registers []uint8
+}
+
+// New creates an empty sketch
+func New() *Sketch {
+	return &Sketch{registers: make([]uint8, registers)}
+}
+
+// Add adds the item to the sketch, adding the same item again does not change the count
+func (s *Sketch) Add(item string) {
+	h := maphash.String(seed, item)
+
+	// the first bits choose the register, the register keeps the longest run of zeros seen in the other bits
+	index := h >> (64 - precision)
+	rank := uint8(bits.LeadingZeros64(h<<precision|1<<(precision-1))) + 1
+	if rank > s.registers[index] {
+		s.registers[index] = rank
+	}
+}
+
+// Count returns the estimated number of distinct items added to the sketch
+func (s *Sketch) Count() int {
+	sum, zeros := 0.0, 0
+	for _, r := range s.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	const m = float64(registers)
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+
+	// the raw estimate is biased for the small sets, counting the empty registers is more precise
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int(math.Round(estimate))
+}
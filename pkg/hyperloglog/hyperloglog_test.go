@@ -0,0 +1,52 @@
+package hyperloglog
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSketch(t *testing.T) {
+	t.Run("Should count zero for an empty sketch", func(t *testing.T) {
+		// Arrange
+		s := New()
+
+		// Act
+		count := s.Count()
+
+		// Assert
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("Should not count the same item twice", func(t *testing.T) {
+		// Arrange
+		s := New()
+
+		// Act
+		for range 100 {
+			s.Add("voter-1")
+			s.Add("voter-2")
+			s.Add("voter-3")
+		}
+
+		// Assert
+		assert.Equal(t, 3, s.Count())
+	})
+
+	for _, distinct := range []int{1000, 100000, 1000000} {
+		t.Run("Should estimate "+strconv.Itoa(distinct)+" distinct items within 3%", func(t *testing.T) {
+			// Arrange
+			s := New()
+
+			// Act
+			for i := range distinct {
+				s.Add("voter-" + strconv.Itoa(i))
+				s.Add("voter-" + strconv.Itoa(i/2))
+			}
+
+			// Assert
+			assert.InEpsilon(t, distinct, s.Count(), 0.03)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package localsql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+)
+
+type LocalSqlDeadLetterRepository struct {
+	db []entity.DeadLetter
+	m  sync.Mutex
+}
+
+func (lr *LocalSqlDeadLetterRepository) Push(ctx context.Context, letter entity.DeadLetter) error {
+	lr.m.Lock()
+	defer lr.m.Unlock()
+
+	lr.db = append(lr.db, letter)
+	return nil
+}
+
+func (lr *LocalSqlDeadLetterRepository) Pop(ctx context.Context) (entity.DeadLetter, bool, error) {
+	lr.m.Lock()
+	defer lr.m.Unlock()
+
+	if len(lr.db) == 0 {
+		return entity.DeadLetter{}, false, nil
+	}
+
+	letter := lr.db[0]
+	lr.db = lr.db[1:]
+	return letter, true, nil
+}
+
+func NewLocalSqlDeadLetterRepository() repository.DeadLetterRepository {
+	return &LocalSqlDeadLetterRepository{}
+}
@@ -0,0 +1,63 @@
+package localsql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sergiodii/bbb/internal/domain/repository"
+)
+
+type idempotencyEntry struct {
+	state     repository.IdempotencyState
+	expiresAt time.Time
+}
+
+// LocalSqlIdempotencyRepository keeps the idempotency keys in memory.
+// The expired keys are removed by the reservations, at most once per TTL.
+type LocalSqlIdempotencyRepository struct {
+	db        map[string]idempotencyEntry
+	nextSweep time.Time
+	m         sync.Mutex
+}
+
+func (lr *LocalSqlIdempotencyRepository) Reserve(ctx context.Context, key string, ttl time.Duration) (repository.IdempotencyState, error) {
+	lr.m.Lock()
+	defer lr.m.Unlock()
+
+	now := time.Now()
+	if now.After(lr.nextSweep) {
+		for k, e := range lr.db {
+			if !now.Before(e.expiresAt) {
+				delete(lr.db, k)
+			}
+		}
+		lr.nextSweep = now.Add(ttl)
+	}
+
+	if e, ok := lr.db[key]; ok && now.Before(e.expiresAt) {
+		return e.state, nil
+	}
+	lr.db[key] = idempotencyEntry{state: repository.IdempotencyPending, expiresAt: now.Add(ttl)}
+	return repository.IdempotencyReserved, nil
+}
+
+func (lr *LocalSqlIdempotencyRepository) Complete(ctx context.Context, key string, ttl time.Duration) error {
+	lr.m.Lock()
+	defer lr.m.Unlock()
+
+	lr.db[key] = idempotencyEntry{state: repository.IdempotencyDone, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (lr *LocalSqlIdempotencyRepository) Release(ctx context.Context, key string) error {
+	lr.m.Lock()
+	defer lr.m.Unlock()
+
+	delete(lr.db, key)
+	return nil
+}
+
+func NewLocalSqlIdempotencyRepository() repository.IdempotencyRepository {
+	return &LocalSqlIdempotencyRepository{db: map[string]idempotencyEntry{}}
+}
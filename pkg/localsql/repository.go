@@ -3,26 +3,51 @@ package localsql
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 
 	"github.com/sergiodii/bbb/internal/domain/entity"
 	"github.com/sergiodii/bbb/internal/domain/repository"
 )
 
-var _LocalSqlRoundRepository *LocalSqlRoundRepository
-var __LocalSqlRoundRepositoryOnce sync.Once
-
 type LocalSqlRoundRepository struct {
-	db []entity.Vote
+	db     []entity.Vote
+	m      sync.RWMutex
+	logger *slog.Logger
+
+	// rounds are the known rounds, created or with votes
+	rounds map[string]entity.Round
+}
+
+// CreateRound stores the metadata of the round, the round is then known without votes
+func (lr *LocalSqlRoundRepository) CreateRound(ctx context.Context, round entity.Round) error {
+	lr.m.Lock()
+	defer lr.m.Unlock()
+
+	lr.rounds[round.ID] = round
+	return nil
 }
 
 func (lr *LocalSqlRoundRepository) VoteRegister(ctx context.Context, vote entity.Vote) error {
-	fmt.Println("Vote registered in Local SQL DB:", vote)
+	lr.logger.DebugContext(ctx, "vote registered in local sql db", "round_id", vote.RoundID, "participant_id", vote.ParticipantID)
+	lr.m.Lock()
+	defer lr.m.Unlock()
+
 	lr.db = append(lr.db, vote)
+	if _, ok := lr.rounds[vote.RoundID]; !ok {
+		lr.rounds[vote.RoundID] = entity.Round{ID: vote.RoundID}
+	}
 	return nil
 }
 
 func (lr *LocalSqlRoundRepository) GetTotalVotes(ctx context.Context, roundID string) (int, error) {
+	lr.m.RLock()
+	defer lr.m.RUnlock()
+
+	if _, ok := lr.rounds[roundID]; !ok {
+		return 0, repository.ErrRoundNotFound
+	}
+
 	// Implement the logic to get the total votes for a round from the local SQL database
 	total := 0
 	for _, vote := range lr.db {
@@ -35,6 +60,13 @@ func (lr *LocalSqlRoundRepository) GetTotalVotes(ctx context.Context, roundID st
 }
 
 func (lr *LocalSqlRoundRepository) GetTotalForParticipant(ctx context.Context, roundID string) (map[string]int, error) {
+	lr.m.RLock()
+	defer lr.m.RUnlock()
+
+	if _, ok := lr.rounds[roundID]; !ok {
+		return map[string]int{}, repository.ErrRoundNotFound
+	}
+
 	// Implement the logic to get the total votes for each participant in a round from the local SQL database
 	total := map[string]int{}
 	for _, vote := range lr.db {
@@ -47,23 +79,66 @@ func (lr *LocalSqlRoundRepository) GetTotalForParticipant(ctx context.Context, r
 }
 
 func (lr *LocalSqlRoundRepository) GetTotalForHour(ctx context.Context, roundID string) (map[string]int, error) {
+	lr.m.RLock()
+	defer lr.m.RUnlock()
+
+	if _, ok := lr.rounds[roundID]; !ok {
+		return map[string]int{}, repository.ErrRoundNotFound
+	}
+
 	// Implement the logic to get the total votes for each hour in a round from the local SQL database
 	total := make(map[string]int)
 	for _, vote := range lr.db {
 		if vote.RoundID == roundID {
-			total[fmt.Sprintf("%d", vote.Timestamp)]++
+			// the hour is the number of hours since the epoch, the same format used by the Redis repository
+			total[fmt.Sprintf("%d", vote.Timestamp/3600)]++
 		}
 	}
 
 	return total, nil
 }
 
-func NewLocalSqlRoundRepository() repository.RoundRepository {
-	__LocalSqlRoundRepositoryOnce.Do(func() {
-		_LocalSqlRoundRepository = &LocalSqlRoundRepository{
-			db: []entity.Vote{},
+// GetUniqueVoters returns the number of unique voters of the round and of each participant.
+// The local database keeps every vote, so the counts are exact.
+func (lr *LocalSqlRoundRepository) GetUniqueVoters(ctx context.Context, roundID string) (entity.UniqueVoters, error) {
+	lr.m.RLock()
+	defer lr.m.RUnlock()
+
+	if _, ok := lr.rounds[roundID]; !ok {
+		return entity.UniqueVoters{Participants: map[string]int{}}, repository.ErrRoundNotFound
+	}
+
+	type participantVoter struct{ participant, voter string }
+	voters := map[string]bool{}
+	participantVoters := map[participantVoter]bool{}
+	result := entity.UniqueVoters{Participants: map[string]int{}}
+	for _, vote := range lr.db {
+		voter := vote.Voter()
+		if vote.RoundID != roundID || voter == "" {
+			continue
+		}
+		if !voters[voter] {
+			voters[voter] = true
+			result.Total++
 		}
-	})
+		if key := (participantVoter{vote.ParticipantID, voter}); !participantVoters[key] {
+			participantVoters[key] = true
+			result.Participants[vote.ParticipantID]++
+		}
+	}
+	return result, nil
+}
 
-	return _LocalSqlRoundRepository
+// Ping checks the connection with the local SQL database.
+// The local database lives in memory, so it is always reachable.
+func (lr *LocalSqlRoundRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func NewLocalSqlRoundRepository(logger *slog.Logger) repository.RoundRepository {
+	return &LocalSqlRoundRepository{
+		db:     []entity.Vote{},
+		logger: logger,
+		rounds: map[string]entity.Round{},
+	}
 }
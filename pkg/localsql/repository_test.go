@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	"github.com/sergiodii/bbb/pkg/logger"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/assert"
@@ -17,7 +19,7 @@ func TestVoteRegister_Success(t *testing.T) {
 	}
 	defer s.Close()
 
-	repo := NewLocalSqlRoundRepository()
+	repo := NewLocalSqlRoundRepository(logger.Discard())
 
 	err = repo.VoteRegister(context.Background(), entity.Vote{
 		RoundID:       "round1",
@@ -34,3 +36,57 @@ func TestVoteRegister_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, map[string]int{"participant1": 1}, m)
 }
+
+func TestUnknownRound(t *testing.T) {
+	repo := NewLocalSqlRoundRepository(logger.Discard())
+
+	_, err := repo.GetTotalVotes(context.Background(), "round1")
+	assert.ErrorIs(t, err, repository.ErrRoundNotFound)
+
+	err = repo.(repository.RoundRegistry).CreateRound(context.Background(), entity.Round{ID: "round1"})
+	assert.NoError(t, err)
+
+	total, err := repo.GetTotalVotes(context.Background(), "round1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	m, err := repo.GetTotalForParticipant(context.Background(), "round1")
+	assert.NoError(t, err)
+	assert.Empty(t, m)
+}
+
+func TestUniqueVoters(t *testing.T) {
+	repo := NewLocalSqlRoundRepository(logger.Discard())
+	for _, vote := range []entity.Vote{
+		{RoundID: "round1", ParticipantID: "alice", VoterID: "v1"},
+		{RoundID: "round1", ParticipantID: "alice", VoterID: "v1"},
+		{RoundID: "round1", ParticipantID: "bob", VoterID: "v1"},
+		{RoundID: "round1", ParticipantID: "bob", IP: "10.0.0.1"},
+		{RoundID: "round1", ParticipantID: "bob"},
+		{RoundID: "round2", ParticipantID: "bob", VoterID: "v2"},
+	} {
+		assert.NoError(t, repo.VoteRegister(context.Background(), vote))
+	}
+
+	voters, err := repo.(repository.VoterCounter).GetUniqueVoters(context.Background(), "round1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.UniqueVoters{Total: 2, Participants: map[string]int{"alice": 1, "bob": 2}}, voters)
+
+	_, err = repo.(repository.VoterCounter).GetUniqueVoters(context.Background(), "unknown")
+	assert.ErrorIs(t, err, repository.ErrRoundNotFound)
+}
+
+func TestNewLocalSqlRoundRepository(t *testing.T) {
+	t.Run("Should create independent repositories", func(t *testing.T) {
+		// Arrange
+		first := NewLocalSqlRoundRepository(logger.Discard())
+		second := NewLocalSqlRoundRepository(logger.Discard())
+
+		// Act
+		first.VoteRegister(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "participant1"})
+		total, _ := second.GetTotalVotes(context.Background(), "round1")
+
+		// Assert
+		assert.Equal(t, 0, total)
+	})
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Options configures the logger created by New
+type Options struct {
+	// Level is the minimum level logged: debug, info, warn or error. Default is info.
+	Level string
+
+	// Output is where the JSON records are written. Default is the stdout.
+	Output io.Writer
+
+	// BufferSize is the number of records kept in memory while they are written in background.
+	// When the buffer is full, new records are dropped instead of blocking the caller.
+	// Zero writes synchronously.
+	BufferSize int
+
+	// OnDrop is called for each record dropped because the buffer was full, e.g. to count them in a metric.
+	OnDrop func()
+
+	// Sampling limits the records below the warn level with the same message.
+	// Nil disables the sampling.
+	Sampling *Sampling
+}
+
+// Sampling logs the First records with the same message in each Tick,
+// and after that only one of every Thereafter records
+type Sampling struct {
+	First      int
+	Thereafter int
+	Tick       time.Duration
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// ParseLevel converts a level name to slog.Level, unknown names are converted to info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// New creates a JSON logger.
+// The records carry the request id stored in the context (see WithRequestID),
+// so the *Context methods of slog.Logger must be used on the request paths.
+// The returned io.Closer flushes the records still in the buffer.
+func New(opts Options) (*slog.Logger, io.Closer) {
+	output := opts.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	var closer io.Closer = nopCloser{}
+	if opts.BufferSize > 0 {
+		w := newAsyncWriter(output, opts.BufferSize, opts.OnDrop)
+		output, closer = w, w
+	}
+
+	var handler slog.Handler = slog.NewJSONHandler(output, &slog.HandlerOptions{
+		Level: ParseLevel(opts.Level),
+	})
+	handler = &contextHandler{handler}
+
+	if opts.Sampling != nil {
+		handler = newSamplingHandler(handler, *opts.Sampling)
+	}
+
+	return slog.New(handler), closer
+}
+
+// NewDefault creates the logger of the application with the given level.
+// The records are written in background and the info records with the same message are sampled,
+// so logging never blocks the vote path. onDrop is called for each record dropped, see Options.OnDrop.
+func NewDefault(level string, onDrop func()) (*slog.Logger, io.Closer) {
+	return New(Options{
+		Level:      level,
+		BufferSize: 4096,
+		OnDrop:     onDrop,
+		Sampling: &Sampling{
+			First:      100,
+			Thereafter: 100,
+			Tick:       time.Second,
+		},
+	})
+}
+
+// Discard returns a logger that drops every record, useful in tests
+func Discard() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
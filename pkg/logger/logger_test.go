@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingWriter blocks the writes until it is released, signaling each one
+type blockingWriter struct {
+	written chan struct{}
+	release chan struct{}
+	records int
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	select {
+	case w.written <- struct{}{}:
+	default:
+	}
+	<-w.release
+	w.records++
+	return len(p), nil
+}
+
+func decodeLines(t *testing.T, b *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("Should write JSON records with the request id of the context", func(t *testing.T) {
+		// Arrange
+		b := &bytes.Buffer{}
+		l, _ := New(Options{Output: b})
+
+		// Act
+		l.InfoContext(WithRequestID(context.Background(), "abc"), "vote registered", "round_id", "round1")
+		l.Debug("ignored")
+
+		// Assert
+		records := decodeLines(t, b)
+		assert.Len(t, records, 1)
+		assert.Equal(t, "vote registered", records[0]["msg"])
+		assert.Equal(t, "abc", records[0][RequestIDKey])
+		assert.Equal(t, "round1", records[0]["round_id"])
+	})
+
+	t.Run("Should sample records with the same message", func(t *testing.T) {
+		// Arrange
+		b := &bytes.Buffer{}
+		l, _ := New(Options{Output: b, Sampling: &Sampling{First: 2, Thereafter: 3, Tick: time.Minute}})
+
+		// Act
+		for i := 0; i < 8; i++ {
+			l.Info("hot path")
+		}
+		l.Warn("hot path")
+
+		// Assert
+		// 2 first records, the 5th and the 8th, and the warn record that is never sampled
+		assert.Len(t, decodeLines(t, b), 5)
+	})
+
+	t.Run("Should flush the buffered records on close", func(t *testing.T) {
+		// Arrange
+		b := &bytes.Buffer{}
+		l, closer := New(Options{Output: b, BufferSize: 10})
+
+		// Act
+		for i := 0; i < 5; i++ {
+			l.Info("buffered")
+		}
+		closer.Close()
+
+		// Assert
+		assert.Len(t, decodeLines(t, b), 5)
+	})
+
+	t.Run("Should drop the records when the buffer is full", func(t *testing.T) {
+		// Arrange
+		w := &blockingWriter{written: make(chan struct{}, 1), release: make(chan struct{})}
+		dropped := 0
+		l, closer := New(Options{Output: w, BufferSize: 1, OnDrop: func() { dropped++ }})
+
+		// Act
+		l.Info("written")
+		<-w.written
+		l.Info("buffered")
+		l.Info("dropped")
+		l.Info("dropped")
+		close(w.release)
+		closer.Close()
+
+		// Assert
+		assert.Equal(t, 2, dropped)
+		assert.Equal(t, 2, w.records)
+	})
+
+	t.Run("Should propagate the request id through the gin middleware", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		b := &bytes.Buffer{}
+		l, _ := New(Options{Output: b})
+
+		var fromHandler string
+		r := gin.New()
+		r.Use(GinMiddleware(l))
+		r.GET("/:round_id", func(c *gin.Context) {
+			fromHandler = RequestID(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/round1", nil)
+		req.Header.Set(RequestIDHeader, "from-client")
+		w := httptest.NewRecorder()
+
+		// Act
+		r.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, "from-client", fromHandler)
+		assert.Equal(t, "from-client", w.Header().Get(RequestIDHeader))
+		records := decodeLines(t, b)
+		assert.Len(t, records, 1)
+		assert.Equal(t, "/:round_id", records[0]["route"])
+		assert.Equal(t, "from-client", records[0][RequestIDKey])
+	})
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type samplingCounters struct {
+	m        sync.Mutex
+	resetAt  time.Time
+	counters map[string]*atomic.Int64
+}
+
+func (s *samplingCounters) inc(msg string, tick time.Duration) int64 {
+	s.m.Lock()
+	now := time.Now()
+	if now.After(s.resetAt) {
+		s.counters = map[string]*atomic.Int64{}
+		s.resetAt = now.Add(tick)
+	}
+	c, ok := s.counters[msg]
+	if !ok {
+		c = &atomic.Int64{}
+		s.counters[msg] = c
+	}
+	s.m.Unlock()
+
+	return c.Add(1)
+}
+
+// samplingHandler drops part of the records below the warn level that have the same message,
+// keeping the volume of logs under control on the high traffic paths
+type samplingHandler struct {
+	slog.Handler
+	sampling Sampling
+	counters *samplingCounters
+}
+
+func newSamplingHandler(h slog.Handler, s Sampling) *samplingHandler {
+	return &samplingHandler{
+		Handler:  h,
+		sampling: s,
+		counters: &samplingCounters{counters: map[string]*atomic.Int64{}},
+	}
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		return h.Handler.Handle(ctx, r)
+	}
+
+	n := h.counters.inc(r.Message, h.sampling.Tick)
+	if n <= int64(h.sampling.First) {
+		return h.Handler.Handle(ctx, r)
+	}
+	if h.sampling.Thereafter > 0 && (n-int64(h.sampling.First))%int64(h.sampling.Thereafter) == 0 {
+		return h.Handler.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{h.Handler.WithAttrs(attrs), h.sampling, h.counters}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{h.Handler.WithGroup(name), h.sampling, h.counters}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"io"
+	"sync"
+)
+
+// asyncWriter writes in background, so the caller never waits for the console or the disk.
+// When the buffer is full the record is dropped and onDrop is called.
+type asyncWriter struct {
+	w      io.Writer
+	ch     chan []byte
+	done   chan struct{}
+	once   sync.Once
+	m      sync.RWMutex
+	closed bool
+	onDrop func()
+}
+
+func newAsyncWriter(w io.Writer, size int, onDrop func()) *asyncWriter {
+	aw := &asyncWriter{
+		w:      w,
+		ch:     make(chan []byte, size),
+		done:   make(chan struct{}),
+		onDrop: onDrop,
+	}
+
+	go func() {
+		defer close(aw.done)
+		for p := range aw.ch {
+			aw.w.Write(p)
+		}
+	}()
+
+	return aw
+}
+
+func (aw *asyncWriter) Write(p []byte) (int, error) {
+	aw.m.RLock()
+	defer aw.m.RUnlock()
+	if aw.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	// the handler reuses its buffer, so the record must be copied
+	b := make([]byte, len(p))
+	copy(b, p)
+
+	select {
+	case aw.ch <- b:
+	default:
+		if aw.onDrop != nil {
+			aw.onDrop()
+		}
+	}
+	return len(p), nil
+}
+
+// Close writes the records left in the buffer and stops the background goroutine
+func (aw *asyncWriter) Close() error {
+	aw.once.Do(func() {
+		aw.m.Lock()
+		aw.closed = true
+		close(aw.ch)
+		aw.m.Unlock()
+		<-aw.done
+	})
+	return nil
+}
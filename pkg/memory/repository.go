@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	"github.com/sergiodii/bbb/pkg/hyperloglog"
+)
+
+// roundCounters holds the metadata and the counters of a round
+type roundCounters struct {
+	round        entity.Round
+	total        int
+	participants map[string]int
+	hours        map[string]int
+
+	// voters are the sketches of the unique voters of the round and of each participant, created on the first voter
+	voters            *hyperloglog.Sketch
+	participantVoters map[string]*hyperloglog.Sketch
+}
+
+// MemoryRoundRepository keeps the vote counters in memory.
+// It is safe for concurrent use and useful as a cache, for tests and for local development.
+// The counters are lost when the process stops.
+type MemoryRoundRepository struct {
+	m      sync.RWMutex
+	rounds map[string]*roundCounters
+}
+
+// counters returns the counters of the round, creating them when the round is not known yet.
+// The lock must be held.
+func (r *MemoryRoundRepository) counters(roundID string) *roundCounters {
+	round, ok := r.rounds[roundID]
+	if !ok {
+		round = &roundCounters{
+			round:             entity.Round{ID: roundID},
+			participants:      map[string]int{},
+			hours:             map[string]int{},
+			participantVoters: map[string]*hyperloglog.Sketch{},
+		}
+		r.rounds[roundID] = round
+	}
+	return round
+}
+
+// CreateRound stores the metadata of the round, the round is then known without votes
+func (r *MemoryRoundRepository) CreateRound(ctx context.Context, round entity.Round) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.counters(round.ID).round = round
+	return nil
+}
+
+func (r *MemoryRoundRepository) VoteRegister(ctx context.Context, vote entity.Vote) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	round := r.counters(vote.RoundID)
+
+	// the hour is the number of hours since the epoch, the same format used by the Redis repository
+	round.total++
+	round.participants[vote.ParticipantID]++
+	round.hours[fmt.Sprintf("%d", vote.Timestamp/3600)]++
+
+	if voter := vote.Voter(); voter != "" {
+		if round.voters == nil {
+			round.voters = hyperloglog.New()
+		}
+		round.voters.Add(voter)
+
+		sketch, ok := round.participantVoters[vote.ParticipantID]
+		if !ok {
+			sketch = hyperloglog.New()
+			round.participantVoters[vote.ParticipantID] = sketch
+		}
+		sketch.Add(voter)
+	}
+	return nil
+}
+
+func (r *MemoryRoundRepository) GetTotalVotes(ctx context.Context, roundID string) (int, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	if round, ok := r.rounds[roundID]; ok {
+		return round.total, nil
+	}
+	return 0, repository.ErrRoundNotFound
+}
+
+func (r *MemoryRoundRepository) GetTotalForParticipant(ctx context.Context, roundID string) (map[string]int, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	if round, ok := r.rounds[roundID]; ok {
+		return maps.Clone(round.participants), nil
+	}
+	return map[string]int{}, repository.ErrRoundNotFound
+}
+
+func (r *MemoryRoundRepository) GetTotalForHour(ctx context.Context, roundID string) (map[string]int, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	if round, ok := r.rounds[roundID]; ok {
+		return maps.Clone(round.hours), nil
+	}
+	return map[string]int{}, repository.ErrRoundNotFound
+}
+
+// GetUniqueVoters returns the approximate number of unique voters of the round and of each participant,
+// estimated with HyperLogLog sketches like the Redis repository
+func (r *MemoryRoundRepository) GetUniqueVoters(ctx context.Context, roundID string) (entity.UniqueVoters, error) {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	round, ok := r.rounds[roundID]
+	if !ok {
+		return entity.UniqueVoters{Participants: map[string]int{}}, repository.ErrRoundNotFound
+	}
+
+	voters := entity.UniqueVoters{Participants: make(map[string]int, len(round.participantVoters))}
+	if round.voters != nil {
+		voters.Total = round.voters.Count()
+	}
+	for participant, sketch := range round.participantVoters {
+		voters.Participants[participant] = sketch.Count()
+	}
+	return voters, nil
+}
+
+// Ping checks the repository, the memory is always reachable
+func (r *MemoryRoundRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func NewMemoryRoundRepository() repository.RoundRepository {
+	return &MemoryRoundRepository{rounds: map[string]*roundCounters{}}
+}
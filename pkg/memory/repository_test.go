@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRoundRepository(t *testing.T) {
+	t.Run("Should count the votes of a round", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRoundRepository()
+		votes := []entity.Vote{
+			{RoundID: "round1", ParticipantID: "alice", Timestamp: 3600},
+			{RoundID: "round1", ParticipantID: "alice", Timestamp: 7300},
+			{RoundID: "round1", ParticipantID: "bob", Timestamp: 7200},
+			{RoundID: "round2", ParticipantID: "bob", Timestamp: 7200},
+		}
+
+		// Act
+		for _, vote := range votes {
+			assert.NoError(t, repo.VoteRegister(context.Background(), vote))
+		}
+		total, totalErr := repo.GetTotalVotes(context.Background(), "round1")
+		participants, participantsErr := repo.GetTotalForParticipant(context.Background(), "round1")
+		hours, hoursErr := repo.GetTotalForHour(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, totalErr)
+		assert.NoError(t, participantsErr)
+		assert.NoError(t, hoursErr)
+		assert.Equal(t, 3, total)
+		assert.Equal(t, map[string]int{"alice": 2, "bob": 1}, participants)
+		assert.Equal(t, map[string]int{"1": 1, "2": 2}, hours)
+	})
+
+	t.Run("Should return empty results and ErrRoundNotFound for an unknown round", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRoundRepository()
+
+		// Act
+		total, totalErr := repo.GetTotalVotes(context.Background(), "unknown")
+		participants, participantsErr := repo.GetTotalForParticipant(context.Background(), "unknown")
+		_, hoursErr := repo.GetTotalForHour(context.Background(), "unknown")
+
+		// Assert
+		assert.Equal(t, 0, total)
+		assert.Empty(t, participants)
+		assert.ErrorIs(t, totalErr, repository.ErrRoundNotFound)
+		assert.ErrorIs(t, participantsErr, repository.ErrRoundNotFound)
+		assert.ErrorIs(t, hoursErr, repository.ErrRoundNotFound)
+	})
+
+	t.Run("Should know a created round without votes", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRoundRepository()
+
+		// Act
+		err := repo.(repository.RoundRegistry).CreateRound(context.Background(), entity.Round{ID: "round1", Nome: "Paredão 1"})
+		total, totalErr := repo.GetTotalVotes(context.Background(), "round1")
+		hours, hoursErr := repo.GetTotalForHour(context.Background(), "round1")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, totalErr)
+		assert.NoError(t, hoursErr)
+		assert.Equal(t, 0, total)
+		assert.Empty(t, hours)
+	})
+
+	t.Run("Should count the unique voters of a round and of each participant", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		repo := NewMemoryRoundRepository()
+		votes := []entity.Vote{
+			{RoundID: "round1", ParticipantID: "alice", VoterID: "v1", IP: "10.0.0.1"},
+			{RoundID: "round1", ParticipantID: "alice", VoterID: "v1", IP: "10.0.0.2"},
+			{RoundID: "round1", ParticipantID: "bob", VoterID: "v1"},
+			{RoundID: "round1", ParticipantID: "bob", IP: "10.0.0.1"},
+			{RoundID: "round1", ParticipantID: "bob"},
+		}
+		repo.(repository.RoundRegistry).CreateRound(ctx, entity.Round{ID: "empty"})
+
+		// Act
+		for _, vote := range votes {
+			assert.NoError(t, repo.VoteRegister(ctx, vote))
+		}
+		voters, err := repo.(repository.VoterCounter).GetUniqueVoters(ctx, "round1")
+		empty, emptyErr := repo.(repository.VoterCounter).GetUniqueVoters(ctx, "empty")
+		_, unknownErr := repo.(repository.VoterCounter).GetUniqueVoters(ctx, "unknown")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, entity.UniqueVoters{Total: 2, Participants: map[string]int{"alice": 1, "bob": 2}}, voters)
+		assert.NoError(t, emptyErr)
+		assert.Equal(t, entity.UniqueVoters{Participants: map[string]int{}}, empty)
+		assert.ErrorIs(t, unknownErr, repository.ErrRoundNotFound)
+	})
+
+	t.Run("Should register concurrent votes", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRoundRepository()
+		var wg sync.WaitGroup
+
+		// Act
+		for range 100 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				repo.VoteRegister(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "alice"})
+			}()
+		}
+		wg.Wait()
+		total, _ := repo.GetTotalVotes(context.Background(), "round1")
+
+		// Assert
+		assert.Equal(t, 100, total)
+	})
+}
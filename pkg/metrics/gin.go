@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GinMiddleware measures the latency of every request.
+// The route template (e.g. /query/:round_id) is used as label to keep the cardinality low.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		httpRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// The round labels of the votes not counted by round, see VoteMiddleware
+const (
+	noRound    = "none"
+	otherRound = "other"
+)
+
+// VoteMiddleware counts the votes (POST requests) accepted and rejected for each of the rounds.
+// It must be used on the routes that have the round_id parameter.
+// A vote is accepted for any round ID, so only the accepted votes of the rounds listed are counted by round,
+// the others with the round label "other". The rejected ones are counted with the round label "none".
+// Both keep the cardinality bounded, whatever the round IDs sent by the clients.
+func VoteMiddleware(rounds []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Request.Method != http.MethodPost {
+			return
+		}
+		if c.Writer.Status() != http.StatusCreated {
+			votesTotal.WithLabelValues(noRound, "rejected").Inc()
+			return
+		}
+
+		// the vote of an idempotency key already registered was counted by the first attempt
+		if c.Writer.Header().Get("Idempotent-Replayed") != "" {
+			return
+		}
+
+		round := c.Param("round_id")
+		if !slices.Contains(rounds, round) {
+			round = otherRound
+		}
+		votesTotal.WithLabelValues(round, "accepted").Inc()
+	}
+}
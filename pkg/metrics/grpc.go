@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCInterceptors measure the latency of every call, like GinMiddleware.
+// The full method (e.g. /bbb.vote.v1.VoteService/GetWinner) is used as label, the methods are the ones of the services.
+func GRPCInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeGRPC(info.FullMethod, start, err)
+		return resp, err
+	}
+
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeGRPC(info.FullMethod, start, err)
+		return err
+	}
+
+	return unary, stream
+}
+
+func observeGRPC(method string, start time.Time, err error) {
+	grpcRequestDuration.
+		WithLabelValues(method, status.Code(err).String()).
+		Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bbb"
+
+// Registry holds every collector exposed by the application.
+// A dedicated registry is used instead of the global one so tests can inspect it safely.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	httpRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the HTTP requests by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	grpcRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the gRPC calls by method, the streams are measured until they end.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	votesTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "votes_total",
+		Help:      "Votes received by round, labelled as accepted or rejected. The accepted votes of the rounds not listed in the configuration have the round other, the rejected votes have the round none.",
+	}, []string{"round_id", "result"})
+
+	pipeTaskDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "pipe",
+		Name:      "task_duration_seconds",
+		Help:      "Latency of the tasks executed by the pipes.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"execution_type", "task"})
+
+	pipeTaskErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pipe",
+		Name:      "task_errors_total",
+		Help:      "Tasks executed by the pipes that returned an error, ObjectNotFound is not counted.",
+	}, []string{"execution_type", "task"})
+
+	pipeDivergences = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pipe",
+		Name:      "divergences_total",
+		Help:      "Executions where the tasks of a pipe returned different results, e.g. repositories out of sync.",
+	}, []string{"execution_type"})
+
+	repositoryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of the repository operations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"repository", "operation", "result"})
+
+	logDroppedRecords = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "log",
+		Name:      "dropped_records_total",
+		Help:      "Log records dropped because the buffer of the logger was full.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// LogDropped counts a log record dropped by the logger, see logger.Options.OnDrop
+func LogDropped() {
+	logDroppedRecords.Inc()
+}
+
+// Handler returns the HTTP handler that exposes the metrics in the Prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
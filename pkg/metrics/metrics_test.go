@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+	"github.com/sergiodii/bbb/pkg/localsql"
+	"github.com/sergiodii/bbb/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Should count the accepted votes of the listed rounds and the others apart", func(t *testing.T) {
+		// Arrange
+		r := gin.New()
+		r.Use(GinMiddleware())
+		g := r.Group("/command")
+		g.Use(VoteMiddleware([]string{"metrics-round"}))
+		g.POST("/:round_id", func(c *gin.Context) {
+			if c.Query("fail") != "" {
+				c.Status(http.StatusInternalServerError)
+				return
+			}
+			c.Status(http.StatusCreated)
+		})
+		g.POST("/:round_id/replayed", func(c *gin.Context) {
+			c.Header("Idempotent-Replayed", "true")
+			c.Status(http.StatusCreated)
+		})
+		g.PUT("/:round_id", func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+
+		// Act
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/command/metrics-round", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/command/metrics-round", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/command/metrics-round?fail=1", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/command/unknown-round?fail=1", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/command/made-up-round-1", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/command/made-up-round-2", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/command/metrics-round", nil))
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/command/metrics-round/replayed", nil))
+
+		// Assert
+		assert.Equal(t, 2.0, testutil.ToFloat64(votesTotal.WithLabelValues("metrics-round", "accepted")))
+		assert.Equal(t, 2.0, testutil.ToFloat64(votesTotal.WithLabelValues(noRound, "rejected")))
+		assert.Equal(t, 2.0, testutil.ToFloat64(votesTotal.WithLabelValues(otherRound, "accepted")))
+		assert.Equal(t, 3, testutil.CollectAndCount(votesTotal))
+		assert.Equal(t, 4, testutil.CollectAndCount(httpRequestDuration))
+	})
+
+	t.Run("Should count pipe task errors ignoring ObjectNotFound", func(t *testing.T) {
+		// Arrange
+		p := pipe.Instrument(pipe.NewSequentiallyPipe[int](), pipe.SEQUENTIAL, NewPipeObserver())
+		p.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, pipe.ONF
+		})
+		p.Enqueue(func(ctx context.Context, i int) (int, error) {
+			return i, assert.AnError
+		})
+
+		// Act
+		_, err := p.Execute(context.Background(), 1)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, 0.0, testutil.ToFloat64(pipeTaskErrors.WithLabelValues("SEQUENTIAL", "0")))
+		assert.Equal(t, 1.0, testutil.ToFloat64(pipeTaskErrors.WithLabelValues("SEQUENTIAL", "1")))
+	})
+
+	t.Run("Should measure repository operations and expose them", func(t *testing.T) {
+		// Arrange
+		repo := InstrumentRepository("localsql", localsql.NewLocalSqlRoundRepository(logger.Discard()))
+		repo.VoteRegister(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "participant1"})
+
+		// Act
+		_, err := repo.GetTotalVotes(context.Background(), "round1")
+		_, notFoundErr := repo.GetTotalVotes(context.Background(), "unknown")
+		w := httptest.NewRecorder()
+		Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.ErrorIs(t, notFoundErr, repository.ErrRoundNotFound)
+		assert.True(t, strings.Contains(w.Body.String(),
+			`bbb_repository_operation_duration_seconds_count{operation="GetTotalVotes",repository="localsql",result="success"} 1`))
+		assert.True(t, strings.Contains(w.Body.String(),
+			`bbb_repository_operation_duration_seconds_count{operation="GetTotalVotes",repository="localsql",result="not_found"} 1`))
+	})
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+)
+
+type pipeObserver struct{}
+
+// ObserveTask measures the latency and counts the errors of a pipe task
+func (o *pipeObserver) ObserveTask(ctx context.Context, info pipe.TaskInfo) (context.Context, func(error)) {
+	start := time.Now()
+	labels := []string{info.ExecutionType.String(), strconv.Itoa(info.Index)}
+
+	return ctx, func(err error) {
+		pipeTaskDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+		// ObjectNotFound is part of the normal flow of the pipes, so it is not an error
+		if err != nil && !errors.Is(err, pipe.ONF) {
+			pipeTaskErrors.WithLabelValues(labels...).Inc()
+		}
+	}
+}
+
+// ObserveDivergence counts the executions where the repositories did not agree
+func (o *pipeObserver) ObserveDivergence(ctx context.Context, executionType pipe.ExecutionType, d pipe.Divergence) {
+	pipeDivergences.WithLabelValues(executionType.String()).Inc()
+}
+
+// NewPipeObserver creates a pipe.Observer that exposes the task metrics.
+// Use it with pipe.SetDefaultObserver or pipe.Instrument.
+func NewPipeObserver() pipe.Observer {
+	return &pipeObserver{}
+}
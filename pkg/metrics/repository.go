@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+)
+
+type instrumentedRepository struct {
+	name string
+	repo repository.RoundRepository
+}
+
+func (r *instrumentedRepository) observe(operation string, start time.Time, err error) {
+	result := "success"
+	switch {
+	case errors.Is(err, repository.ErrRoundNotFound):
+		// an unknown round is an answer of the repository, not a failure
+		result = "not_found"
+	case err != nil:
+		result = "error"
+	}
+	repositoryDuration.WithLabelValues(r.name, operation, result).Observe(time.Since(start).Seconds())
+}
+
+func (r *instrumentedRepository) VoteRegister(ctx context.Context, vote entity.Vote) (err error) {
+	defer func(start time.Time) { r.observe("VoteRegister", start, err) }(time.Now())
+	return r.repo.VoteRegister(ctx, vote)
+}
+
+func (r *instrumentedRepository) GetTotalVotes(ctx context.Context, roundID string) (total int, err error) {
+	defer func(start time.Time) { r.observe("GetTotalVotes", start, err) }(time.Now())
+	return r.repo.GetTotalVotes(ctx, roundID)
+}
+
+func (r *instrumentedRepository) GetTotalForParticipant(ctx context.Context, roundID string) (m map[string]int, err error) {
+	defer func(start time.Time) { r.observe("GetTotalForParticipant", start, err) }(time.Now())
+	return r.repo.GetTotalForParticipant(ctx, roundID)
+}
+
+func (r *instrumentedRepository) GetTotalForHour(ctx context.Context, roundID string) (m map[string]int, err error) {
+	defer func(start time.Time) { r.observe("GetTotalForHour", start, err) }(time.Now())
+	return r.repo.GetTotalForHour(ctx, roundID)
+}
+
+// CreateRound forwards the round to the decorated repository, when it stores the rounds
+func (r *instrumentedRepository) CreateRound(ctx context.Context, round entity.Round) (err error) {
+	registry, ok := r.repo.(repository.RoundRegistry)
+	if !ok {
+		return nil
+	}
+	defer func(start time.Time) { r.observe("CreateRound", start, err) }(time.Now())
+	return registry.CreateRound(ctx, round)
+}
+
+// GetUniqueVoters forwards the query to the decorated repository, when it counts the voters
+func (r *instrumentedRepository) GetUniqueVoters(ctx context.Context, roundID string) (voters entity.UniqueVoters, err error) {
+	counter, ok := r.repo.(repository.VoterCounter)
+	if !ok {
+		return voters, errors.ErrUnsupported
+	}
+	defer func(start time.Time) { r.observe("GetUniqueVoters", start, err) }(time.Now())
+	return counter.GetUniqueVoters(ctx, roundID)
+}
+
+// Ping forwards the health check to the decorated repository, when it supports it
+func (r *instrumentedRepository) Ping(ctx context.Context) error {
+	if hc, ok := r.repo.(repository.HealthChecker); ok {
+		return hc.Ping(ctx)
+	}
+	return nil
+}
+
+func (r *instrumentedRepository) Unwrap() repository.RoundRepository {
+	return r.repo
+}
+
+// InstrumentRepository decorates a repository to measure the latency of its operations.
+// The name is used as the repository label (e.g. redis, localsql).
+func InstrumentRepository(name string, repo repository.RoundRepository) repository.RoundRepository {
+	return &instrumentedRepository{
+		name: name,
+		repo: repo,
+	}
+}
@@ -0,0 +1,1188 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.29.3
+// source: vote/v1/vote.proto
+
+// The gRPC interface of the voting API, served by the grpc-api command.
+// The Go code in pkg/pb is generated with `make proto`.
+
+package votev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateVoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoundId       string                 `protobuf:"bytes,1,opt,name=round_id,json=roundId,proto3" json:"round_id,omitempty"`
+	ParticipantId string                 `protobuf:"bytes,2,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	// voter_id identifies the authenticated voter to count the unique voters, it is only accepted
+	// from a trusted proxy (trusted_proxies); the votes without it are counted by the IP of the client
+	VoterId       string `protobuf:"bytes,3,opt,name=voter_id,json=voterId,proto3" json:"voter_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVoteRequest) Reset() {
+	*x = CreateVoteRequest{}
+	mi := &file_vote_v1_vote_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVoteRequest) ProtoMessage() {}
+
+func (x *CreateVoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateVoteRequest) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateVoteRequest) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+func (x *CreateVoteRequest) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *CreateVoteRequest) GetVoterId() string {
+	if x != nil {
+		return x.VoterId
+	}
+	return ""
+}
+
+type CreateVoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVoteResponse) Reset() {
+	*x = CreateVoteResponse{}
+	mi := &file_vote_v1_vote_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVoteResponse) ProtoMessage() {}
+
+func (x *CreateVoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVoteResponse.ProtoReflect.Descriptor instead.
+func (*CreateVoteResponse) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateVoteResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type BulkCreateVotesResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Accepted int64                  `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Failed   int64                  `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`
+	// errors has the first failures, in the order of the stream
+	Errors        []*BulkVoteError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCreateVotesResponse) Reset() {
+	*x = BulkCreateVotesResponse{}
+	mi := &file_vote_v1_vote_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCreateVotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCreateVotesResponse) ProtoMessage() {}
+
+func (x *BulkCreateVotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCreateVotesResponse.ProtoReflect.Descriptor instead.
+func (*BulkCreateVotesResponse) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BulkCreateVotesResponse) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *BulkCreateVotesResponse) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *BulkCreateVotesResponse) GetErrors() []*BulkVoteError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type BulkVoteError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// index is the position of the vote in the stream, starting at 0
+	Index         int64  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkVoteError) Reset() {
+	*x = BulkVoteError{}
+	mi := &file_vote_v1_vote_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkVoteError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkVoteError) ProtoMessage() {}
+
+func (x *BulkVoteError) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkVoteError.ProtoReflect.Descriptor instead.
+func (*BulkVoteError) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BulkVoteError) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkVoteError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CreateRoundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoundId       string                 `protobuf:"bytes,1,opt,name=round_id,json=roundId,proto3" json:"round_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Participants  []*Participant         `protobuf:"bytes,3,rep,name=participants,proto3" json:"participants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRoundRequest) Reset() {
+	*x = CreateRoundRequest{}
+	mi := &file_vote_v1_vote_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRoundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRoundRequest) ProtoMessage() {}
+
+func (x *CreateRoundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRoundRequest.ProtoReflect.Descriptor instead.
+func (*CreateRoundRequest) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateRoundRequest) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+func (x *CreateRoundRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateRoundRequest) GetParticipants() []*Participant {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+type Participant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Participant) Reset() {
+	*x = Participant{}
+	mi := &file_vote_v1_vote_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Participant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Participant) ProtoMessage() {}
+
+func (x *Participant) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Participant.ProtoReflect.Descriptor instead.
+func (*Participant) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Participant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Participant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateRoundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRoundResponse) Reset() {
+	*x = CreateRoundResponse{}
+	mi := &file_vote_v1_vote_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRoundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRoundResponse) ProtoMessage() {}
+
+func (x *CreateRoundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRoundResponse.ProtoReflect.Descriptor instead.
+func (*CreateRoundResponse) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateRoundResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type RoundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoundId       string                 `protobuf:"bytes,1,opt,name=round_id,json=roundId,proto3" json:"round_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoundRequest) Reset() {
+	*x = RoundRequest{}
+	mi := &file_vote_v1_vote_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundRequest) ProtoMessage() {}
+
+func (x *RoundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundRequest.ProtoReflect.Descriptor instead.
+func (*RoundRequest) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RoundRequest) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+type TotalVotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TotalVotesResponse) Reset() {
+	*x = TotalVotesResponse{}
+	mi := &file_vote_v1_vote_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TotalVotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TotalVotesResponse) ProtoMessage() {}
+
+func (x *TotalVotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TotalVotesResponse.ProtoReflect.Descriptor instead.
+func (*TotalVotesResponse) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TotalVotesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type VotesByKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Votes         map[string]int64       `protobuf:"bytes,1,rep,name=votes,proto3" json:"votes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VotesByKeyResponse) Reset() {
+	*x = VotesByKeyResponse{}
+	mi := &file_vote_v1_vote_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VotesByKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VotesByKeyResponse) ProtoMessage() {}
+
+func (x *VotesByKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VotesByKeyResponse.ProtoReflect.Descriptor instead.
+func (*VotesByKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VotesByKeyResponse) GetVotes() map[string]int64 {
+	if x != nil {
+		return x.Votes
+	}
+	return nil
+}
+
+type Winner struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ParticipantId string                 `protobuf:"bytes,1,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	Votes         int64                  `protobuf:"varint,2,opt,name=votes,proto3" json:"votes,omitempty"`
+	Percentage    float64                `protobuf:"fixed64,3,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	// tie is true when other participants have the same number of votes,
+	// the winner is then the first one in alphabetical order
+	Tie           bool `protobuf:"varint,4,opt,name=tie,proto3" json:"tie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Winner) Reset() {
+	*x = Winner{}
+	mi := &file_vote_v1_vote_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Winner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Winner) ProtoMessage() {}
+
+func (x *Winner) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Winner.ProtoReflect.Descriptor instead.
+func (*Winner) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Winner) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *Winner) GetVotes() int64 {
+	if x != nil {
+		return x.Votes
+	}
+	return 0
+}
+
+func (x *Winner) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+func (x *Winner) GetTie() bool {
+	if x != nil {
+		return x.Tie
+	}
+	return false
+}
+
+type RankingEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      int64                  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	ParticipantId string                 `protobuf:"bytes,2,opt,name=participant_id,json=participantId,proto3" json:"participant_id,omitempty"`
+	Votes         int64                  `protobuf:"varint,3,opt,name=votes,proto3" json:"votes,omitempty"`
+	Percentage    float64                `protobuf:"fixed64,4,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RankingEntry) Reset() {
+	*x = RankingEntry{}
+	mi := &file_vote_v1_vote_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RankingEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RankingEntry) ProtoMessage() {}
+
+func (x *RankingEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RankingEntry.ProtoReflect.Descriptor instead.
+func (*RankingEntry) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RankingEntry) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *RankingEntry) GetParticipantId() string {
+	if x != nil {
+		return x.ParticipantId
+	}
+	return ""
+}
+
+func (x *RankingEntry) GetVotes() int64 {
+	if x != nil {
+		return x.Votes
+	}
+	return 0
+}
+
+func (x *RankingEntry) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+type RankingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*RankingEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RankingResponse) Reset() {
+	*x = RankingResponse{}
+	mi := &file_vote_v1_vote_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RankingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RankingResponse) ProtoMessage() {}
+
+func (x *RankingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RankingResponse.ProtoReflect.Descriptor instead.
+func (*RankingResponse) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RankingResponse) GetEntries() []*RankingEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type TimeSeriesPoint struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// timestamp is the Unix timestamp of the beginning of the hour
+	Timestamp     int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Votes         int64 `protobuf:"varint,2,opt,name=votes,proto3" json:"votes,omitempty"`
+	Cumulative    int64 `protobuf:"varint,3,opt,name=cumulative,proto3" json:"cumulative,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeSeriesPoint) Reset() {
+	*x = TimeSeriesPoint{}
+	mi := &file_vote_v1_vote_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeSeriesPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSeriesPoint) ProtoMessage() {}
+
+func (x *TimeSeriesPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSeriesPoint.ProtoReflect.Descriptor instead.
+func (*TimeSeriesPoint) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TimeSeriesPoint) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TimeSeriesPoint) GetVotes() int64 {
+	if x != nil {
+		return x.Votes
+	}
+	return 0
+}
+
+func (x *TimeSeriesPoint) GetCumulative() int64 {
+	if x != nil {
+		return x.Cumulative
+	}
+	return 0
+}
+
+type TimeSeriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Points        []*TimeSeriesPoint     `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeSeriesResponse) Reset() {
+	*x = TimeSeriesResponse{}
+	mi := &file_vote_v1_vote_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSeriesResponse) ProtoMessage() {}
+
+func (x *TimeSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSeriesResponse.ProtoReflect.Descriptor instead.
+func (*TimeSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TimeSeriesResponse) GetPoints() []*TimeSeriesPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+type UniqueVotersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Participants  map[string]int64       `protobuf:"bytes,2,rep,name=participants,proto3" json:"participants,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniqueVotersResponse) Reset() {
+	*x = UniqueVotersResponse{}
+	mi := &file_vote_v1_vote_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniqueVotersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniqueVotersResponse) ProtoMessage() {}
+
+func (x *UniqueVotersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniqueVotersResponse.ProtoReflect.Descriptor instead.
+func (*UniqueVotersResponse) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UniqueVotersResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *UniqueVotersResponse) GetParticipants() map[string]int64 {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+type WatchTotalsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	RoundId string                 `protobuf:"bytes,1,opt,name=round_id,json=roundId,proto3" json:"round_id,omitempty"`
+	// interval is how often the totals are checked, 1s when not set
+	Interval      *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTotalsRequest) Reset() {
+	*x = WatchTotalsRequest{}
+	mi := &file_vote_v1_vote_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTotalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTotalsRequest) ProtoMessage() {}
+
+func (x *WatchTotalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTotalsRequest.ProtoReflect.Descriptor instead.
+func (*WatchTotalsRequest) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WatchTotalsRequest) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+func (x *WatchTotalsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type LiveTotals struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	RoundId      string                 `protobuf:"bytes,1,opt,name=round_id,json=roundId,proto3" json:"round_id,omitempty"`
+	Total        int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Participants map[string]int64       `protobuf:"bytes,3,rep,name=participants,proto3" json:"participants,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// timestamp is the Unix timestamp of the check
+	Timestamp     int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LiveTotals) Reset() {
+	*x = LiveTotals{}
+	mi := &file_vote_v1_vote_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LiveTotals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LiveTotals) ProtoMessage() {}
+
+func (x *LiveTotals) ProtoReflect() protoreflect.Message {
+	mi := &file_vote_v1_vote_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LiveTotals.ProtoReflect.Descriptor instead.
+func (*LiveTotals) Descriptor() ([]byte, []int) {
+	return file_vote_v1_vote_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LiveTotals) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+func (x *LiveTotals) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *LiveTotals) GetParticipants() map[string]int64 {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+func (x *LiveTotals) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+var File_vote_v1_vote_proto protoreflect.FileDescriptor
+
+const file_vote_v1_vote_proto_rawDesc = "" +
+	"\n" +
+	"\x12vote/v1/vote.proto\x12\vbbb.vote.v1\x1a\x1egoogle/protobuf/duration.proto\"p\n" +
+	"\x11CreateVoteRequest\x12\x19\n" +
+	"\bround_id\x18\x01 \x01(\tR\aroundId\x12%\n" +
+	"\x0eparticipant_id\x18\x02 \x01(\tR\rparticipantId\x12\x19\n" +
+	"\bvoter_id\x18\x03 \x01(\tR\avoterId\",\n" +
+	"\x12CreateVoteResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"\x81\x01\n" +
+	"\x17BulkCreateVotesResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x03R\baccepted\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\x03R\x06failed\x122\n" +
+	"\x06errors\x18\x03 \x03(\v2\x1a.bbb.vote.v1.BulkVoteErrorR\x06errors\"?\n" +
+	"\rBulkVoteError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x03R\x05index\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x81\x01\n" +
+	"\x12CreateRoundRequest\x12\x19\n" +
+	"\bround_id\x18\x01 \x01(\tR\aroundId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12<\n" +
+	"\fparticipants\x18\x03 \x03(\v2\x18.bbb.vote.v1.ParticipantR\fparticipants\"1\n" +
+	"\vParticipant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"-\n" +
+	"\x13CreateRoundResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\")\n" +
+	"\fRoundRequest\x12\x19\n" +
+	"\bround_id\x18\x01 \x01(\tR\aroundId\"*\n" +
+	"\x12TotalVotesResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\"\x90\x01\n" +
+	"\x12VotesByKeyResponse\x12@\n" +
+	"\x05votes\x18\x01 \x03(\v2*.bbb.vote.v1.VotesByKeyResponse.VotesEntryR\x05votes\x1a8\n" +
+	"\n" +
+	"VotesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"w\n" +
+	"\x06Winner\x12%\n" +
+	"\x0eparticipant_id\x18\x01 \x01(\tR\rparticipantId\x12\x14\n" +
+	"\x05votes\x18\x02 \x01(\x03R\x05votes\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x03 \x01(\x01R\n" +
+	"percentage\x12\x10\n" +
+	"\x03tie\x18\x04 \x01(\bR\x03tie\"\x87\x01\n" +
+	"\fRankingEntry\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x03R\bposition\x12%\n" +
+	"\x0eparticipant_id\x18\x02 \x01(\tR\rparticipantId\x12\x14\n" +
+	"\x05votes\x18\x03 \x01(\x03R\x05votes\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x04 \x01(\x01R\n" +
+	"percentage\"F\n" +
+	"\x0fRankingResponse\x123\n" +
+	"\aentries\x18\x01 \x03(\v2\x19.bbb.vote.v1.RankingEntryR\aentries\"e\n" +
+	"\x0fTimeSeriesPoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05votes\x18\x02 \x01(\x03R\x05votes\x12\x1e\n" +
+	"\n" +
+	"cumulative\x18\x03 \x01(\x03R\n" +
+	"cumulative\"J\n" +
+	"\x12TimeSeriesResponse\x124\n" +
+	"\x06points\x18\x01 \x03(\v2\x1c.bbb.vote.v1.TimeSeriesPointR\x06points\"\xc6\x01\n" +
+	"\x14UniqueVotersResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x12W\n" +
+	"\fparticipants\x18\x02 \x03(\v23.bbb.vote.v1.UniqueVotersResponse.ParticipantsEntryR\fparticipants\x1a?\n" +
+	"\x11ParticipantsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"f\n" +
+	"\x12WatchTotalsRequest\x12\x19\n" +
+	"\bround_id\x18\x01 \x01(\tR\aroundId\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\xeb\x01\n" +
+	"\n" +
+	"LiveTotals\x12\x19\n" +
+	"\bround_id\x18\x01 \x01(\tR\aroundId\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12M\n" +
+	"\fparticipants\x18\x03 \x03(\v2).bbb.vote.v1.LiveTotals.ParticipantsEntryR\fparticipants\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x1a?\n" +
+	"\x11ParticipantsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x012\xf2\x06\n" +
+	"\vVoteService\x12M\n" +
+	"\n" +
+	"CreateVote\x12\x1e.bbb.vote.v1.CreateVoteRequest\x1a\x1f.bbb.vote.v1.CreateVoteResponse\x12Y\n" +
+	"\x0fBulkCreateVotes\x12\x1e.bbb.vote.v1.CreateVoteRequest\x1a$.bbb.vote.v1.BulkCreateVotesResponse(\x01\x12P\n" +
+	"\vCreateRound\x12\x1f.bbb.vote.v1.CreateRoundRequest\x1a .bbb.vote.v1.CreateRoundResponse\x12K\n" +
+	"\rGetTotalVotes\x12\x19.bbb.vote.v1.RoundRequest\x1a\x1f.bbb.vote.v1.TotalVotesResponse\x12Y\n" +
+	"\x1bGetTotalVotesForParticipant\x12\x19.bbb.vote.v1.RoundRequest\x1a\x1f.bbb.vote.v1.VotesByKeyResponse\x12R\n" +
+	"\x14GetTotalVotesForHour\x12\x19.bbb.vote.v1.RoundRequest\x1a\x1f.bbb.vote.v1.VotesByKeyResponse\x12;\n" +
+	"\tGetWinner\x12\x19.bbb.vote.v1.RoundRequest\x1a\x13.bbb.vote.v1.Winner\x12E\n" +
+	"\n" +
+	"GetRanking\x12\x19.bbb.vote.v1.RoundRequest\x1a\x1c.bbb.vote.v1.RankingResponse\x12K\n" +
+	"\rGetTimeSeries\x12\x19.bbb.vote.v1.RoundRequest\x1a\x1f.bbb.vote.v1.TimeSeriesResponse\x12O\n" +
+	"\x0fGetUniqueVoters\x12\x19.bbb.vote.v1.RoundRequest\x1a!.bbb.vote.v1.UniqueVotersResponse\x12I\n" +
+	"\vWatchTotals\x12\x1f.bbb.vote.v1.WatchTotalsRequest\x1a\x17.bbb.vote.v1.LiveTotals0\x01B0Z.github.com/sergiodii/bbb/pkg/pb/vote/v1;votev1b\x06proto3"
+
+var (
+	file_vote_v1_vote_proto_rawDescOnce sync.Once
+	file_vote_v1_vote_proto_rawDescData []byte
+)
+
+func file_vote_v1_vote_proto_rawDescGZIP() []byte {
+	file_vote_v1_vote_proto_rawDescOnce.Do(func() {
+		file_vote_v1_vote_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vote_v1_vote_proto_rawDesc), len(file_vote_v1_vote_proto_rawDesc)))
+	})
+	return file_vote_v1_vote_proto_rawDescData
+}
+
+var file_vote_v1_vote_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_vote_v1_vote_proto_goTypes = []any{
+	(*CreateVoteRequest)(nil),       // 0: bbb.vote.v1.CreateVoteRequest
+	(*CreateVoteResponse)(nil),      // 1: bbb.vote.v1.CreateVoteResponse
+	(*BulkCreateVotesResponse)(nil), // 2: bbb.vote.v1.BulkCreateVotesResponse
+	(*BulkVoteError)(nil),           // 3: bbb.vote.v1.BulkVoteError
+	(*CreateRoundRequest)(nil),      // 4: bbb.vote.v1.CreateRoundRequest
+	(*Participant)(nil),             // 5: bbb.vote.v1.Participant
+	(*CreateRoundResponse)(nil),     // 6: bbb.vote.v1.CreateRoundResponse
+	(*RoundRequest)(nil),            // 7: bbb.vote.v1.RoundRequest
+	(*TotalVotesResponse)(nil),      // 8: bbb.vote.v1.TotalVotesResponse
+	(*VotesByKeyResponse)(nil),      // 9: bbb.vote.v1.VotesByKeyResponse
+	(*Winner)(nil),                  // 10: bbb.vote.v1.Winner
+	(*RankingEntry)(nil),            // 11: bbb.vote.v1.RankingEntry
+	(*RankingResponse)(nil),         // 12: bbb.vote.v1.RankingResponse
+	(*TimeSeriesPoint)(nil),         // 13: bbb.vote.v1.TimeSeriesPoint
+	(*TimeSeriesResponse)(nil),      // 14: bbb.vote.v1.TimeSeriesResponse
+	(*UniqueVotersResponse)(nil),    // 15: bbb.vote.v1.UniqueVotersResponse
+	(*WatchTotalsRequest)(nil),      // 16: bbb.vote.v1.WatchTotalsRequest
+	(*LiveTotals)(nil),              // 17: bbb.vote.v1.LiveTotals
+	nil,                             // 18: bbb.vote.v1.VotesByKeyResponse.VotesEntry
+	nil,                             // 19: bbb.vote.v1.UniqueVotersResponse.ParticipantsEntry
+	nil,                             // 20: bbb.vote.v1.LiveTotals.ParticipantsEntry
+	(*durationpb.Duration)(nil),     // 21: google.protobuf.Duration
+}
+var file_vote_v1_vote_proto_depIdxs = []int32{
+	3,  // 0: bbb.vote.v1.BulkCreateVotesResponse.errors:type_name -> bbb.vote.v1.BulkVoteError
+	5,  // 1: bbb.vote.v1.CreateRoundRequest.participants:type_name -> bbb.vote.v1.Participant
+	18, // 2: bbb.vote.v1.VotesByKeyResponse.votes:type_name -> bbb.vote.v1.VotesByKeyResponse.VotesEntry
+	11, // 3: bbb.vote.v1.RankingResponse.entries:type_name -> bbb.vote.v1.RankingEntry
+	13, // 4: bbb.vote.v1.TimeSeriesResponse.points:type_name -> bbb.vote.v1.TimeSeriesPoint
+	19, // 5: bbb.vote.v1.UniqueVotersResponse.participants:type_name -> bbb.vote.v1.UniqueVotersResponse.ParticipantsEntry
+	21, // 6: bbb.vote.v1.WatchTotalsRequest.interval:type_name -> google.protobuf.Duration
+	20, // 7: bbb.vote.v1.LiveTotals.participants:type_name -> bbb.vote.v1.LiveTotals.ParticipantsEntry
+	0,  // 8: bbb.vote.v1.VoteService.CreateVote:input_type -> bbb.vote.v1.CreateVoteRequest
+	0,  // 9: bbb.vote.v1.VoteService.BulkCreateVotes:input_type -> bbb.vote.v1.CreateVoteRequest
+	4,  // 10: bbb.vote.v1.VoteService.CreateRound:input_type -> bbb.vote.v1.CreateRoundRequest
+	7,  // 11: bbb.vote.v1.VoteService.GetTotalVotes:input_type -> bbb.vote.v1.RoundRequest
+	7,  // 12: bbb.vote.v1.VoteService.GetTotalVotesForParticipant:input_type -> bbb.vote.v1.RoundRequest
+	7,  // 13: bbb.vote.v1.VoteService.GetTotalVotesForHour:input_type -> bbb.vote.v1.RoundRequest
+	7,  // 14: bbb.vote.v1.VoteService.GetWinner:input_type -> bbb.vote.v1.RoundRequest
+	7,  // 15: bbb.vote.v1.VoteService.GetRanking:input_type -> bbb.vote.v1.RoundRequest
+	7,  // 16: bbb.vote.v1.VoteService.GetTimeSeries:input_type -> bbb.vote.v1.RoundRequest
+	7,  // 17: bbb.vote.v1.VoteService.GetUniqueVoters:input_type -> bbb.vote.v1.RoundRequest
+	16, // 18: bbb.vote.v1.VoteService.WatchTotals:input_type -> bbb.vote.v1.WatchTotalsRequest
+	1,  // 19: bbb.vote.v1.VoteService.CreateVote:output_type -> bbb.vote.v1.CreateVoteResponse
+	2,  // 20: bbb.vote.v1.VoteService.BulkCreateVotes:output_type -> bbb.vote.v1.BulkCreateVotesResponse
+	6,  // 21: bbb.vote.v1.VoteService.CreateRound:output_type -> bbb.vote.v1.CreateRoundResponse
+	8,  // 22: bbb.vote.v1.VoteService.GetTotalVotes:output_type -> bbb.vote.v1.TotalVotesResponse
+	9,  // 23: bbb.vote.v1.VoteService.GetTotalVotesForParticipant:output_type -> bbb.vote.v1.VotesByKeyResponse
+	9,  // 24: bbb.vote.v1.VoteService.GetTotalVotesForHour:output_type -> bbb.vote.v1.VotesByKeyResponse
+	10, // 25: bbb.vote.v1.VoteService.GetWinner:output_type -> bbb.vote.v1.Winner
+	12, // 26: bbb.vote.v1.VoteService.GetRanking:output_type -> bbb.vote.v1.RankingResponse
+	14, // 27: bbb.vote.v1.VoteService.GetTimeSeries:output_type -> bbb.vote.v1.TimeSeriesResponse
+	15, // 28: bbb.vote.v1.VoteService.GetUniqueVoters:output_type -> bbb.vote.v1.UniqueVotersResponse
+	17, // 29: bbb.vote.v1.VoteService.WatchTotals:output_type -> bbb.vote.v1.LiveTotals
+	19, // [19:30] is the sub-list for method output_type
+	8,  // [8:19] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_vote_v1_vote_proto_init() }
+func file_vote_v1_vote_proto_init() {
+	if File_vote_v1_vote_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vote_v1_vote_proto_rawDesc), len(file_vote_v1_vote_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vote_v1_vote_proto_goTypes,
+		DependencyIndexes: file_vote_v1_vote_proto_depIdxs,
+		MessageInfos:      file_vote_v1_vote_proto_msgTypes,
+	}.Build()
+	File_vote_v1_vote_proto = out.File
+	file_vote_v1_vote_proto_goTypes = nil
+	file_vote_v1_vote_proto_depIdxs = nil
+}
@@ -0,0 +1,531 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: vote/v1/vote.proto
+
+// The gRPC interface of the voting API, served by the grpc-api command.
+// The Go code in pkg/pb is generated with `make proto`.
+
+package votev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VoteService_CreateVote_FullMethodName                  = "/bbb.vote.v1.VoteService/CreateVote"
+	VoteService_BulkCreateVotes_FullMethodName             = "/bbb.vote.v1.VoteService/BulkCreateVotes"
+	VoteService_CreateRound_FullMethodName                 = "/bbb.vote.v1.VoteService/CreateRound"
+	VoteService_GetTotalVotes_FullMethodName               = "/bbb.vote.v1.VoteService/GetTotalVotes"
+	VoteService_GetTotalVotesForParticipant_FullMethodName = "/bbb.vote.v1.VoteService/GetTotalVotesForParticipant"
+	VoteService_GetTotalVotesForHour_FullMethodName        = "/bbb.vote.v1.VoteService/GetTotalVotesForHour"
+	VoteService_GetWinner_FullMethodName                   = "/bbb.vote.v1.VoteService/GetWinner"
+	VoteService_GetRanking_FullMethodName                  = "/bbb.vote.v1.VoteService/GetRanking"
+	VoteService_GetTimeSeries_FullMethodName               = "/bbb.vote.v1.VoteService/GetTimeSeries"
+	VoteService_GetUniqueVoters_FullMethodName             = "/bbb.vote.v1.VoteService/GetUniqueVoters"
+	VoteService_WatchTotals_FullMethodName                 = "/bbb.vote.v1.VoteService/WatchTotals"
+)
+
+// VoteServiceClient is the client API for VoteService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VoteService registers the votes and queries the results of the rounds,
+// with the same aggregators of the REST API
+type VoteServiceClient interface {
+	// CreateVote registers a vote
+	CreateVote(ctx context.Context, in *CreateVoteRequest, opts ...grpc.CallOption) (*CreateVoteResponse, error)
+	// BulkCreateVotes registers the votes sent in the stream, the failures do not stop the stream
+	BulkCreateVotes(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreateVoteRequest, BulkCreateVotesResponse], error)
+	// CreateRound stores the metadata of a round, a created round without votes is queried with zero totals
+	CreateRound(ctx context.Context, in *CreateRoundRequest, opts ...grpc.CallOption) (*CreateRoundResponse, error)
+	// GetTotalVotes returns the total number of votes of a round
+	GetTotalVotes(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*TotalVotesResponse, error)
+	// GetTotalVotesForParticipant returns the number of votes of each participant of a round
+	GetTotalVotesForParticipant(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*VotesByKeyResponse, error)
+	// GetTotalVotesForHour returns the number of votes per hour of a round,
+	// the key is the number of hours since the epoch
+	GetTotalVotesForHour(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*VotesByKeyResponse, error)
+	// GetWinner returns the participant with the most votes in a round
+	GetWinner(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*Winner, error)
+	// GetRanking returns the participants of a round ordered by the number of votes
+	GetRanking(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*RankingResponse, error)
+	// GetTimeSeries returns the number of votes per hour of a round, ordered by time
+	GetTimeSeries(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*TimeSeriesResponse, error)
+	// GetUniqueVoters returns the approximate number of unique voters of a round, overall and of each participant
+	GetUniqueVoters(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*UniqueVotersResponse, error)
+	// WatchTotals sends the totals of a round when they change, until the client cancels the stream
+	WatchTotals(ctx context.Context, in *WatchTotalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LiveTotals], error)
+}
+
+type voteServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVoteServiceClient(cc grpc.ClientConnInterface) VoteServiceClient {
+	return &voteServiceClient{cc}
+}
+
+func (c *voteServiceClient) CreateVote(ctx context.Context, in *CreateVoteRequest, opts ...grpc.CallOption) (*CreateVoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateVoteResponse)
+	err := c.cc.Invoke(ctx, VoteService_CreateVote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) BulkCreateVotes(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreateVoteRequest, BulkCreateVotesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VoteService_ServiceDesc.Streams[0], VoteService_BulkCreateVotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreateVoteRequest, BulkCreateVotesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VoteService_BulkCreateVotesClient = grpc.ClientStreamingClient[CreateVoteRequest, BulkCreateVotesResponse]
+
+func (c *voteServiceClient) CreateRound(ctx context.Context, in *CreateRoundRequest, opts ...grpc.CallOption) (*CreateRoundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateRoundResponse)
+	err := c.cc.Invoke(ctx, VoteService_CreateRound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) GetTotalVotes(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*TotalVotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TotalVotesResponse)
+	err := c.cc.Invoke(ctx, VoteService_GetTotalVotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) GetTotalVotesForParticipant(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*VotesByKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VotesByKeyResponse)
+	err := c.cc.Invoke(ctx, VoteService_GetTotalVotesForParticipant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) GetTotalVotesForHour(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*VotesByKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VotesByKeyResponse)
+	err := c.cc.Invoke(ctx, VoteService_GetTotalVotesForHour_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) GetWinner(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*Winner, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Winner)
+	err := c.cc.Invoke(ctx, VoteService_GetWinner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) GetRanking(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*RankingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RankingResponse)
+	err := c.cc.Invoke(ctx, VoteService_GetRanking_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) GetTimeSeries(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*TimeSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeSeriesResponse)
+	err := c.cc.Invoke(ctx, VoteService_GetTimeSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) GetUniqueVoters(ctx context.Context, in *RoundRequest, opts ...grpc.CallOption) (*UniqueVotersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UniqueVotersResponse)
+	err := c.cc.Invoke(ctx, VoteService_GetUniqueVoters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *voteServiceClient) WatchTotals(ctx context.Context, in *WatchTotalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LiveTotals], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VoteService_ServiceDesc.Streams[1], VoteService_WatchTotals_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTotalsRequest, LiveTotals]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VoteService_WatchTotalsClient = grpc.ServerStreamingClient[LiveTotals]
+
+// VoteServiceServer is the server API for VoteService service.
+// All implementations must embed UnimplementedVoteServiceServer
+// for forward compatibility.
+//
+// VoteService registers the votes and queries the results of the rounds,
+// with the same aggregators of the REST API
+type VoteServiceServer interface {
+	// CreateVote registers a vote
+	CreateVote(context.Context, *CreateVoteRequest) (*CreateVoteResponse, error)
+	// BulkCreateVotes registers the votes sent in the stream, the failures do not stop the stream
+	BulkCreateVotes(grpc.ClientStreamingServer[CreateVoteRequest, BulkCreateVotesResponse]) error
+	// CreateRound stores the metadata of a round, a created round without votes is queried with zero totals
+	CreateRound(context.Context, *CreateRoundRequest) (*CreateRoundResponse, error)
+	// GetTotalVotes returns the total number of votes of a round
+	GetTotalVotes(context.Context, *RoundRequest) (*TotalVotesResponse, error)
+	// GetTotalVotesForParticipant returns the number of votes of each participant of a round
+	GetTotalVotesForParticipant(context.Context, *RoundRequest) (*VotesByKeyResponse, error)
+	// GetTotalVotesForHour returns the number of votes per hour of a round,
+	// the key is the number of hours since the epoch
+	GetTotalVotesForHour(context.Context, *RoundRequest) (*VotesByKeyResponse, error)
+	// GetWinner returns the participant with the most votes in a round
+	GetWinner(context.Context, *RoundRequest) (*Winner, error)
+	// GetRanking returns the participants of a round ordered by the number of votes
+	GetRanking(context.Context, *RoundRequest) (*RankingResponse, error)
+	// GetTimeSeries returns the number of votes per hour of a round, ordered by time
+	GetTimeSeries(context.Context, *RoundRequest) (*TimeSeriesResponse, error)
+	// GetUniqueVoters returns the approximate number of unique voters of a round, overall and of each participant
+	GetUniqueVoters(context.Context, *RoundRequest) (*UniqueVotersResponse, error)
+	// WatchTotals sends the totals of a round when they change, until the client cancels the stream
+	WatchTotals(*WatchTotalsRequest, grpc.ServerStreamingServer[LiveTotals]) error
+	mustEmbedUnimplementedVoteServiceServer()
+}
+
+// UnimplementedVoteServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVoteServiceServer struct{}
+
+func (UnimplementedVoteServiceServer) CreateVote(context.Context, *CreateVoteRequest) (*CreateVoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVote not implemented")
+}
+func (UnimplementedVoteServiceServer) BulkCreateVotes(grpc.ClientStreamingServer[CreateVoteRequest, BulkCreateVotesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BulkCreateVotes not implemented")
+}
+func (UnimplementedVoteServiceServer) CreateRound(context.Context, *CreateRoundRequest) (*CreateRoundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRound not implemented")
+}
+func (UnimplementedVoteServiceServer) GetTotalVotes(context.Context, *RoundRequest) (*TotalVotesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTotalVotes not implemented")
+}
+func (UnimplementedVoteServiceServer) GetTotalVotesForParticipant(context.Context, *RoundRequest) (*VotesByKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTotalVotesForParticipant not implemented")
+}
+func (UnimplementedVoteServiceServer) GetTotalVotesForHour(context.Context, *RoundRequest) (*VotesByKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTotalVotesForHour not implemented")
+}
+func (UnimplementedVoteServiceServer) GetWinner(context.Context, *RoundRequest) (*Winner, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWinner not implemented")
+}
+func (UnimplementedVoteServiceServer) GetRanking(context.Context, *RoundRequest) (*RankingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRanking not implemented")
+}
+func (UnimplementedVoteServiceServer) GetTimeSeries(context.Context, *RoundRequest) (*TimeSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimeSeries not implemented")
+}
+func (UnimplementedVoteServiceServer) GetUniqueVoters(context.Context, *RoundRequest) (*UniqueVotersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUniqueVoters not implemented")
+}
+func (UnimplementedVoteServiceServer) WatchTotals(*WatchTotalsRequest, grpc.ServerStreamingServer[LiveTotals]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTotals not implemented")
+}
+func (UnimplementedVoteServiceServer) mustEmbedUnimplementedVoteServiceServer() {}
+func (UnimplementedVoteServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeVoteServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VoteServiceServer will
+// result in compilation errors.
+type UnsafeVoteServiceServer interface {
+	mustEmbedUnimplementedVoteServiceServer()
+}
+
+func RegisterVoteServiceServer(s grpc.ServiceRegistrar, srv VoteServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVoteServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VoteService_ServiceDesc, srv)
+}
+
+func _VoteService_CreateVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).CreateVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_CreateVote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).CreateVote(ctx, req.(*CreateVoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_BulkCreateVotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VoteServiceServer).BulkCreateVotes(&grpc.GenericServerStream[CreateVoteRequest, BulkCreateVotesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VoteService_BulkCreateVotesServer = grpc.ClientStreamingServer[CreateVoteRequest, BulkCreateVotesResponse]
+
+func _VoteService_CreateRound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).CreateRound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_CreateRound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).CreateRound(ctx, req.(*CreateRoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_GetTotalVotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).GetTotalVotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_GetTotalVotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).GetTotalVotes(ctx, req.(*RoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_GetTotalVotesForParticipant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).GetTotalVotesForParticipant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_GetTotalVotesForParticipant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).GetTotalVotesForParticipant(ctx, req.(*RoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_GetTotalVotesForHour_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).GetTotalVotesForHour(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_GetTotalVotesForHour_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).GetTotalVotesForHour(ctx, req.(*RoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_GetWinner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).GetWinner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_GetWinner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).GetWinner(ctx, req.(*RoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_GetRanking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).GetRanking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_GetRanking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).GetRanking(ctx, req.(*RoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_GetTimeSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).GetTimeSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_GetTimeSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).GetTimeSeries(ctx, req.(*RoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_GetUniqueVoters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VoteServiceServer).GetUniqueVoters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VoteService_GetUniqueVoters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VoteServiceServer).GetUniqueVoters(ctx, req.(*RoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VoteService_WatchTotals_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTotalsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VoteServiceServer).WatchTotals(m, &grpc.GenericServerStream[WatchTotalsRequest, LiveTotals]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VoteService_WatchTotalsServer = grpc.ServerStreamingServer[LiveTotals]
+
+// VoteService_ServiceDesc is the grpc.ServiceDesc for VoteService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VoteService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bbb.vote.v1.VoteService",
+	HandlerType: (*VoteServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateVote",
+			Handler:    _VoteService_CreateVote_Handler,
+		},
+		{
+			MethodName: "CreateRound",
+			Handler:    _VoteService_CreateRound_Handler,
+		},
+		{
+			MethodName: "GetTotalVotes",
+			Handler:    _VoteService_GetTotalVotes_Handler,
+		},
+		{
+			MethodName: "GetTotalVotesForParticipant",
+			Handler:    _VoteService_GetTotalVotesForParticipant_Handler,
+		},
+		{
+			MethodName: "GetTotalVotesForHour",
+			Handler:    _VoteService_GetTotalVotesForHour_Handler,
+		},
+		{
+			MethodName: "GetWinner",
+			Handler:    _VoteService_GetWinner_Handler,
+		},
+		{
+			MethodName: "GetRanking",
+			Handler:    _VoteService_GetRanking_Handler,
+		},
+		{
+			MethodName: "GetTimeSeries",
+			Handler:    _VoteService_GetTimeSeries_Handler,
+		},
+		{
+			MethodName: "GetUniqueVoters",
+			Handler:    _VoteService_GetUniqueVoters_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BulkCreateVotes",
+			Handler:       _VoteService_BulkCreateVotes_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchTotals",
+			Handler:       _VoteService_WatchTotals_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "vote/v1/vote.proto",
+}
@@ -0,0 +1,90 @@
+// Package problem writes the errors of the API as problem details (RFC 7807),
+// with the media type application/problem+json.
+package problem
+
+import (
+	"net/http"
+
+	"github.com/sergiodii/bbb/internal/domain/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of the problems
+const ContentType = "application/problem+json"
+
+// The codes of the problems, stable identifiers of the errors for the clients
+const (
+	CodeValidation   = "validation_error"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeRateLimited  = "rate_limited"
+	CodeUnavailable  = "unavailable"
+	CodeInternal     = "internal_error"
+)
+
+// internalDetail is the detail of the unexpected errors, their message is only logged
+const internalDetail = "an unexpected error occurred"
+
+// Problem is the body of the error responses.
+// Type is about:blank, so Title is the text of the status; Code is an extension with the code of the error.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// New creates the problem of the status
+func New(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// kinds are the status and the code of each kind of domain error
+var kinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{errs.ErrValidation, http.StatusBadRequest, CodeValidation},
+	{errs.ErrNotFound, http.StatusNotFound, CodeNotFound},
+	{errs.ErrConflict, http.StatusConflict, CodeConflict},
+	{errs.ErrRateLimited, http.StatusTooManyRequests, CodeRateLimited},
+	{errs.ErrUnavailable, http.StatusServiceUnavailable, CodeUnavailable},
+}
+
+// FromError converts the error into a problem. The errors of the domain keep their message, the others
+// are internal errors whose message is not exposed, it could reveal details of the repositories.
+func FromError(err error) Problem {
+	kind := errs.KindOf(err)
+	for _, k := range kinds {
+		if k.kind == kind {
+			return New(k.status, k.code, errs.Message(err))
+		}
+	}
+	return New(http.StatusInternalServerError, CodeInternal, internalDetail)
+}
+
+// Write writes the problem as the response and aborts the request, the instance is the path of the request
+func Write(c *gin.Context, p Problem) {
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// Error writes the error as a problem, see FromError
+func Error(c *gin.Context, err error) {
+	Write(c, FromError(err))
+}
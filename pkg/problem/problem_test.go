@@ -0,0 +1,71 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sergiodii/bbb/internal/domain/errs"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{"validation", errs.New(errs.ErrValidation, "participant_id is required"), http.StatusBadRequest, CodeValidation, "participant_id is required"},
+		{"not found", fmt.Errorf("round1: %w", errs.ErrNotFound), http.StatusNotFound, CodeNotFound, "not found"},
+		{"conflict", errs.New(errs.ErrConflict, "the round is closed"), http.StatusConflict, CodeConflict, "the round is closed"},
+		{"rate limited", errs.New(errs.ErrRateLimited, "slow down"), http.StatusTooManyRequests, CodeRateLimited, "slow down"},
+		{"unavailable", errors.Join(errs.Wrap(errs.ErrUnavailable, errors.New("dial tcp 10.0.0.1:6379"), "the vote store is unavailable")), http.StatusServiceUnavailable, CodeUnavailable, "the vote store is unavailable"},
+		{"internal", errors.New("redis: nil"), http.StatusInternalServerError, CodeInternal, internalDetail},
+	}
+
+	for _, tt := range tests {
+		t.Run("Should convert the "+tt.name+" errors", func(t *testing.T) {
+			// Act
+			p := FromError(tt.err)
+
+			// Assert
+			assert.Equal(t, Problem{Type: "about:blank", Title: http.StatusText(tt.status), Status: tt.status, Detail: tt.detail, Code: tt.code}, p)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Should write the problem and abort the request", func(t *testing.T) {
+		// Arrange
+		r := gin.New()
+		next := false
+		r.GET("/query/:round_id", func(c *gin.Context) {
+			Error(c, errs.New(errs.ErrValidation, "round_id is required"))
+		}, func(c *gin.Context) {
+			next = true
+		})
+
+		// Act
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/query/round1", nil))
+
+		// Assert
+		var p Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "/query/round1", p.Instance)
+		assert.Equal(t, CodeValidation, p.Code)
+		assert.False(t, next)
+	})
+}
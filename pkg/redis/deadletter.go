@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const deadLetterKey = "deadletter:votes"
+
+type RedisDeadLetterRepository struct {
+	Client redis.UniversalClient
+}
+
+// Push adds the dead letter to the head of a Redis list
+func (r *RedisDeadLetterRepository) Push(ctx context.Context, letter entity.DeadLetter) error {
+	b, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+	return mapError(r.Client.LPush(ctx, deadLetterKey, b).Err())
+}
+
+// Pop removes the dead letter from the tail of the Redis list, so the oldest one is returned first
+func (r *RedisDeadLetterRepository) Pop(ctx context.Context) (entity.DeadLetter, bool, error) {
+	var letter entity.DeadLetter
+
+	b, err := r.Client.RPop(ctx, deadLetterKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return letter, false, nil
+	}
+	if err != nil {
+		return letter, false, mapError(err)
+	}
+
+	if err := json.Unmarshal(b, &letter); err != nil {
+		return letter, false, err
+	}
+	return letter, true, nil
+}
+
+// Close closes the connections with the Redis server.
+func (r *RedisDeadLetterRepository) Close() error {
+	return r.Client.Close()
+}
+
+// NewRedisDeadLetterRepository creates the dead letter repository connected to the Redis server with the default options
+func NewRedisDeadLetterRepository(addr string) repository.DeadLetterRepository {
+	return NewRedisDeadLetterRepositoryWithOptions(DefaultOptions(addr))
+}
+
+// NewRedisDeadLetterRepositoryWithOptions creates the dead letter repository connected to the Redis server configured by the options
+func NewRedisDeadLetterRepositoryWithOptions(o Options) repository.DeadLetterRepository {
+	return &RedisDeadLetterRepository{Client: o.newClient()}
+}
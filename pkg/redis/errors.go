@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/sergiodii/bbb/internal/domain/errs"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// unavailablePrefixes are the replies of a Redis server that can not answer for now
+var unavailablePrefixes = []string{"LOADING ", "READONLY ", "CLUSTERDOWN ", "TRYAGAIN ", "MASTERDOWN "}
+
+// mapError converts the errors of the Redis client into errors of the domain:
+// a missing key is ErrNotFound, and the failures to reach the server are ErrUnavailable.
+// The other errors are returned as they are.
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, redis.Nil) {
+		return errs.Wrap(errs.ErrNotFound, err, "not found")
+	}
+	if unavailable(err) {
+		return errs.Wrap(errs.ErrUnavailable, err, "the vote store is unavailable")
+	}
+	return err
+}
+
+// unavailable returns true when the error comes from the connection with the server or from a server not ready
+func unavailable(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, redis.ErrClosed) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	msg := err.Error()
+	if msg == "redis: connection pool timeout" || msg == "ERR max number of clients reached" {
+		return true
+	}
+	for _, prefix := range unavailablePrefixes {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return false
+}
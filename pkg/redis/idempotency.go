@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sergiodii/bbb/internal/domain/repository"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// The values of an idempotency key
+const (
+	idempotencyPending = "pending"
+	idempotencyDone    = "done"
+)
+
+func idempotencyKey(key string) string { return "idempotency:" + key }
+
+// RedisIdempotencyRepository stores each idempotency key in a string with the TTL,
+// reserved with SET NX so only one of the requests with the same key is processed
+type RedisIdempotencyRepository struct {
+	Client redis.UniversalClient
+}
+
+// Reserve sets the key as pending when it does not exist, otherwise it returns the state stored in the key
+func (r *RedisIdempotencyRepository) Reserve(ctx context.Context, key string, ttl time.Duration) (repository.IdempotencyState, error) {
+	ok, err := r.Client.SetNX(ctx, idempotencyKey(key), idempotencyPending, ttl).Result()
+	if err != nil {
+		return repository.IdempotencyPending, mapError(err)
+	}
+	if ok {
+		return repository.IdempotencyReserved, nil
+	}
+
+	value, err := r.Client.Get(ctx, idempotencyKey(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		// the key was released or expired after SET NX, the request is retried
+		return repository.IdempotencyPending, nil
+	}
+	if err != nil {
+		return repository.IdempotencyPending, mapError(err)
+	}
+	if value == idempotencyDone {
+		return repository.IdempotencyDone, nil
+	}
+	return repository.IdempotencyPending, nil
+}
+
+// Complete sets the key as done, its TTL starts again
+func (r *RedisIdempotencyRepository) Complete(ctx context.Context, key string, ttl time.Duration) error {
+	return mapError(r.Client.Set(ctx, idempotencyKey(key), idempotencyDone, ttl).Err())
+}
+
+// Release deletes the key
+func (r *RedisIdempotencyRepository) Release(ctx context.Context, key string) error {
+	return mapError(r.Client.Del(ctx, idempotencyKey(key)).Err())
+}
+
+// Close closes the connections with the Redis server.
+func (r *RedisIdempotencyRepository) Close() error {
+	return r.Client.Close()
+}
+
+// NewRedisIdempotencyRepositoryWithOptions creates the idempotency repository connected to the Redis server configured by the options
+func NewRedisIdempotencyRepositoryWithOptions(o Options) repository.IdempotencyRepository {
+	return &RedisIdempotencyRepository{Client: o.newClient()}
+}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// The keys of the first version of the repository, one string per counter and without the hash tag:
+// round:<id>:total, round:<id>:participant:<pid> and round:<id>:hour:<hour>.
+// The round id of the current keys is between braces, so they never match.
+var (
+	legacyTotalKey       = regexp.MustCompile(`^round:([^:{}]+):total$`)
+	legacyParticipantKey = regexp.MustCompile(`^round:([^:{}]+):participant:(.+)$`)
+	legacyHourKey        = regexp.MustCompile(`^round:([^:{}]+):hour:(\d+)$`)
+)
+
+// legacyScanCount is the number of keys SCAN looks at in each call
+const legacyScanCount = 1000
+
+// MigrateLegacyKeys moves the counters stored with the keys of the first version of the repository
+// to the current keys, and returns the number of legacy keys moved.
+// The counters are copied, not added, and each legacy key is deleted after it is copied, so running it again
+// after a failure is safe. The votes registered in the current keys of a round being moved are overwritten,
+// so it must run while the command API is stopped. The keys are found with SCAN, on every primary of a Cluster.
+func (r *RedisRoundRepository) MigrateLegacyKeys(ctx context.Context) (int, error) {
+	cluster, ok := r.Client.(*redis.ClusterClient)
+	if !ok {
+		return r.migrateLegacyKeys(ctx, r.Client)
+	}
+
+	// the primaries are scanned at the same time
+	var moved atomic.Int64
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		n, err := r.migrateLegacyKeys(ctx, node)
+		moved.Add(int64(n))
+		return err
+	})
+	return int(moved.Load()), err
+}
+
+// migrateLegacyKeys moves the legacy keys found in the node, the counters are written through the repository client
+func (r *RedisRoundRepository) migrateLegacyKeys(ctx context.Context, node redis.UniversalClient) (int, error) {
+	moved := 0
+	iter := node.Scan(ctx, 0, "round:*", legacyScanCount).Iterator()
+	for iter.Next(ctx) {
+		ok, err := r.migrateLegacyKey(ctx, iter.Val())
+		if err != nil {
+			return moved, err
+		}
+		if ok {
+			moved++
+		}
+	}
+	return moved, mapError(iter.Err())
+}
+
+// migrateLegacyKey copies the counter of the legacy key to the current key and deletes it,
+// it returns false when the key is not a legacy key or was already moved
+func (r *RedisRoundRepository) migrateLegacyKey(ctx context.Context, key string) (bool, error) {
+	var write func(value int) error
+	if m := legacyTotalKey.FindStringSubmatch(key); m != nil {
+		write = func(value int) error { return r.Client.Set(ctx, totalKey(m[1]), value, 0).Err() }
+	} else if m := legacyParticipantKey.FindStringSubmatch(key); m != nil {
+		write = func(value int) error { return r.Client.HSet(ctx, participantsKey(m[1]), m[2], value).Err() }
+	} else if m := legacyHourKey.FindStringSubmatch(key); m != nil {
+		write = func(value int) error { return r.Client.HSet(ctx, hoursKey(m[1]), m[2], value).Err() }
+	} else {
+		return false, nil
+	}
+
+	value, err := r.Client.Get(ctx, key).Int()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	// in a Cluster the legacy key may be in another slot than the current key, so it is deleted apart
+	if err := write(value); err != nil {
+		return false, mapError(err)
+	}
+	return true, mapError(r.Client.Del(ctx, key).Err())
+}
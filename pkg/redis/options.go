@@ -0,0 +1,204 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrInvalidURL is returned when the Redis URL can not be parsed
+var ErrInvalidURL = errors.New("invalid redis url")
+
+// The schemes accepted by WithURL, the rediss variants enable TLS
+const (
+	SchemeSingle   = "redis"
+	SchemeSentinel = "redis+sentinel"
+	SchemeCluster  = "redis+cluster"
+)
+
+// Options are the connection settings of the Redis client.
+// A single node is used by default, see MasterName for Sentinel and Cluster for Redis Cluster.
+type Options struct {
+	// Addrs is the address of the server, or the seed list of the Sentinel or Cluster nodes
+	Addrs []string
+
+	// MasterName is the name of the primary monitored by the Sentinel nodes of Addrs
+	MasterName string
+
+	// Cluster connects to a Redis Cluster, Addrs are the seed nodes
+	Cluster bool
+
+	Username string
+	Password string
+
+	// SentinelUsername and SentinelPassword authenticate on the Sentinel nodes, when they differ from the primary
+	SentinelUsername string
+	SentinelPassword string
+
+	// DB is not supported by Redis Cluster
+	DB int
+
+	// TLS enables TLS when not nil, see WithCAFile
+	TLS *tls.Config
+
+	// PoolSize is the maximum number of connections, the default is sized for high concurrency
+	PoolSize int
+
+	// MinIdleConns keeps connections open to reduce the latency
+	MinIdleConns int
+
+	// MaxRetries is the number of retries of a command that failed with a network error
+	MaxRetries int
+
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// DefaultOptions returns the options used by the repositories when no option is given
+func DefaultOptions(addr string) Options {
+	return Options{
+		Addrs:        []string{addr},
+		PoolSize:     100,
+		MinIdleConns: 10,
+		MaxRetries:   3,
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  3 * time.Second,
+		WriteTimeout: 3 * time.Second,
+	}
+}
+
+// WithURL returns the options with the connection described by the URL, the pool settings are kept.
+//
+//	redis://[user:password@]host:port[/db]
+//	redis+sentinel://[user:password@]host:port[,host:port...]/master[/db]
+//	redis+cluster://[user:password@]host:port[,host:port...]
+//
+// The rediss, rediss+sentinel and rediss+cluster schemes enable TLS. The query accepts
+// ca_file (the CA that signed the server certificate), server_name (the name checked in the certificate),
+// sentinel_username and sentinel_password.
+func (o Options) WithURL(rawURL string) (Options, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return o, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+
+	scheme, secure := u.Scheme, false
+	if rest, ok := strings.CutPrefix(u.Scheme, "rediss"); ok {
+		scheme, secure = "redis"+rest, true
+	}
+
+	o.Addrs = strings.Split(u.Host, ",")
+	if u.Host == "" || strings.Contains(u.Host, ",,") {
+		return o, fmt.Errorf("%w: no host in %q", ErrInvalidURL, u.Redacted())
+	}
+
+	path := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if path[0] == "" {
+		path = nil
+	}
+
+	o.MasterName, o.Cluster = "", false
+	switch scheme {
+	case SchemeSingle:
+	case SchemeSentinel:
+		if len(path) == 0 {
+			return o, fmt.Errorf("%w: the sentinel url needs the master name", ErrInvalidURL)
+		}
+		o.MasterName, path = path[0], path[1:]
+	case SchemeCluster:
+		o.Cluster = true
+	default:
+		return o, fmt.Errorf("%w: unknown scheme %q", ErrInvalidURL, u.Scheme)
+	}
+
+	switch {
+	case len(path) > 1 || (len(path) == 1 && o.Cluster):
+		return o, fmt.Errorf("%w: unexpected path %q", ErrInvalidURL, u.Path)
+	case len(path) == 1:
+		if o.DB, err = strconv.Atoi(path[0]); err != nil {
+			return o, fmt.Errorf("%w: invalid db %q", ErrInvalidURL, path[0])
+		}
+	}
+
+	if u.User != nil {
+		o.Username = u.User.Username()
+		o.Password, _ = u.User.Password()
+	}
+
+	query := u.Query()
+	if v := query.Get("sentinel_username"); v != "" {
+		o.SentinelUsername = v
+	}
+	if v := query.Get("sentinel_password"); v != "" {
+		o.SentinelPassword = v
+	}
+
+	// without a server_name, the host of each node is checked in its certificate
+	if secure {
+		o.TLS = &tls.Config{MinVersion: tls.VersionTLS12, ServerName: query.Get("server_name")}
+	}
+	if caFile := query.Get("ca_file"); caFile != "" {
+		return o.WithCAFile(caFile)
+	}
+	return o, nil
+}
+
+// WithCAFile returns the options trusting the CA certificates of the PEM file, TLS is enabled
+func (o Options) WithCAFile(path string) (Options, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return o, fmt.Errorf("reading the redis CA: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return o, fmt.Errorf("reading the redis CA: no certificate in %s", path)
+	}
+
+	if o.TLS == nil {
+		o.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		o.TLS = o.TLS.Clone()
+	}
+	o.TLS.RootCAs = pool
+	return o, nil
+}
+
+// newClient creates the client of the options: a Cluster, a Sentinel (failover) or a single node client
+func (o Options) newClient() redis.UniversalClient {
+	universal := &redis.UniversalOptions{
+		Addrs:            o.Addrs,
+		MasterName:       o.MasterName,
+		Username:         o.Username,
+		Password:         o.Password,
+		SentinelUsername: o.SentinelUsername,
+		SentinelPassword: o.SentinelPassword,
+		DB:               o.DB,
+		TLSConfig:        o.TLS,
+		PoolSize:         o.PoolSize,
+		MinIdleConns:     o.MinIdleConns,
+		MaxRetries:       o.MaxRetries,
+		DialTimeout:      o.DialTimeout,
+		ReadTimeout:      o.ReadTimeout,
+		WriteTimeout:     o.WriteTimeout,
+	}
+
+	// NewUniversalClient would choose a single node client for a cluster with a single seed node
+	switch {
+	case o.Cluster:
+		return redis.NewClusterClient(universal.Cluster())
+	case o.MasterName != "":
+		return redis.NewFailoverClient(universal.Failover())
+	default:
+		return redis.NewClient(universal.Simple())
+	}
+}
@@ -2,147 +2,165 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"sync"
-	"time"
+	"strconv"
 
-	"github.com/sergiodii/bbb/extension/slice"
-	"github.com/sergiodii/bbb/extension/text"
 	"github.com/sergiodii/bbb/internal/domain/entity"
 	"github.com/sergiodii/bbb/internal/domain/repository"
 
 	"github.com/go-redis/redis/v8"
-	"golang.org/x/sync/errgroup"
 )
 
-type totalResult struct {
-	Total int
-	m     sync.Mutex
-}
-
-func (t *totalResult) Add(v int) {
-	t.m.Lock()
-	defer t.m.Unlock()
-	t.Total += v
+// The keys of a round share the hash tag {roundID}, so in Redis Cluster they are stored in the same slot
+// and a vote is registered in a single transaction
+func totalKey(roundID string) string        { return fmt.Sprintf("round:{%s}:total", roundID) }
+func participantsKey(roundID string) string { return fmt.Sprintf("round:{%s}:participants", roundID) }
+func hoursKey(roundID string) string        { return fmt.Sprintf("round:{%s}:hours", roundID) }
+func metaKey(roundID string) string         { return fmt.Sprintf("round:{%s}:meta", roundID) }
+func votersKey(roundID string) string       { return fmt.Sprintf("round:{%s}:voters", roundID) }
+func participantVotersKey(roundID, participantID string) string {
+	return fmt.Sprintf("round:{%s}:voters:%s", roundID, participantID)
 }
 
+// RedisRoundRepository stores the counters of each round in Redis: the total in a string,
+// and the votes per participant and per hour in hashes. The metadata of a created round is a JSON string.
+// The unique voters of the round and of each participant are HyperLogLogs.
+// It works with a single node, Sentinel or Redis Cluster, see Options.
 type RedisRoundRepository struct {
-	Client *redis.Client
+	Client redis.UniversalClient
 }
 
-// VoteRegister registers a vote in Redis by incrementing the count for the participant and the hour.
-// It uses errgroup to perform both increments concurrently.
-// When occurs an error, a rollback policy should be implemented to ensure data consistency. A method example to decrement the counts could be VoteRegisterRollback(ctx context.Context, vote entity.Vote) error.
-// However, for simplicity, this example does not include rollback logic.
+// VoteRegister registers a vote in Redis by incrementing the total and the counts of the participant and the hour.
+// The voter is added to the HyperLogLogs of the round and of the participant.
+// The commands run in a MULTI/EXEC transaction, so a vote is counted everywhere or nowhere.
 func (r *RedisRoundRepository) VoteRegister(ctx context.Context, vote entity.Vote) error {
-
-	var eg errgroup.Group
-
-	eg.Go(func() error {
-		key := fmt.Sprintf("round:%s:participant:%s", vote.RoundID, vote.ParticipantID)
-		return r.Client.Incr(ctx, key).Err()
-	})
-
-	eg.Go(func() error {
-		hourKey := fmt.Sprintf("round:%s:hour:%d", vote.RoundID, vote.Timestamp/3600)
-		return r.Client.Incr(ctx, hourKey).Err()
-	})
-
-	eg.Go(func() error {
-		totalKey := fmt.Sprintf("round:%s:total", vote.RoundID)
-		return r.Client.Incr(ctx, totalKey).Err()
+	_, err := r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, totalKey(vote.RoundID))
+		pipe.HIncrBy(ctx, participantsKey(vote.RoundID), vote.ParticipantID, 1)
+		pipe.HIncrBy(ctx, hoursKey(vote.RoundID), strconv.FormatInt(vote.Timestamp/3600, 10), 1)
+		if voter := vote.Voter(); voter != "" {
+			pipe.PFAdd(ctx, votersKey(vote.RoundID), voter)
+			pipe.PFAdd(ctx, participantVotersKey(vote.RoundID, vote.ParticipantID), voter)
+		}
+		return nil
 	})
+	return mapError(err)
+}
 
-	if err := eg.Wait(); err != nil {
-
-		// rollback logic should be implemented here to ensure data consistency
-		// for simplicity, it's omitted in this example
+// CreateRound stores the metadata of the round, the round is then known without votes
+func (r *RedisRoundRepository) CreateRound(ctx context.Context, round entity.Round) error {
+	b, err := json.Marshal(round)
+	if err != nil {
 		return err
 	}
-
-	return nil
+	return mapError(r.Client.Set(ctx, metaKey(round.ID), b, 0).Err())
 }
 
+// GetTotalVotes returns the total of the round, zero when the round was created but has no vote
 func (r *RedisRoundRepository) GetTotalVotes(ctx context.Context, roundID string) (int, error) {
-	key := fmt.Sprintf("round:%s:total", roundID)
-	val, err := r.Client.Get(ctx, key).Int()
+	val, err := r.Client.Get(ctx, totalKey(roundID)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, r.known(ctx, roundID)
+	}
 	if err != nil {
-		return 0, err
+		return 0, mapError(err)
 	}
 	return val, nil
 }
 
 func (r *RedisRoundRepository) GetTotalForParticipant(ctx context.Context, roundID string) (map[string]int, error) {
-	pattern := fmt.Sprintf("round:%s:participant:*", roundID)
-	keys, err := r.Client.Keys(ctx, pattern).Result()
+	return r.counters(ctx, roundID, participantsKey(roundID))
+}
+
+// GetTotalForHour returns the votes per hour, the hour is the number of hours since the epoch
+func (r *RedisRoundRepository) GetTotalForHour(ctx context.Context, roundID string) (map[string]int, error) {
+	return r.counters(ctx, roundID, hoursKey(roundID))
+}
+
+// counters reads a hash of counters of the round
+func (r *RedisRoundRepository) counters(ctx context.Context, roundID, key string) (map[string]int, error) {
+	values, err := r.Client.HGetAll(ctx, key).Result()
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
+	}
+	if len(values) == 0 {
+		return map[string]int{}, r.known(ctx, roundID)
+	}
+
+	result := make(map[string]int, len(values))
+	for field, value := range values {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("counter %s of %s: %w", field, key, err)
+		}
+		result[field] = n
 	}
+	return result, nil
+}
+
+// GetUniqueVoters returns the approximate number of unique voters of the round and of each participant voted,
+// counted by PFCOUNT with a standard error of 0.81%
+func (r *RedisRoundRepository) GetUniqueVoters(ctx context.Context, roundID string) (entity.UniqueVoters, error) {
+	voters := entity.UniqueVoters{Participants: map[string]int{}}
 
-	result := &sync.Map{}
-	var eg errgroup.Group
-	for _, chunk := range slice.TransformSliceToMultipleSlices(keys, 10) {
-		eg.Go(func() error {
-			for _, key := range chunk {
-				v, err := r.Client.Get(ctx, key).Int()
-				if err != nil {
-					return err
-				}
-				// key format: round:<id>:participant:<pid>
-				pid := text.GetPartOfString(key, `round:[^:]+:participant:(.+)`)
-				result.Store(pid, v)
-			}
-			return nil
-		})
+	participants, err := r.Client.HKeys(ctx, participantsKey(roundID)).Result()
+	if err != nil {
+		return voters, mapError(err)
+	}
+	if len(participants) == 0 {
+		return voters, r.known(ctx, roundID)
 	}
 
-	if err := eg.Wait(); err != nil {
-		return nil, err
+	var total *redis.IntCmd
+	counts := make([]*redis.IntCmd, len(participants))
+	_, err = r.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		total = pipe.PFCount(ctx, votersKey(roundID))
+		for i, participant := range participants {
+			counts[i] = pipe.PFCount(ctx, participantVotersKey(roundID, participant))
+		}
+		return nil
+	})
+	if err != nil {
+		return voters, mapError(err)
 	}
 
-	return convertSyncMapToMapStringInt(result), nil
+	voters.Total = int(total.Val())
+	for i, participant := range participants {
+		voters.Participants[participant] = int(counts[i].Val())
+	}
+	return voters, nil
 }
 
-func (r *RedisRoundRepository) GetTotalForHour(ctx context.Context, roundID string) (map[string]int, error) {
-	pattern := fmt.Sprintf("round:%s:hour:*", roundID)
-	keys, err := r.Client.Keys(ctx, pattern).Result()
+// known returns ErrRoundNotFound when the round has neither votes nor metadata
+func (r *RedisRoundRepository) known(ctx context.Context, roundID string) error {
+	n, err := r.Client.Exists(ctx, totalKey(roundID), metaKey(roundID)).Result()
 	if err != nil {
-		return nil, err
+		return mapError(err)
 	}
-	result := make(map[string]int)
-	for _, key := range keys {
-		v, err := r.Client.Get(ctx, key).Int()
-		if err == nil {
-			// key format: round:<id>:hour:<hour>
-			hour := text.GetPartOfString(key, `round:[^:]+:hour:(.+)`)
-			result[hour] = v
-		}
+	if n == 0 {
+		return repository.ErrRoundNotFound
 	}
-	return result, nil
+	return nil
 }
 
-func convertSyncMapToMapStringInt(sm *sync.Map) map[string]int {
-	result := make(map[string]int)
-	sm.Range(func(key, value any) bool {
-		k, ok1 := key.(string)
-		v, ok2 := value.(int)
-		if ok1 && ok2 {
-			result[k] = v
-		}
-		return true
-	})
-	return result
+// Ping checks the connection with the Redis server.
+func (r *RedisRoundRepository) Ping(ctx context.Context) error {
+	return mapError(r.Client.Ping(ctx).Err())
 }
 
+// Close closes the connections with the Redis server.
+func (r *RedisRoundRepository) Close() error {
+	return r.Client.Close()
+}
+
+// NewRedisRoundRepository creates the repository connected to the Redis server with the default options
 func NewRedisRoundRepository(addr string) repository.RoundRepository {
-	client := redis.NewClient(&redis.Options{
-		Addr:         addr,
-		PoolSize:     100,             // Aumentar pool de conexões para alta concorrência
-		MinIdleConns: 10,              // Manter conexões ativas para reduzir latência
-		MaxRetries:   3,               // Retry em caso de timeout
-		DialTimeout:  5 * time.Second, // Timeout para conectar
-		ReadTimeout:  3 * time.Second, // Timeout para leitura
-		WriteTimeout: 3 * time.Second, // Timeout para escrita
-	})
-	return &RedisRoundRepository{Client: client}
+	return NewRedisRoundRepositoryWithOptions(DefaultOptions(addr))
+}
+
+// NewRedisRoundRepositoryWithOptions creates the repository connected to the Redis server configured by the options
+func NewRedisRoundRepositoryWithOptions(o Options) repository.RoundRepository {
+	return &RedisRoundRepository{Client: o.newClient()}
 }
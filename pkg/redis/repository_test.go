@@ -3,8 +3,11 @@ package redis
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/errs"
+	"github.com/sergiodii/bbb/internal/domain/repository"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/assert"
@@ -34,3 +37,209 @@ func TestVoteRegister_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, map[string]int{"participant1": 1}, m)
 }
+
+func TestRoundWithoutVotes(t *testing.T) {
+	s := miniredis.RunT(t)
+	repo := NewRedisRoundRepository(s.Addr())
+
+	// a round that was never created nor voted is not found
+	total, err := repo.GetTotalVotes(context.Background(), "unknown")
+	assert.ErrorIs(t, err, repository.ErrRoundNotFound)
+	assert.Equal(t, 0, total)
+
+	_, err = repo.GetTotalForParticipant(context.Background(), "unknown")
+	assert.ErrorIs(t, err, repository.ErrRoundNotFound)
+
+	// a created round without votes is known
+	err = repo.(repository.RoundRegistry).CreateRound(context.Background(), entity.Round{ID: "round1", Nome: "Paredão 1"})
+	assert.NoError(t, err)
+
+	total, err = repo.GetTotalVotes(context.Background(), "round1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	m, err := repo.GetTotalForHour(context.Background(), "round1")
+	assert.NoError(t, err)
+	assert.Empty(t, m)
+	assert.Contains(t, s.Keys(), "round:{round1}:meta")
+}
+
+func TestErrorsOfTheDomain(t *testing.T) {
+	s := miniredis.RunT(t)
+	repo := NewRedisRoundRepository(s.Addr())
+	s.Close()
+
+	_, err := repo.GetTotalVotes(context.Background(), "round1")
+	assert.ErrorIs(t, err, errs.ErrUnavailable)
+	assert.Equal(t, "the vote store is unavailable", errs.Message(err))
+
+	err = repo.VoteRegister(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "participant1"})
+	assert.ErrorIs(t, err, errs.ErrUnavailable)
+
+	_, err = repo.GetTotalForHour(context.Background(), "round1")
+	assert.ErrorIs(t, err, errs.ErrUnavailable)
+}
+
+func TestPing(t *testing.T) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to start miniredis: %v", err)
+	}
+
+	repo := NewRedisRoundRepository(s.Addr()).(*RedisRoundRepository)
+	assert.NoError(t, repo.Ping(context.Background()))
+
+	s.Close()
+	assert.Error(t, repo.Ping(context.Background()))
+}
+
+func TestClose(t *testing.T) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to start miniredis: %v", err)
+	}
+	defer s.Close()
+
+	repo := NewRedisRoundRepository(s.Addr()).(*RedisRoundRepository)
+	assert.NoError(t, repo.Close())
+	assert.Error(t, repo.Ping(context.Background()))
+}
+
+func TestDeadLetter(t *testing.T) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to start miniredis: %v", err)
+	}
+	defer s.Close()
+
+	repo := NewRedisDeadLetterRepository(s.Addr())
+
+	first := entity.DeadLetter{Vote: entity.Vote{RoundID: "round1", ParticipantID: "participant1"}, RepositoryIndex: 1, Error: "timeout"}
+	second := entity.DeadLetter{Vote: entity.Vote{RoundID: "round1", ParticipantID: "participant2"}, RepositoryIndex: 1, Error: "timeout"}
+	assert.NoError(t, repo.Push(context.Background(), first))
+	assert.NoError(t, repo.Push(context.Background(), second))
+
+	letter, ok, err := repo.Pop(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, first, letter)
+
+	letter, ok, err = repo.Pop(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, second, letter)
+
+	_, ok, err = repo.Pop(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestIdempotency(t *testing.T) {
+	s := miniredis.RunT(t)
+	repo := NewRedisIdempotencyRepositoryWithOptions(DefaultOptions(s.Addr()))
+	ctx := context.Background()
+
+	// the first request reserves the key, the others wait while it is pending
+	state, err := repo.Reserve(ctx, "round1:key1", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.IdempotencyReserved, state)
+
+	state, err = repo.Reserve(ctx, "round1:key1", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.IdempotencyPending, state)
+
+	// a completed key is done until it expires
+	assert.NoError(t, repo.Complete(ctx, "round1:key1", time.Minute))
+	state, err = repo.Reserve(ctx, "round1:key1", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.IdempotencyDone, state)
+
+	s.FastForward(time.Minute)
+	state, err = repo.Reserve(ctx, "round1:key1", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.IdempotencyReserved, state)
+
+	// a released key is reserved again
+	assert.NoError(t, repo.Release(ctx, "round1:key1"))
+	state, err = repo.Reserve(ctx, "round1:key1", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.IdempotencyReserved, state)
+	assert.Contains(t, s.Keys(), "idempotency:round1:key1")
+}
+
+func TestKeysShareTheRoundHashTag(t *testing.T) {
+	s := miniredis.RunT(t)
+	repo := NewRedisRoundRepository(s.Addr())
+
+	err := repo.VoteRegister(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "participant1", Timestamp: 3600, VoterID: "v1"})
+	assert.NoError(t, err)
+
+	// every key of the round has the hash tag {round1}, so they are in the same slot of a cluster
+	assert.Equal(t, []string{
+		"round:{round1}:hours", "round:{round1}:participants", "round:{round1}:total",
+		"round:{round1}:voters", "round:{round1}:voters:participant1",
+	}, s.Keys())
+	assert.Equal(t, "1", s.HGet("round:{round1}:hours", "1"))
+}
+
+func TestUniqueVoters(t *testing.T) {
+	s := miniredis.RunT(t)
+	repo := NewRedisRoundRepository(s.Addr())
+	for _, vote := range []entity.Vote{
+		{RoundID: "round1", ParticipantID: "alice", VoterID: "v1"},
+		{RoundID: "round1", ParticipantID: "alice", VoterID: "v1"},
+		{RoundID: "round1", ParticipantID: "bob", VoterID: "v1"},
+		{RoundID: "round1", ParticipantID: "bob", IP: "10.0.0.1"},
+		{RoundID: "round1", ParticipantID: "bob"},
+	} {
+		assert.NoError(t, repo.VoteRegister(context.Background(), vote))
+	}
+	counter := repo.(repository.VoterCounter)
+
+	voters, err := counter.GetUniqueVoters(context.Background(), "round1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.UniqueVoters{Total: 2, Participants: map[string]int{"alice": 1, "bob": 2}}, voters)
+
+	// a created round without votes has no voters, an unknown round is not found
+	assert.NoError(t, repo.(repository.RoundRegistry).CreateRound(context.Background(), entity.Round{ID: "round2"}))
+	voters, err = counter.GetUniqueVoters(context.Background(), "round2")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.UniqueVoters{Participants: map[string]int{}}, voters)
+
+	_, err = counter.GetUniqueVoters(context.Background(), "unknown")
+	assert.ErrorIs(t, err, repository.ErrRoundNotFound)
+}
+
+func TestMigrateLegacyKeys(t *testing.T) {
+	t.Run("Should move the legacy counters to the keys of the round", func(t *testing.T) {
+		// Arrange
+		s := miniredis.RunT(t)
+		s.Set("round:round1:total", "3")
+		s.Set("round:round1:participant:alice", "2")
+		s.Set("round:round1:participant:bob", "1")
+		s.Set("round:round1:hour:451411", "3")
+		s.Set("other:key", "1")
+		repo := NewRedisRoundRepository(s.Addr()).(*RedisRoundRepository)
+		ctx := context.Background()
+
+		// Act
+		moved, err := repo.MigrateLegacyKeys(ctx)
+		again, againErr := repo.MigrateLegacyKeys(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, againErr)
+		assert.Equal(t, 4, moved)
+		assert.Zero(t, again)
+
+		total, _ := repo.GetTotalVotes(ctx, "round1")
+		participants, _ := repo.GetTotalForParticipant(ctx, "round1")
+		hours, _ := repo.GetTotalForHour(ctx, "round1")
+		assert.Equal(t, 3, total)
+		assert.Equal(t, map[string]int{"alice": 2, "bob": 1}, participants)
+		assert.Equal(t, map[string]int{"451411": 3}, hours)
+		assert.False(t, s.Exists("round:round1:total"))
+		assert.False(t, s.Exists("round:round1:participant:alice"))
+		assert.True(t, s.Exists("other:key"))
+	})
+}
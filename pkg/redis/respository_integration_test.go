@@ -21,7 +21,7 @@ func TestRedisEstress(t *testing.T) {
 	}
 	defer s.Close()
 
-	repo := redisPkg.NewRedisRoundRepository("localhost:6379")
+	repo := redisPkg.NewRedisRoundRepository(s.Addr())
 
 	entities := slice.MultipliesSlice([]entity.Vote{
 		{RoundID: "round1", ParticipantID: "participant1", Timestamp: 1625079600},
@@ -59,7 +59,7 @@ func TestRedisEstress(t *testing.T) {
 	if end.Sub(start) > time.Second*1 {
 		t.Fatalf("Vote registration took too long: %v", end.Sub(start))
 	}
-	t.Logf("Time taken to register 1000 votes: %v", end.Sub(start)*time.Second)
+	t.Logf("Time taken to register 1000 votes: %v", end.Sub(start))
 
 	if counter.Count != 1000 {
 		t.Fatalf("Expected %d votes to be registered, got %d", 1000, counter.Count)
@@ -69,34 +69,34 @@ func TestRedisEstress(t *testing.T) {
 		t.Fatalf("Failed to register votes: %v", err)
 	}
 
-	// total, err := repo.GetTotalVotes(context.Background(), "round1")
-	// if err != nil {
-	// 	t.Fatalf("Failed to get total votes: %v", err)
-	// }
-	// if total != 6 {
-	// 	t.Fatalf("Expected total votes to be 6, got %d", total)
-	// }
-
-	// m, err := repo.GetTotalForParticipant(context.Background(), "round1")
-	// if err != nil {
-	// 	t.Fatalf("Failed to get total for participant: %v", err)
-	// }
-	// expected := map[string]int{"participant1": 3, "participant2": 2, "participant3": 1}
-	// for k, v := range expected {
-	// 	if m[k] != v {
-	// 		t.Fatalf("Expected participant %s to have %d votes, got %d", k, v, m[k])
-	// 	}
-	// }
-
-	// h, err := repo.GetTotalForHour(context.Background(), "round1")
-	// if err != nil {
-	// 	t.Fatalf("Failed to get total for hour: %v", err)
-	// }
-	// expectedHours := map[string]int{"14": 2, "15": 2, "16": 1, "17": 1}
-	// for k, v := range expectedHours {
-	// 	if h[k] != v {
-	// 		t.Fatalf("Expected hour %s to have %d votes, got %d", k, v, h[k])
-	// 	}
-	// }
+	total, err := repo.GetTotalVotes(context.Background(), "round1")
+	if err != nil {
+		t.Fatalf("Failed to get total votes: %v", err)
+	}
+	if total != 1000 {
+		t.Fatalf("Expected total votes to be 1000, got %d", total)
+	}
+
+	m, err := repo.GetTotalForParticipant(context.Background(), "round1")
+	if err != nil {
+		t.Fatalf("Failed to get total for participant: %v", err)
+	}
+	expected := map[string]int{"participant1": 500, "participant2": 250, "participant3": 250}
+	for k, v := range expected {
+		if m[k] != v {
+			t.Fatalf("Expected participant %s to have %d votes, got %d", k, v, m[k])
+		}
+	}
 
+	// the hours are counted since the epoch
+	h, err := repo.GetTotalForHour(context.Background(), "round1")
+	if err != nil {
+		t.Fatalf("Failed to get total for hour: %v", err)
+	}
+	expectedHours := map[string]int{"451411": 500, "451412": 250, "451413": 250}
+	for k, v := range expectedHours {
+		if h[k] != v {
+			t.Fatalf("Expected hour %s to have %d votes, got %d", k, v, h[k])
+		}
+	}
 }
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type pipeObserver struct{}
+
+// ObserveTask creates a span around a pipe task.
+// Fire-and-forget tasks start a new trace linked to the request span,
+// so they are not reported as children of a request that already finished.
+func (o *pipeObserver) ObserveTask(ctx context.Context, info pipe.TaskInfo) (context.Context, func(error)) {
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			attribute.String("pipe.execution_type", info.ExecutionType.String()),
+			attribute.Int("pipe.task", info.Index),
+		),
+	}
+
+	if origin, ok := pipe.Origin(ctx); ok {
+		opts = append(opts, trace.WithNewRoot(), trace.WithLinks(trace.LinkFromContext(origin)))
+	}
+
+	ctx, span := tracer().Start(ctx, "pipe.task", opts...)
+	return ctx, func(err error) {
+		if err != nil && !errors.Is(err, pipe.ONF) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// NewPipeObserver creates a pipe.Observer that creates a span for every task.
+// Use it with pipe.SetDefaultObserver or pipe.Instrument.
+func NewPipeObserver() pipe.Observer {
+	return &pipeObserver{}
+}
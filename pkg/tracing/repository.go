@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/internal/domain/repository"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedRepository struct {
+	name string
+	repo repository.RoundRepository
+}
+
+func (r *tracedRepository) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("repository.name", r.name))
+	return tracer().Start(ctx, r.name+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// end ends the span, an unknown round is an answer of the repository and not an error of the span
+func end(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, repository.ErrRoundNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (r *tracedRepository) VoteRegister(ctx context.Context, vote entity.Vote) (err error) {
+	ctx, span := r.start(ctx, "VoteRegister", RoundIDKey.String(vote.RoundID), ParticipantIDKey.String(vote.ParticipantID))
+	defer func() { end(span, err) }()
+	return r.repo.VoteRegister(ctx, vote)
+}
+
+func (r *tracedRepository) GetTotalVotes(ctx context.Context, roundID string) (total int, err error) {
+	ctx, span := r.start(ctx, "GetTotalVotes", RoundIDKey.String(roundID))
+	defer func() { end(span, err) }()
+	return r.repo.GetTotalVotes(ctx, roundID)
+}
+
+func (r *tracedRepository) GetTotalForParticipant(ctx context.Context, roundID string) (m map[string]int, err error) {
+	ctx, span := r.start(ctx, "GetTotalForParticipant", RoundIDKey.String(roundID))
+	defer func() { end(span, err) }()
+	return r.repo.GetTotalForParticipant(ctx, roundID)
+}
+
+func (r *tracedRepository) GetTotalForHour(ctx context.Context, roundID string) (m map[string]int, err error) {
+	ctx, span := r.start(ctx, "GetTotalForHour", RoundIDKey.String(roundID))
+	defer func() { end(span, err) }()
+	return r.repo.GetTotalForHour(ctx, roundID)
+}
+
+// CreateRound forwards the round to the decorated repository, when it stores the rounds
+func (r *tracedRepository) CreateRound(ctx context.Context, round entity.Round) (err error) {
+	registry, ok := r.repo.(repository.RoundRegistry)
+	if !ok {
+		return nil
+	}
+	ctx, span := r.start(ctx, "CreateRound", RoundIDKey.String(round.ID))
+	defer func() { end(span, err) }()
+	return registry.CreateRound(ctx, round)
+}
+
+// GetUniqueVoters forwards the query to the decorated repository, when it counts the voters
+func (r *tracedRepository) GetUniqueVoters(ctx context.Context, roundID string) (voters entity.UniqueVoters, err error) {
+	counter, ok := r.repo.(repository.VoterCounter)
+	if !ok {
+		return voters, errors.ErrUnsupported
+	}
+	ctx, span := r.start(ctx, "GetUniqueVoters", RoundIDKey.String(roundID))
+	defer func() { end(span, err) }()
+	return counter.GetUniqueVoters(ctx, roundID)
+}
+
+// Ping forwards the health check to the decorated repository, when it supports it.
+// Probes are called very often, so no span is created for them.
+func (r *tracedRepository) Ping(ctx context.Context) error {
+	if hc, ok := r.repo.(repository.HealthChecker); ok {
+		return hc.Ping(ctx)
+	}
+	return nil
+}
+
+func (r *tracedRepository) Unwrap() repository.RoundRepository {
+	return r.repo
+}
+
+// TraceRepository decorates a repository to create a span for each of its operations.
+// The name is used as the span prefix and as the repository.name attribute (e.g. redis, localsql).
+func TraceRepository(name string, repo repository.RoundRepository) repository.RoundRepository {
+	return &tracedRepository{
+		name: name,
+		repo: repo,
+	}
+}
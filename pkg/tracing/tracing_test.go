@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sergiodii/bbb/extension/pipe"
+	"github.com/sergiodii/bbb/internal/domain/entity"
+	"github.com/sergiodii/bbb/pkg/localsql"
+	"github.com/sergiodii/bbb/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func TestTracing(t *testing.T) {
+	t.Run("Should create a span for the route with the round attribute", func(t *testing.T) {
+		// Arrange
+		recorder := setupRecorder(t)
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.Use(GinMiddleware())
+		r.GET("/query/:round_id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		// Act
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/query/round1", nil))
+
+		// Assert
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET /query/:round_id", spans[0].Name())
+		assert.Contains(t, spans[0].Attributes(), RoundIDKey.String("round1"))
+	})
+
+	t.Run("Should create repository spans as children of the pipe task", func(t *testing.T) {
+		// Arrange
+		recorder := setupRecorder(t)
+		repo := TraceRepository("localsql", localsql.NewLocalSqlRoundRepository(logger.Discard()))
+
+		p := pipe.Instrument(pipe.NewSequentiallyPipe[entity.Vote](), pipe.SEQUENTIAL, NewPipeObserver())
+		p.Enqueue(func(ctx context.Context, v entity.Vote) (entity.Vote, error) {
+			return v, repo.VoteRegister(ctx, v)
+		})
+
+		// Act
+		_, err := p.Execute(context.Background(), entity.Vote{RoundID: "round1", ParticipantID: "participant1"})
+
+		// Assert
+		assert.NoError(t, err)
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		assert.Equal(t, "localsql.VoteRegister", spans[0].Name())
+		assert.Equal(t, "pipe.task", spans[1].Name())
+		assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+		assert.Contains(t, spans[0].Attributes(), ParticipantIDKey.String("participant1"))
+	})
+
+	t.Run("Should link fire-and-forget tasks to the request span", func(t *testing.T) {
+		// Arrange
+		recorder := setupRecorder(t)
+		done := make(chan struct{})
+
+		p := pipe.Instrument(pipe.NewSequentiallyBlockingFirstResultPipe[int](), pipe.SEQUENTIAL_BLOCKING_ONLY_FIRST, NewPipeObserver())
+		p.Enqueue(func(ctx context.Context, i int) (int, error) { return i, nil })
+		p.Enqueue(func(ctx context.Context, i int) (int, error) {
+			defer close(done)
+			return i, nil
+		})
+
+		ctx, request := otel.Tracer("test").Start(context.Background(), "request")
+
+		// Act
+		_, err := p.Execute(ctx, 1)
+		request.End()
+		<-done
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool { return len(recorder.Ended()) == 3 }, time.Second, 10*time.Millisecond)
+
+		for _, span := range recorder.Ended() {
+			if span.Name() != "pipe.task" || len(span.Links()) == 0 {
+				continue
+			}
+			assert.NotEqual(t, request.SpanContext().TraceID(), span.SpanContext().TraceID())
+			assert.Equal(t, request.SpanContext().SpanID(), span.Links()[0].SpanContext.SpanID())
+			return
+		}
+		t.Fatal("expected a linked span for the fire-and-forget task")
+	})
+}